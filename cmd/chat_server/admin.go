@@ -0,0 +1,157 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Admin - служебные маршруты чат-сервера: /healthz, /metrics и JSON API администратора /admin/.
+// API администратора доступно только с токеном из флага -admin-token в заголовке Authorization: Bearer.
+type Admin struct {
+	chat    *ChatServer
+	direct  *DirectServer
+	metrics *Metrics
+	token   string // токен администратора, пусто - API администратора отключено
+	started time.Time
+}
+
+// kickRequest - тело POST /admin/kick
+type kickRequest struct {
+	UserID int    `json:"user_id"`
+	RoomID *int   `json:"room_id"` // без поля - все комнаты и личные сообщения
+	Reason string `json:"reason"`
+}
+
+// broadcastRequest - тело POST /admin/broadcast
+type broadcastRequest struct {
+	Message string `json:"message"`
+	RoomID  *int   `json:"room_id"` // без поля - все комнаты
+}
+
+// NewAdmin - служебные маршруты серверов chat и direct, token - токен API администратора
+func NewAdmin(chat *ChatServer, direct *DirectServer, metrics *Metrics, token string) *Admin {
+	return &Admin{chat: chat, direct: direct, metrics: metrics, token: token, started: time.Now()}
+}
+
+// register - служебные маршруты в mux, nil Admin ничего не регистрирует
+func (a *Admin) register(mux *http.ServeMux) {
+	if a == nil {
+		return
+	}
+	mux.HandleFunc("GET /healthz", a.handleHealth)
+	mux.HandleFunc("GET /metrics", a.handleMetrics)
+	if a.token == "" {
+		return
+	}
+	mux.HandleFunc("GET /admin/rooms", a.authorized(a.handleRooms))
+	mux.HandleFunc("POST /admin/kick", a.authorized(a.handleKick))
+	mux.HandleFunc("POST /admin/broadcast", a.authorized(a.handleBroadcast))
+}
+
+// handleHealth - 200, пока сервер принимает подключения, 503 во время остановки
+func (a *Admin) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if a.chat.Closing() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "shutting_down"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ok", "uptime_seconds": int(time.Since(a.started).Seconds())})
+}
+
+// handleMetrics - метрики в текстовом формате Prometheus
+func (a *Admin) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	var b strings.Builder
+	metric := func(name, kind, help string, values ...string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for _, value := range values {
+			fmt.Fprintf(&b, "%s%s\n", name, value)
+		}
+	}
+	metric("chat_rooms", "gauge", "Комнаты гильдий и лобби с подключениями",
+		fmt.Sprintf(" %d", len(a.chat.Rooms())))
+	metric("chat_clients", "gauge", "Открытые подключения",
+		fmt.Sprintf(`{chat="rooms"} %d`, a.chat.Connections()),
+		fmt.Sprintf(`{chat="direct"} %d`, a.direct.Connections()))
+	metric("chat_messages_total", "counter", "Сообщения, шепот и личные сообщения с запуска",
+		fmt.Sprintf(" %d", a.metrics.Total()))
+	metric("chat_messages_per_second", "gauge", fmt.Sprintf("Сообщений в секунду за последние %d секунд", metricsWindow),
+		fmt.Sprintf(" %g", a.metrics.Rate(now)))
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
+
+// handleRooms - комнаты с подключенными пользователями
+func (a *Admin) handleRooms(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"rooms": a.chat.Rooms(), "direct_clients": a.direct.Connections()})
+}
+
+// handleKick - отключение пользователя от комнаты или от всех чатов
+func (a *Admin) handleKick(w http.ResponseWriter, r *http.Request) {
+	var req kickRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "нужен user_id"})
+		return
+	}
+	reason := "вы отключены администратором"
+	if req.Reason != "" {
+		reason += ": " + req.Reason
+	}
+
+	kicked := 0
+	if req.RoomID != nil {
+		kicked = a.chat.Kick(*req.RoomID, req.UserID, reason)
+	} else {
+		for _, room := range a.chat.Rooms() {
+			kicked += a.chat.Kick(room.RoomID, req.UserID, reason)
+		}
+		kicked += a.direct.Kick(req.UserID, reason)
+	}
+	log.Printf("Admin kicked user %d: %d connections", req.UserID, kicked)
+	writeJSON(w, http.StatusOK, map[string]int{"kicked": kicked})
+}
+
+// handleBroadcast - служебное сообщение в комнату или во все комнаты
+func (a *Admin) handleBroadcast(w http.ResponseWriter, r *http.Request) {
+	var req broadcastRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Message) == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "нужен message"})
+		return
+	}
+
+	delivered := 0
+	if req.RoomID != nil {
+		delivered = a.chat.Broadcast(*req.RoomID, req.Message)
+	} else {
+		for _, room := range a.chat.Rooms() {
+			delivered += a.chat.Broadcast(room.RoomID, req.Message)
+		}
+	}
+	log.Printf("Admin broadcast to %d connections: %s", delivered, req.Message)
+	writeJSON(w, http.StatusOK, map[string]int{"delivered": delivered})
+}
+
+// authorized - обработчик только для запросов с токеном администратора
+func (a *Admin) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "нужен токен администратора"})
+			return
+		}
+		next(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Printf("Ошибка отправки ответа: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+)
+
+// adminRequest - запрос к API администратора, ответ декодируется в value
+func adminRequest(t *testing.T, server *httptest.Server, method, path, token, body string, value any) int {
+	t.Helper()
+	req, _ := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("ошибка запроса %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+	if value != nil {
+		json.NewDecoder(resp.Body).Decode(value)
+	}
+	return resp.StatusCode
+}
+
+func TestChatServerAdmin(t *testing.T) {
+	auth, store, metrics := NewInsecureAuthenticator(), NewMemoryStore(), NewMetrics()
+	opts := ChatOptions{History: 10, Metrics: metrics}
+	direct, _ := NewDirectServer(auth, store, opts)
+	chat := NewChatServer(auth, store, opts)
+	server := httptest.NewServer(newMux(chat, direct, NewAdmin(chat, direct, metrics, "secret")))
+	defer server.Close()
+
+	var history guild.ChatHistory
+	admiral, _ := dial(t, server, "/ws/guild/1/1", accessToken(t, "1", "admiral"))
+	read(t, admiral, &history)
+	bosun, _ := dial(t, server, "/ws/lobby/2", accessToken(t, "2", "bosun"))
+	read(t, bosun, &history)
+	admiral.WriteJSON(guild.ChatMessage{Msg: "Всем привет"})
+	next(t, admiral)
+
+	var health map[string]any
+	if status := adminRequest(t, server, "GET", "/healthz", "", "", &health); status != http.StatusOK || health["status"] != "ok" {
+		t.Errorf("healthz: %d %v", status, health)
+	}
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	for _, line := range []string{"chat_rooms 2", `chat_clients{chat="rooms"} 2`, `chat_clients{chat="direct"} 0`, "chat_messages_total 1"} {
+		if !strings.Contains(string(data), line+"\n") {
+			t.Errorf("в метриках нет %q:\n%s", line, data)
+		}
+	}
+
+	if status := adminRequest(t, server, "GET", "/admin/rooms", "wrong", "", nil); status != http.StatusUnauthorized {
+		t.Errorf("API администратора с чужим токеном: статус %d", status)
+	}
+	var rooms struct {
+		Rooms []RoomInfo `json:"rooms"`
+	}
+	adminRequest(t, server, "GET", "/admin/rooms", "secret", "", &rooms)
+	if len(rooms.Rooms) != 2 || rooms.Rooms[0].RoomID != lobbyID || rooms.Rooms[1].Users[0].Username != "admiral" {
+		t.Errorf("комнаты: %+v", rooms.Rooms)
+	}
+
+	var delivered map[string]int
+	adminRequest(t, server, "POST", "/admin/broadcast", "secret", `{"message": "Перезапуск через 5 минут"}`, &delivered)
+	if delivered["delivered"] != 2 {
+		t.Errorf("рассылка: %v", delivered)
+	}
+	if p := next(t, bosun); p.Type != guild.TypeNotice || p.Message != "Перезапуск через 5 минут" {
+		t.Errorf("служебное сообщение: %+v", p)
+	}
+	next(t, admiral)
+
+	var kicked map[string]int
+	adminRequest(t, server, "POST", "/admin/kick", "secret", `{"user_id": 2, "room_id": 0, "reason": "флуд"}`, &kicked)
+	if kicked["kicked"] != 1 {
+		t.Errorf("отключение: %v", kicked)
+	}
+	if p := next(t, bosun); p.Type != guild.TypeError || p.Message != "вы отключены администратором: флуд" {
+		t.Errorf("причина отключения: %+v", p)
+	}
+}
+
+func TestChatServerShutdown(t *testing.T) {
+	auth, store := NewInsecureAuthenticator(), NewMemoryStore()
+	direct, _ := NewDirectServer(auth, store, ChatOptions{})
+	chat := NewChatServer(auth, store, ChatOptions{History: 10})
+	server := httptest.NewServer(newMux(chat, direct, NewAdmin(chat, direct, nil, "")))
+	defer server.Close()
+
+	conn, _ := dial(t, server, "/ws/guild/1/1", accessToken(t, "1", "admiral"))
+	var history guild.ChatHistory
+	read(t, conn, &history)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := chat.Shutdown(ctx); err != nil {
+		t.Fatalf("ошибка остановки: %v", err)
+	}
+
+	// клиент получает кадр закрытия, а не обрыв соединения
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err := conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseGoingAway {
+		t.Errorf("закрытие при остановке: %v", err)
+	}
+	if _, status := dial(t, server, "/ws/guild/1/1", accessToken(t, "1", "admiral")); status != http.StatusServiceUnavailable {
+		t.Errorf("подключение во время остановки: статус %d", status)
+	}
+	if status := adminRequest(t, server, "GET", "/healthz", "", "", nil); status != http.StatusServiceUnavailable {
+		t.Errorf("healthz во время остановки: статус %d", status)
+	}
+	if status := adminRequest(t, server, "GET", "/admin/rooms", "", "", nil); status != http.StatusNotFound {
+		t.Errorf("API администратора без токена в настройках: статус %d", status)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"lesta-start-battleship/cli/internal/api/auth"
+	"lesta-start-battleship/cli/storage/token"
+)
+
+// errUnauthorized - подключение без действующего access token
+var errUnauthorized = errors.New("требуется access token")
+
+// Identity - пользователь подключения, определенный по access token
+type Identity struct {
+	UserID   int
+	Username string
+	Header   string // заголовок Authorization подключения для запросов к сервисам от имени пользователя
+}
+
+// errNoAuthService - сервис авторизации не задан, а чтение claims без проверки не включено
+var errNoAuthService = errors.New("сервис авторизации не задан")
+
+// Authenticator - определение пользователя по заголовку Authorization.
+// Токен проверяется запросом профиля в сервисе авторизации, имя берется из профиля.
+// Только в небезопасном режиме claims читаются без проверки подписи, имя - из claim username или user<id>.
+type Authenticator struct {
+	authURL  string // базовый адрес сервиса авторизации
+	insecure bool   // claims без проверки подписи, выбрать можно любого пользователя
+	client   *http.Client
+}
+
+// NewAuthenticator - проверка токенов через сервис авторизации authURL.
+// С пустым authURL все подключения отклоняются.
+func NewAuthenticator(authURL string) *Authenticator {
+	if authURL != "" && !strings.HasSuffix(authURL, "/") {
+		authURL += "/"
+	}
+	return &Authenticator{authURL: authURL, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// NewInsecureAuthenticator - пользователь из claims токена без проверки подписи, только для локальной отладки
+func NewInsecureAuthenticator() *Authenticator {
+	return &Authenticator{insecure: true, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Identify - пользователь по заголовку Authorization запроса
+func (a *Authenticator) Identify(ctx context.Context, r *http.Request) (Identity, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return Identity{}, errUnauthorized
+	}
+	claims, err := token.ParseClaims(header)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", errUnauthorized, err)
+	}
+	if claims.UserID <= 0 {
+		return Identity{}, fmt.Errorf("%w: в токене нет user_id", errUnauthorized)
+	}
+	if !claims.ExpiresAt.IsZero() && claims.ExpiresAt.Before(time.Now()) {
+		return Identity{}, fmt.Errorf("%w: срок действия токена истек", errUnauthorized)
+	}
+
+	switch {
+	case a.authURL != "":
+		return a.profile(ctx, header, claims.UserID)
+	case a.insecure:
+		return Identity{UserID: claims.UserID, Username: claimUsername(header, claims.UserID), Header: header}, nil
+	}
+	return Identity{}, fmt.Errorf("%w: %w", errUnauthorized, errNoAuthService)
+}
+
+// profile - проверка токена запросом профиля пользователя в сервисе авторизации
+func (a *Authenticator) profile(ctx context.Context, header string, userID int) (Identity, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.authURL+fmt.Sprintf(auth.GetProfilePath, userID), nil)
+	if err != nil {
+		return Identity{}, err
+	}
+	bearer := header
+	if !strings.HasPrefix(bearer, "Bearer ") {
+		bearer = "Bearer " + bearer
+	}
+	req.Header.Set("Authorization", bearer)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return Identity{}, fmt.Errorf("ошибка запроса профиля: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return Identity{}, fmt.Errorf("%w: сервис авторизации отклонил токен", errUnauthorized)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Identity{}, fmt.Errorf("ошибка запроса профиля: статус %d", resp.StatusCode)
+	}
+
+	var profile auth.ProfileResponse
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return Identity{}, fmt.Errorf("ошибка декодирования профиля: %w", err)
+	}
+	if profile.ID != userID {
+		return Identity{}, fmt.Errorf("%w: профиль принадлежит другому пользователю", errUnauthorized)
+	}
+	return Identity{UserID: profile.ID, Username: profile.Username, Header: header}, nil
+}
+
+// claimUsername - имя из claim username токена без проверки подписи
+func claimUsername(header string, userID int) string {
+	claims := jwt.MapClaims{}
+	raw := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	if _, _, err := jwt.NewParser().ParseUnverified(raw, claims); err == nil {
+		if name, ok := claims["username"].(string); ok && name != "" {
+			return name
+		}
+	}
+	return fmt.Sprintf("user%d", userID)
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/direct"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+)
+
+// Корзины файла BoltDB
+var (
+	bucketRooms     = []byte("rooms")     // корзина на комнату, ключ сообщения - его ID
+	bucketDirect    = []byte("direct")    // личные сообщения, ключ - ID
+	bucketSanctions = []byte("sanctions") // ключ - room/user/kind
+	bucketMeta      = []byte("meta")      // последовательность ID сообщений
+)
+
+// boltStore - хранилище в файле BoltDB, переписка и санкции сохраняются между перезапусками.
+// ID сообщений одной длины, поэтому порядок ключей совпадает с порядком отправки.
+type boltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore - хранилище в файле path, файл создается при первом запуске
+func OpenBoltStore(path string) (Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия базы чата %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketRooms, bucketDirect, bucketSanctions, bucketMeta} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ошибка создания базы чата: %w", err)
+	}
+	return &boltStore{db: db}, nil
+}
+
+func (s *boltStore) NextID() (uint64, error) {
+	var id uint64
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		id, err = tx.Bucket(bucketMeta).NextSequence()
+		return err
+	})
+	return id, err
+}
+
+func (s *boltStore) Append(roomID int, msg guild.ChatHistoryMessage, keep int) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		room, err := tx.Bucket(bucketRooms).CreateBucketIfNotExists(roomKey(roomID))
+		if err != nil {
+			return err
+		}
+		if err := room.Put([]byte(msg.Id), data); err != nil {
+			return err
+		}
+		// самые старые сообщения сверх keep удаляются, ключи собираются до удаления:
+		// курсор после Delete может пропустить следующий ключ
+		var keys [][]byte
+		c := room.Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			keys = append(keys, append([]byte(nil), k...))
+		}
+		for _, k := range keys[:max(len(keys)-keep, 0)] {
+			if err := room.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *boltStore) Latest(roomID, limit int) ([]guild.ChatHistoryMessage, error) {
+	messages := []guild.ChatHistoryMessage{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		room := tx.Bucket(bucketRooms).Bucket(roomKey(roomID))
+		if room == nil {
+			return nil
+		}
+		c := room.Cursor()
+		k, v := c.Last()
+		return collect(&messages, c, k, v, limit)
+	})
+	return messages, err
+}
+
+func (s *boltStore) Before(roomID int, id string, limit int) ([]guild.ChatHistoryMessage, error) {
+	messages := []guild.ChatHistoryMessage{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		room := tx.Bucket(bucketRooms).Bucket(roomKey(roomID))
+		if room == nil {
+			return nil
+		}
+		c := room.Cursor()
+		if k, _ := c.Seek([]byte(id)); k == nil || string(k) != id {
+			return nil
+		}
+		k, v := c.Prev()
+		return collect(&messages, c, k, v, limit)
+	})
+	return messages, err
+}
+
+// collect - до limit сообщений от позиции курсора к более старым, результат старые первыми
+func collect(messages *[]guild.ChatHistoryMessage, c *bolt.Cursor, k, v []byte, limit int) error {
+	for ; k != nil && len(*messages) < limit; k, v = c.Prev() {
+		var m guild.ChatHistoryMessage
+		if err := json.Unmarshal(v, &m); err != nil {
+			return fmt.Errorf("ошибка чтения сообщения %s: %w", k, err)
+		}
+		*messages = append(*messages, m)
+	}
+	for i, j := 0, len(*messages)-1; i < j; i, j = i+1, j-1 {
+		(*messages)[i], (*messages)[j] = (*messages)[j], (*messages)[i]
+	}
+	return nil
+}
+
+func (s *boltStore) Message(roomID int, id string) (guild.ChatHistoryMessage, bool, error) {
+	var m guild.ChatHistoryMessage
+	found := false
+	err := s.db.View(func(tx *bolt.Tx) error {
+		room := tx.Bucket(bucketRooms).Bucket(roomKey(roomID))
+		if room == nil {
+			return nil
+		}
+		data := room.Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &m)
+	})
+	return m, found, err
+}
+
+func (s *boltStore) Delete(roomID int, id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		room := tx.Bucket(bucketRooms).Bucket(roomKey(roomID))
+		if room == nil {
+			return nil
+		}
+		return room.Delete([]byte(id))
+	})
+}
+
+func (s *boltStore) AppendDirect(msg direct.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketDirect).Put([]byte(msg.Id), data)
+	})
+}
+
+func (s *boltStore) Direct() ([]direct.Message, error) {
+	var messages []direct.Message
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketDirect).ForEach(func(k, v []byte) error {
+			var m direct.Message
+			if err := json.Unmarshal(v, &m); err != nil {
+				return fmt.Errorf("ошибка чтения личного сообщения %s: %w", k, err)
+			}
+			messages = append(messages, m)
+			return nil
+		})
+	})
+	return messages, err
+}
+
+func (s *boltStore) SetSanction(sanction Sanction) error {
+	data, err := json.Marshal(sanction)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketSanctions).Put(sanctionID(sanction.RoomID, sanction.UserID, sanction.Kind), data)
+	})
+}
+
+func (s *boltStore) Sanction(roomID, userID int, kind string) (Sanction, bool, error) {
+	var sanction Sanction
+	found := false
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucketSanctions).Get(sanctionID(roomID, userID, kind))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &sanction)
+	})
+	return sanction, found, err
+}
+
+func (s *boltStore) RemoveSanction(roomID, userID int, kind string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketSanctions).Delete(sanctionID(roomID, userID, kind))
+	})
+}
+
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
+
+func roomKey(roomID int) []byte {
+	return []byte(strconv.Itoa(roomID))
+}
+
+func sanctionID(roomID, userID int, kind string) []byte {
+	return []byte(fmt.Sprintf("%d/%d/%s", roomID, userID, kind))
+}
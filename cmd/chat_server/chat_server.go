@@ -1,150 +1,567 @@
+// Локальный чат-сервер: чат гильдий, лобби и личные сообщения по протоколам клиента.
+// Пользователь определяется по access token, переписка и санкции модерации хранятся в памяти или в файле BoltDB.
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
 	"sync"
+	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gorilla/websocket"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
 )
 
-var (
-	upgrader   = websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
-	serverOnce sync.Once
-	chatServer *ChatServer
+const (
+	lobbyID          = 0       // комната общего чата лобби, id гильдий начинаются с 1
+	chatPageLimit    = 50      // максимум сообщений в ответе на запрос истории
+	chatMessageLimit = 500     // максимальная длина сообщения в символах, как в поле ввода клиента
+	readLimit        = 1 << 16 // максимальный размер пакета клиента в байтах
+
+	shutdownTimeout = 10 * time.Second // ожидание отключения клиентов при остановке
 )
 
+var upgrader = websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+
+// ChatServer - комнаты чата гильдий и лобби по протоколу packets/guild.
+// Первым пакетом клиент получает ChatHistory, затем каждое сообщение комнаты как ChatHistoryMessage.
+// В комнатах гильдий участники модерируют друг друга по ролям гильдии, в лобби модерации нет.
 type ChatServer struct {
-	rooms   map[int]*ChatRoom
-	roomsMu sync.Mutex
+	auth    *Authenticator
+	store   Store
+	history int // сколько последних сообщений комнаты хранится и отправляется при подключении
+	roles   Roles
+	guard   *Guard
+	metrics *Metrics
+	rooms   map[int]*chatRoom
+	reads   map[int]map[int]guild.Read // комната -> пользователь -> последняя отметка о прочтении, только в памяти
+	conns   connections
+	mu      sync.Mutex
+}
+
+// ChatOptions - настройки комнат чата
+type ChatOptions struct {
+	History int      // сколько последних сообщений комнаты хранить
+	Roles   Roles    // роли участников гильдий, nil - модерация недоступна
+	Guard   *Guard   // частота сообщений и фильтр текста, nil - без ограничений
+	Metrics *Metrics // счетчик сообщений для /metrics, nil - без учета
+}
+
+// chatRoom - подключения к комнате
+type chatRoom struct {
+	clients map[*client]struct{}
+}
+
+// chatRequest - пакет клиента: сообщение или служебный пакет с полем type
+type chatRequest struct {
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	Before  string `json:"before"`  // HistoryRequest
+	Limit   int    `json:"limit"`   // HistoryRequest
+	To      string `json:"to"`      // Whisper
+	UserID  int    `json:"user_id"` // Moderation
+	Minutes int    `json:"minutes"` // Moderation
+	ID      string `json:"_id"`     // Delete
 }
 
-type ChatRoom struct {
-	clients  map[*websocket.Conn]string
-	messages []ChatMessage
-	mu       sync.Mutex
+// NewChatServer - чат с перепиской и санкциями в store
+func NewChatServer(auth *Authenticator, store Store, opts ChatOptions) *ChatServer {
+	return &ChatServer{
+		auth:    auth,
+		store:   store,
+		history: opts.History,
+		roles:   opts.Roles,
+		guard:   opts.Guard,
+		metrics: opts.Metrics,
+		rooms:   make(map[int]*chatRoom),
+		reads:   make(map[int]map[int]guild.Read),
+	}
 }
 
-type ChatMessage struct {
-	GuildID   int       `json:"guild_id"`
-	Username  string    `json:"username"`
-	Text      string    `json:"text"`
-	Timestamp time.Time `json:"timestamp"`
+// HandleGuild - комната гильдии /ws/guild/{guild_id}/{user_id}
+func (s *ChatServer) HandleGuild(w http.ResponseWriter, r *http.Request) {
+	guildID, err := strconv.Atoi(r.PathValue("guild_id"))
+	if err != nil || guildID <= 0 {
+		http.Error(w, "некорректный guild_id", http.StatusBadRequest)
+		return
+	}
+	s.serve(w, r, guildID)
 }
 
-func NewChatServer() *ChatServer {
-	serverOnce.Do(func() {
-		chatServer = &ChatServer{rooms: make(map[int]*ChatRoom)}
-	})
-	return chatServer
+// HandleLobby - общий чат лобби /ws/lobby/{user_id}
+func (s *ChatServer) HandleLobby(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, r, lobbyID)
 }
 
-func (s *ChatServer) HandleConnection(w http.ResponseWriter, r *http.Request) {
+// serve - подключение владельца токена к комнате roomID, user_id в адресе должен совпадать с токеном
+func (s *ChatServer) serve(w http.ResponseWriter, r *http.Request, roomID int) {
+	identity, ok := authorize(w, r, s.auth)
+	if !ok {
+		return
+	}
+	if ban, ok := s.sanction(roomID, identity.UserID, SanctionBan); ok {
+		http.Error(w, "вы заблокированы в чате гильдии "+until(ban), http.StatusForbidden)
+		return
+	}
+	if !s.conns.enter() {
+		http.Error(w, "сервер останавливается", http.StatusServiceUnavailable)
+		return
+	}
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Println("WebSocket upgrade error:", err)
+		s.conns.exit(nil)
 		return
 	}
-	defer conn.Close()
+	conn.SetReadLimit(readLimit)
+	c := newClient(conn, identity)
+	s.conns.track(c)
+	defer s.conns.exit(c)
+	s.join(roomID, c)
+	defer s.leave(roomID, c)
+	log.Printf("User %s (%d) connected to room %d", identity.Username, identity.UserID, roomID)
+
+	for {
+		var req chatRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			break
+		}
+		switch req.Type {
+		case guild.TypeHistory:
+			c.push(s.page(roomID, req.Before, req.Limit))
+		case guild.TypeWho:
+			c.push(guild.Online{Type: guild.TypeOnline, Users: s.online(roomID)})
+		case guild.TypeWhisper:
+			s.whisper(c, roomID, req)
+		case guild.TypeMute, guild.TypeUnmute, guild.TypeBan, guild.TypeUnban:
+			s.moderate(r.Context(), c, roomID, req)
+		case guild.TypeDelete:
+			s.delete(r.Context(), c, roomID, req.ID)
+		case guild.TypeTyping:
+			s.typing(c, roomID)
+		case guild.TypeRead:
+			s.read(c, roomID, req.ID)
+		case "":
+			s.post(c, roomID, req.Content)
+		default:
+			c.push(guild.Error{Type: guild.TypeError, Message: "неизвестный пакет " + req.Type})
+		}
+	}
+	log.Printf("User %s (%d) disconnected from room %d", identity.Username, identity.UserID, roomID)
+}
 
-	_, msgBytes, err := conn.ReadMessage()
+// authorize - пользователь по токену запроса, совпадающий с user_id в адресе. Ошибка уже отправлена клиенту.
+func authorize(w http.ResponseWriter, r *http.Request, auth *Authenticator) (Identity, bool) {
+	userID, err := strconv.Atoi(r.PathValue("user_id"))
+	if err != nil || userID <= 0 {
+		http.Error(w, "некорректный user_id", http.StatusBadRequest)
+		return Identity{}, false
+	}
+	identity, err := auth.Identify(r.Context(), r)
+	if errors.Is(err, errUnauthorized) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return Identity{}, false
+	}
 	if err != nil {
-		log.Println("Read error:", err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return Identity{}, false
+	}
+	if identity.UserID != userID {
+		http.Error(w, "токен принадлежит другому пользователю", http.StatusForbidden)
+		return Identity{}, false
+	}
+	return identity, true
+}
+
+// room - комната roomID, создается при первом обращении. Вызывается под блокировкой.
+func (s *ChatServer) room(roomID int) *chatRoom {
+	room, ok := s.rooms[roomID]
+	if !ok {
+		room = &chatRoom{clients: make(map[*client]struct{})}
+		s.rooms[roomID] = room
+	}
+	return room
+}
+
+// join - история комнаты и подписка на новые сообщения. Под одной блокировкой сообщения не теряются и не повторяются.
+func (s *ChatServer) join(roomID int, c *client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	history, err := s.store.Latest(roomID, s.history)
+	if err != nil {
+		log.Printf("Ошибка чтения истории комнаты %d: %v", roomID, err)
+		c.push(guild.Error{Type: guild.TypeError, Message: "история чата недоступна"})
+	}
+	c.push(guild.ChatHistory{Type: guild.HistoryInitial, Data: history})
+	for _, read := range s.reads[roomID] {
+		c.push(read)
+	}
+	s.room(roomID).clients[c] = struct{}{}
+}
+
+// leave - отключение клиента, пустая комната удаляется
+func (s *ChatServer) leave(roomID int, c *client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	room := s.room(roomID)
+	delete(room.clients, c)
+	if len(room.clients) == 0 {
+		delete(s.rooms, roomID)
+	}
+	c.close()
+}
+
+// post - сохранение сообщения и рассылка всем в комнате, включая автора
+func (s *ChatServer) post(c *client, roomID int, content string) {
+	content, ok := s.check(c, roomID, content)
+	if !ok {
 		return
 	}
 
-	var init struct {
-		GuildID  int    `json:"guild_id"`
-		Username string `json:"username"`
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg, err := s.message(c, roomID, content)
+	if err == nil {
+		err = s.store.Append(roomID, msg, s.history)
+	}
+	if err != nil {
+		log.Printf("Ошибка сохранения сообщения в комнате %d: %v", roomID, err)
+		c.push(guild.Error{Type: guild.TypeError, Message: "сообщение не сохранено, попробуйте позже"})
+		return
+	}
+	s.metrics.Message(time.Now())
+	for member := range s.room(roomID).clients {
+		member.push(msg)
 	}
-	if err := json.Unmarshal(msgBytes, &init); err != nil {
-		log.Println("Unmarshal error:", err)
+}
+
+// whisper - шепот подключениям получателя и автора, если получатель подключен к комнате. В историю комнаты не попадает.
+func (s *ChatServer) whisper(c *client, roomID int, req chatRequest) {
+	content, ok := s.check(c, roomID, req.Content)
+	if !ok {
 		return
 	}
 
-	log.Printf("User %s connected to guild %d", init.Username, init.GuildID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	// Получаем или создаем комнату
-	s.roomsMu.Lock()
-	room, exists := s.rooms[init.GuildID]
-	if !exists {
-		room = &ChatRoom{
-			clients:  make(map[*websocket.Conn]string),
-			messages: make([]ChatMessage, 0, 100), // увеличил размер слайса
+	room := s.room(roomID)
+	delivered := false
+	for member := range room.clients {
+		delivered = delivered || member.identity.Username == req.To
+	}
+	if !delivered {
+		c.push(guild.Error{Type: guild.TypeError, Message: req.To + " не в чате"})
+		return
+	}
+	msg, err := s.message(c, roomID, content)
+	if err != nil {
+		log.Printf("Ошибка выдачи ID сообщения: %v", err)
+		c.push(guild.Error{Type: guild.TypeError, Message: "сообщение не отправлено, попробуйте позже"})
+		return
+	}
+	msg.To = req.To
+	s.metrics.Message(time.Now())
+	for member := range room.clients {
+		if member.identity.Username == req.To || member.identity.UserID == c.identity.UserID {
+			member.push(msg)
 		}
-		s.rooms[init.GuildID] = room
 	}
-	s.roomsMu.Unlock()
-
-	// Добавляем клиента
-	room.mu.Lock()
-	room.clients[conn] = init.Username
-	room.mu.Unlock()
+}
 
-	// Отправляем историю
-	room.mu.Lock()
-	for _, msg := range room.messages {
-		if err := conn.WriteJSON(msg); err != nil {
-			log.Println("Send history error:", err)
+// typing - автор набирает сообщение: пакет остальным в комнате. Участник под мутом не виден.
+func (s *ChatServer) typing(c *client, roomID int) {
+	if _, muted := s.sanction(roomID, c.identity.UserID, SanctionMute); muted {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	packet := guild.Typing{Type: guild.TypeTyping, UserId: c.identity.UserID, Username: c.identity.Username}
+	for member := range s.room(roomID).clients {
+		if member.identity.UserID != c.identity.UserID {
+			member.push(packet)
 		}
 	}
-	room.mu.Unlock()
+}
 
-	// Читаем сообщения
-	for {
-		_, msgBytes, err := conn.ReadMessage()
-		if err != nil {
-			break
+// read - отметка о прочтении сообщений до id: запоминается и пересылается остальным подключениям комнаты.
+// ID одной длины сравниваются как строки, более ранняя отметка игнорируется.
+func (s *ChatServer) read(c *client, roomID int, id string) {
+	if id == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reads := s.reads[roomID]
+	if reads == nil {
+		reads = make(map[int]guild.Read)
+		s.reads[roomID] = reads
+	}
+	if prev, ok := reads[c.identity.UserID]; ok && len(prev.Id) == len(id) && prev.Id >= id {
+		return
+	}
+	packet := guild.Read{Type: guild.TypeRead, Id: id, UserId: c.identity.UserID, Username: c.identity.Username}
+	reads[c.identity.UserID] = packet
+	for member := range s.room(roomID).clients {
+		if member != c {
+			member.push(packet)
 		}
+	}
+}
+
+// check - текст сообщения после проверок длины, мута, частоты и фильтра. Отказ уже отправлен автору.
+func (s *ChatServer) check(c *client, roomID int, content string) (string, bool) {
+	if content == "" {
+		return "", false
+	}
+	if utf8.RuneCountInString(content) > chatMessageLimit {
+		c.push(guild.Error{Type: guild.TypeError, Message: fmt.Sprintf("сообщение длиннее %d символов", chatMessageLimit)})
+		return "", false
+	}
+	if mute, ok := s.sanction(roomID, c.identity.UserID, SanctionMute); ok {
+		c.push(guild.Error{Type: guild.TypeError, Message: "вам запрещено писать в чат гильдии " + until(mute)})
+		return "", false
+	}
+	content, err := s.guard.Check(c.identity.UserID, content)
+	if err != nil {
+		c.push(guild.Error{Type: guild.TypeError, Message: err.Error()})
+		return "", false
+	}
+	return content, true
+}
 
-		var msg ChatMessage
-		if err := json.Unmarshal(msgBytes, &msg); err != nil {
-			log.Println("Message parse error:", err)
+// message - новое сообщение автора c
+func (s *ChatServer) message(c *client, roomID int, content string) (guild.ChatHistoryMessage, error) {
+	id, err := s.store.NextID()
+	if err != nil {
+		return guild.ChatHistoryMessage{}, err
+	}
+	return guild.ChatHistoryMessage{
+		Id:        fmt.Sprintf("%024x", id),
+		GuildId:   roomID,
+		UserId:    c.identity.UserID,
+		Username:  c.identity.Username,
+		Content:   content,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+	}, nil
+}
+
+// page - до limit сообщений комнаты старше сообщения before
+func (s *ChatServer) page(roomID int, before string, limit int) guild.ChatHistory {
+	if limit <= 0 || limit > chatPageLimit {
+		limit = chatPageLimit
+	}
+	messages, err := s.store.Before(roomID, before, limit)
+	if err != nil {
+		log.Printf("Ошибка чтения истории комнаты %d: %v", roomID, err)
+		messages = []guild.ChatHistoryMessage{}
+	}
+	return guild.ChatHistory{Type: guild.HistoryPage, Data: messages}
+}
+
+// online - подключенные к комнате, один раз на пользователя
+func (s *ChatServer) online(roomID int) []guild.OnlineUser {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.room(roomID).users()
+}
+
+// users - подключенные пользователи по имени, вызывается под блокировкой сервера
+func (room *chatRoom) users() []guild.OnlineUser {
+	seen := make(map[int]struct{})
+	users := []guild.OnlineUser{}
+	for member := range room.clients {
+		if _, ok := seen[member.identity.UserID]; ok {
 			continue
 		}
+		seen[member.identity.UserID] = struct{}{}
+		users = append(users, guild.OnlineUser{UserId: member.identity.UserID, Username: member.identity.Username})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return users
+}
 
-		log.Printf("Message from %s in guild %d: %s", msg.Username, init.GuildID, msg.Text)
+// RoomInfo - комната с подключенными участниками для API администратора
+type RoomInfo struct {
+	RoomID  int                `json:"room_id"` // 0 - лобби
+	Clients int                `json:"clients"` // подключений, у пользователя их может быть несколько
+	Users   []guild.OnlineUser `json:"users"`
+}
 
-		room.mu.Lock()
-		room.messages = append(room.messages, msg)
-		if len(room.messages) > 10 {
-			room.messages = room.messages[1:]
-		}
+// Rooms - комнаты с подключениями по возрастанию ID
+func (s *ChatServer) Rooms() []RoomInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-		for client := range room.clients {
-			if err := client.WriteJSON(msg); err != nil {
-				delete(room.clients, client)
-				client.Close()
-			}
-		}
-		room.mu.Unlock()
+	rooms := make([]RoomInfo, 0, len(s.rooms))
+	for id, room := range s.rooms {
+		rooms = append(rooms, RoomInfo{RoomID: id, Clients: len(room.clients), Users: room.users()})
 	}
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i].RoomID < rooms[j].RoomID })
+	return rooms
+}
 
-	// Удаляем клиента
-	room.mu.Lock()
-	delete(room.clients, conn)
-	room.mu.Unlock()
-	log.Printf("User %s disconnected from guild %d", init.Username, init.GuildID)
+// Kick - отключение пользователя userID от комнаты roomID с причиной reason, возвращает число закрытых подключений
+func (s *ChatServer) Kick(roomID, userID int, reason string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	room, ok := s.rooms[roomID]
+	if !ok {
+		return 0
+	}
+	return s.kick(room, userID, reason)
 }
 
-func (r *ChatRoom) messageExists(msg ChatMessage) bool {
-	for _, m := range r.messages {
-		if m.Username == msg.Username && m.Text == msg.Text && m.Timestamp.Equal(msg.Timestamp) {
-			return true
+// kick - исключение подключений пользователя из рассылки комнаты, ошибка reason отправляется перед закрытием.
+// Вызывается под блокировкой.
+func (s *ChatServer) kick(room *chatRoom, userID int, reason string) int {
+	kicked := 0
+	for member := range room.clients {
+		if member.identity.UserID != userID {
+			continue
 		}
+		delete(room.clients, member)
+		member.push(guild.Error{Type: guild.TypeError, Message: reason})
+		member.close()
+		kicked++
+	}
+	return kicked
+}
+
+// Broadcast - служебное сообщение всем подключенным к комнате roomID, возвращает число получателей
+func (s *ChatServer) Broadcast(roomID int, message string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	room, ok := s.rooms[roomID]
+	if !ok {
+		return 0
 	}
-	return false
+	for member := range room.clients {
+		member.push(guild.Notice{Type: guild.TypeNotice, Message: message})
+	}
+	return len(room.clients)
+}
+
+// Connections - открытые подключения к комнатам
+func (s *ChatServer) Connections() int {
+	return s.conns.count()
+}
+
+// Closing - сервер останавливается и не принимает подключения
+func (s *ChatServer) Closing() bool {
+	return s.conns.closed()
+}
+
+// Shutdown - кадр закрытия всем подключениям и ожидание их отключения до отмены ctx
+func (s *ChatServer) Shutdown(ctx context.Context) error {
+	return s.conns.shutdown(ctx)
 }
 
-func StartChatServer() {
-	server := NewChatServer()
-	http.HandleFunc("/ws", server.HandleConnection)
-	log.Println("Chat server started on :8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+// newMux - маршруты чат-сервера, admin - служебные маршруты
+func newMux(chat *ChatServer, direct *DirectServer, admin *Admin) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ws/guild/{guild_id}/{user_id}", chat.HandleGuild)
+	mux.HandleFunc("GET /ws/lobby/{user_id}", chat.HandleLobby)
+	mux.HandleFunc("GET /ws/direct/{user_id}", direct.HandleConnection)
+	admin.register(mux)
+	return mux
 }
 
 func main() {
-	StartChatServer()
+	addr := flag.String("addr", ":8080", "адрес сервера")
+	history := flag.Int("history", 200, "сколько последних сообщений комнаты хранить")
+	authURL := flag.String("auth-url", "", "адрес сервиса авторизации для проверки токенов")
+	insecureClaims := flag.Bool("insecure-claims", false, "без -auth-url брать пользователя из claims токена без проверки подписи, только для отладки")
+	dbPath := flag.String("db", "", "файл BoltDB для переписки и санкций, пусто - хранение в памяти")
+	guildsURL := flag.String("guilds-url", "", "адрес сервиса гильдий для проверки ролей модераторов, пусто - без модерации")
+	rateLimit := flag.Int("rate-limit", 5, "сколько сообщений пользователь может отправить за rate-window, 0 - без ограничения")
+	rateWindow := flag.Duration("rate-window", 10*time.Second, "окно ограничения частоты сообщений")
+	wordsPath := flag.String("profanity-file", "", "файл запрещенных слов, слово на строку: слова заменяются звездочками")
+	adminToken := flag.String("admin-token", "", "токен API администратора /admin/, пусто - API отключено")
+	flag.Parse()
+
+	if *history <= 0 {
+		log.Fatal("history должен быть больше 0")
+	}
+	var auth *Authenticator
+	switch {
+	case *authURL != "":
+		auth = NewAuthenticator(*authURL)
+	case *insecureClaims:
+		auth = NewInsecureAuthenticator()
+		log.Println("ВНИМАНИЕ: токены не проверяются, пользователь берется из claims без проверки подписи (--insecure-claims).")
+		log.Println("ВНИМАНИЕ: любой клиент может выдать себя за другого пользователя, в том числе за модератора.")
+	default:
+		log.Fatal("нужен адрес сервиса авторизации --auth-url или явный --insecure-claims для отладки")
+	}
+
+	store := NewMemoryStore()
+	if *dbPath != "" {
+		var err error
+		if store, err = OpenBoltStore(*dbPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	defer store.Close()
+
+	var filter Filter
+	if *wordsPath != "" {
+		var err error
+		if filter, err = LoadWordFilter(*wordsPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	guard := NewGuard(*rateLimit, *rateWindow, filter)
+
+	var roles Roles
+	if *guildsURL != "" {
+		roles = NewGuildRoles(*guildsURL)
+	} else {
+		log.Println("Модерация отключена: не задан адрес сервиса гильдий (--guilds-url)")
+	}
+
+	metrics := NewMetrics()
+	opts := ChatOptions{History: *history, Roles: roles, Guard: guard, Metrics: metrics}
+	direct, err := NewDirectServer(auth, store, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	chat := NewChatServer(auth, store, opts)
+	server := &http.Server{Addr: *addr, Handler: newMux(chat, direct, NewAdmin(chat, direct, metrics, *adminToken))}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		log.Printf("Chat server started on %s", *addr)
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.Println(err)
+			stop()
+		}
+	}()
+	<-ctx.Done()
+
+	// http.Server.Shutdown не ждет websocket: их обработчики закрывают сами серверы чата
+	log.Println("Остановка чат-сервера")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Ошибка остановки HTTP сервера: %v", err)
+	}
+	for _, s := range []interface{ Shutdown(context.Context) error }{chat, direct} {
+		if err := s.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Не все подключения закрыты: %v", err)
+		}
+	}
 }
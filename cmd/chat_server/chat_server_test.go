@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+)
+
+// accessToken - неподписанный токен пользователя sub с именем username
+func accessToken(t *testing.T, sub, username string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": sub, "username": username}).SignedString([]byte("test"))
+	if err != nil {
+		t.Fatalf("ошибка подписи токена: %v", err)
+	}
+	return "Bearer " + token
+}
+
+// newServer - чат-сервер с хранилищем в памяти
+func newServer(t *testing.T, auth *Authenticator, opts ChatOptions) *httptest.Server {
+	t.Helper()
+	store := NewMemoryStore()
+	direct, err := NewDirectServer(auth, store, opts)
+	if err != nil {
+		t.Fatalf("ошибка создания сервера личных сообщений: %v", err)
+	}
+	server := httptest.NewServer(newMux(NewChatServer(auth, store, opts), direct, nil))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// dial - подключение к чату с токеном, status - код ответа при ошибке подключения
+func dial(t *testing.T, server *httptest.Server, path, token string) (*websocket.Conn, int) {
+	t.Helper()
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", token)
+	}
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+path, header)
+	if err != nil {
+		if resp == nil {
+			t.Fatalf("ошибка подключения к %s: %v", path, err)
+		}
+		return nil, resp.StatusCode
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, http.StatusSwitchingProtocols
+}
+
+// read - следующий пакет клиента в value
+func read(t *testing.T, conn *websocket.Conn, value any) {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err := conn.ReadJSON(value); err != nil {
+		t.Fatalf("ошибка чтения пакета: %v", err)
+	}
+}
+
+func TestChatServerIdentity(t *testing.T) {
+	server := newServer(t, NewInsecureAuthenticator(), ChatOptions{History: 10})
+
+	if _, status := dial(t, server, "/ws/guild/1/1", ""); status != http.StatusUnauthorized {
+		t.Errorf("подключение без токена: статус %d", status)
+	}
+	if _, status := dial(t, server, "/ws/guild/1/2", accessToken(t, "1", "admiral")); status != http.StatusForbidden {
+		t.Errorf("подключение с чужим user_id: статус %d", status)
+	}
+
+	// имя автора берется из токена, а не из пакета клиента
+	conn, _ := dial(t, server, "/ws/guild/1/1", accessToken(t, "1", "admiral"))
+	var history guild.ChatHistory
+	read(t, conn, &history)
+	conn.WriteJSON(map[string]string{"content": "Всем привет", "username": "bosun"})
+	var msg guild.ChatHistoryMessage
+	read(t, conn, &msg)
+	if msg.Username != "admiral" || msg.UserId != 1 || msg.GuildId != 1 || msg.Id == "" {
+		t.Errorf("сообщение: %+v", msg)
+	}
+}
+
+func TestChatServerHistory(t *testing.T) {
+	server := newServer(t, NewInsecureAuthenticator(), ChatOptions{History: 2})
+
+	admiral, _ := dial(t, server, "/ws/guild/1/1", accessToken(t, "1", "admiral"))
+	var history guild.ChatHistory
+	read(t, admiral, &history)
+	if history.Type != guild.HistoryInitial || len(history.Data) != 0 {
+		t.Fatalf("история пустой комнаты: %+v", history)
+	}
+	for _, text := range []string{"раз", "два", "три"} {
+		admiral.WriteJSON(guild.ChatMessage{Msg: text})
+		var msg guild.ChatHistoryMessage
+		read(t, admiral, &msg)
+	}
+
+	// хранятся только последние history сообщений
+	bosun, _ := dial(t, server, "/ws/guild/1/2", accessToken(t, "2", "bosun"))
+	read(t, bosun, &history)
+	if len(history.Data) != 2 || history.Data[0].Content != "два" || history.Data[1].Content != "три" {
+		t.Fatalf("история при подключении: %+v", history.Data)
+	}
+
+	// страница старше первого сообщения истории пуста: более старые вытеснены
+	bosun.WriteJSON(guild.HistoryRequest{Type: guild.TypeHistory, Before: history.Data[0].Id, Limit: 50})
+	var page guild.ChatHistory
+	read(t, bosun, &page)
+	if page.Type != guild.HistoryPage || len(page.Data) != 0 {
+		t.Errorf("страница истории: %+v", page)
+	}
+
+	bosun.WriteJSON(guild.WhoRequest{Type: guild.TypeWho})
+	var online guild.Online
+	read(t, bosun, &online)
+	if data, _ := json.Marshal(online.Users); string(data) != `[{"user_id":1,"username":"admiral"},{"user_id":2,"username":"bosun"}]` {
+		t.Errorf("участники в комнате: %s", data)
+	}
+}
+
+func TestChatServerAuthService(t *testing.T) {
+	good := accessToken(t, "1", "подмена")
+	auth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/1" || r.Header.Get("Authorization") != good {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id": 1, "username": "admiral"}`))
+	}))
+	defer auth.Close()
+	server := newServer(t, NewAuthenticator(auth.URL), ChatOptions{History: 10})
+
+	if _, status := dial(t, server, "/ws/lobby/1", accessToken(t, "1", "admiral")); status != http.StatusUnauthorized {
+		t.Errorf("токен, отклоненный сервисом авторизации: статус %d", status)
+	}
+
+	// имя берется из профиля сервиса авторизации
+	conn, _ := dial(t, server, "/ws/lobby/1", good)
+	var history guild.ChatHistory
+	read(t, conn, &history)
+	conn.WriteJSON(guild.ChatMessage{Msg: "Привет, лобби"})
+	var msg guild.ChatHistoryMessage
+	read(t, conn, &msg)
+	if msg.Username != "admiral" || msg.GuildId != lobbyID {
+		t.Errorf("сообщение в лобби: %+v", msg)
+	}
+}
+
+func TestChatServerWithoutAuthService(t *testing.T) {
+	// без сервиса авторизации и --insecure-claims подпись не проверить, подключения отклоняются
+	server := newServer(t, NewAuthenticator(""), ChatOptions{History: 10})
+	if _, status := dial(t, server, "/ws/lobby/1", accessToken(t, "1", "admiral")); status != http.StatusUnauthorized {
+		t.Errorf("подключение без сервиса авторизации: статус %d", status)
+	}
+}
+
+func TestChatServerTypingAndReads(t *testing.T) {
+	server := newServer(t, NewInsecureAuthenticator(), ChatOptions{History: 10})
+	var history guild.ChatHistory
+	admiral, _ := dial(t, server, "/ws/guild/1/1", accessToken(t, "1", "admiral"))
+	read(t, admiral, &history)
+	bosun, _ := dial(t, server, "/ws/guild/1/2", accessToken(t, "2", "bosun"))
+	read(t, bosun, &history)
+
+	// автор не получает свой пакет набора, остальные получают его с именем из токена
+	bosun.WriteJSON(guild.Typing{Type: guild.TypeTyping, Username: "подмена"})
+	var typing guild.Typing
+	if read(t, admiral, &typing); typing.Type != guild.TypeTyping || typing.UserId != 2 || typing.Username != "bosun" {
+		t.Errorf("набор сообщения: %+v", typing)
+	}
+
+	admiral.WriteJSON(guild.ChatMessage{Msg: "Сбор в 20:00"})
+	var msg guild.ChatHistoryMessage
+	read(t, admiral, &msg)
+	read(t, bosun, &msg)
+	bosun.WriteJSON(guild.Read{Type: guild.TypeRead, Id: msg.Id})
+	var receipt guild.Read
+	if read(t, admiral, &receipt); receipt.Type != guild.TypeRead || receipt.Id != msg.Id || receipt.UserId != 2 {
+		t.Errorf("отметка о прочтении: %+v", receipt)
+	}
+
+	// подключившийся получает отметки участников после истории
+	cabin, _ := dial(t, server, "/ws/guild/1/3", accessToken(t, "3", "cabin"))
+	read(t, cabin, &history)
+	receipt = guild.Read{}
+	if read(t, cabin, &receipt); receipt.Id != msg.Id || receipt.Username != "bosun" {
+		t.Errorf("отметки при подключении: %+v", receipt)
+	}
+}
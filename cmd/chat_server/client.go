@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	clientQueue  = 64               // пакетов в очереди отправки клиента
+	writeTimeout = 10 * time.Second // предельное время записи пакета в соединение
+)
+
+// client - подключение с собственной горутиной отправки.
+// Рассылка только ставит пакет в очередь и не ждет медленных клиентов, запись в соединение выполняет writeLoop.
+type client struct {
+	conn     *websocket.Conn
+	identity Identity
+	send     chan any
+	closed   bool // отправка завершена, новые пакеты отбрасываются
+	mu       sync.Mutex
+}
+
+func newClient(conn *websocket.Conn, identity Identity) *client {
+	c := &client{conn: conn, identity: identity, send: make(chan any, clientQueue)}
+	go c.writeLoop()
+	return c
+}
+
+func (c *client) writeLoop() {
+	for packet := range c.send {
+		c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := c.conn.WriteJSON(packet); err != nil {
+			log.Printf("Ошибка отправки пользователю %d: %v", c.identity.UserID, err)
+			c.conn.Close()
+			// очередь дочитывается до close, чтобы push не блокировался
+			for range c.send {
+			}
+			return
+		}
+	}
+	c.conn.Close()
+}
+
+// push - пакет в очередь отправки. Клиент с переполненной очередью отключается:
+// пропуск пакетов сломал бы порядок переписки.
+func (c *client) push(packet any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	select {
+	case c.send <- packet:
+	default:
+		log.Printf("Пользователь %d не успевает получать сообщения, отключение", c.identity.UserID)
+		c.conn.Close()
+	}
+}
+
+// close - завершение отправки после выхода клиента из всех комнат или при бане.
+// Пакеты из очереди отправляются, затем соединение закрывается.
+func (c *client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.send)
+	}
+}
+
+// shutdown - кадр закрытия при остановке сервера и закрытие соединения.
+// WriteControl можно вызывать одновременно с записью writeLoop.
+func (c *client) shutdown() {
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "сервер остановлен")
+	if err := c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(writeTimeout)); err != nil {
+		log.Printf("Ошибка отправки кадра закрытия пользователю %d: %v", c.identity.UserID, err)
+	}
+	c.conn.Close()
+}
+
+// connections - подключения сервера для плавной остановки: обработчики websocket не учитываются http.Server.Shutdown
+type connections struct {
+	clients map[*client]struct{}
+	closing bool
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+}
+
+// enter - начало обработки подключения, false - сервер останавливается и подключение нужно отклонить
+func (c *connections) enter() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closing {
+		return false
+	}
+	c.wg.Add(1)
+	return true
+}
+
+// track - подключение, закрываемое при остановке. Подключение после начала остановки закрывается сразу.
+func (c *connections) track(cl *client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closing {
+		go cl.shutdown()
+		return
+	}
+	if c.clients == nil {
+		c.clients = make(map[*client]struct{})
+	}
+	c.clients[cl] = struct{}{}
+}
+
+// exit - завершение обработки подключения, начатой enter
+func (c *connections) exit(cl *client) {
+	c.mu.Lock()
+	delete(c.clients, cl)
+	c.mu.Unlock()
+	c.wg.Done()
+}
+
+// count - открытые подключения
+func (c *connections) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.clients)
+}
+
+// closed - сервер останавливается
+func (c *connections) closed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closing
+}
+
+// shutdown - кадр закрытия всем подключениям и ожидание завершения их обработчиков до отмены ctx
+func (c *connections) shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	c.closing = true
+	clients := make([]*client, 0, len(c.clients))
+	for cl := range c.clients {
+		clients = append(clients, cl)
+	}
+	c.mu.Unlock()
+
+	for _, cl := range clients {
+		cl.shutdown()
+	}
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/direct"
+)
+
+// directHistoryLimit - максимум сообщений в ответе на запрос переписки
+const directHistoryLimit = 50
+
+// DirectServer - личные сообщения по протоколу packets/direct.
+// Пользователь подключается по адресу /ws/direct/{user_id} со своим access token, имя берется из токена.
+// Сообщения сохраняются в Store, счетчики непрочитанных - только в памяти.
+type DirectServer struct {
+	auth     *Authenticator
+	store    Store
+	guard    *Guard
+	metrics  *Metrics
+	clients  map[int]map[*client]struct{} // ключ - user_id
+	names    map[int]string               // имена подключавшихся пользователей и участников переписки
+	messages []direct.Message
+	unread   map[int]map[int]int // получатель -> автор -> непрочитанных
+	conns    connections
+	mu       sync.Mutex
+}
+
+// directRequest - пакет клиента, тип задается полем type
+type directRequest struct {
+	Type    string `json:"type"`
+	To      int    `json:"to_id"`
+	Content string `json:"content"`
+	With    int    `json:"with_id"`
+	Before  string `json:"before"`
+	Limit   int    `json:"limit"`
+}
+
+// NewDirectServer - личные сообщения с перепиской из store. Из opts используются Guard и Metrics.
+func NewDirectServer(auth *Authenticator, store Store, opts ChatOptions) (*DirectServer, error) {
+	messages, err := store.Direct()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки личных сообщений: %w", err)
+	}
+	s := &DirectServer{
+		auth:     auth,
+		store:    store,
+		guard:    opts.Guard,
+		metrics:  opts.Metrics,
+		clients:  make(map[int]map[*client]struct{}),
+		names:    make(map[int]string),
+		messages: messages,
+		unread:   make(map[int]map[int]int),
+	}
+	for _, msg := range messages {
+		s.names[msg.FromId], s.names[msg.ToId] = msg.FromName, msg.ToName
+	}
+	return s, nil
+}
+
+func (s *DirectServer) HandleConnection(w http.ResponseWriter, r *http.Request) {
+	identity, ok := authorize(w, r, s.auth)
+	if !ok {
+		return
+	}
+	userID := identity.UserID
+	if !s.conns.enter() {
+		http.Error(w, "сервер останавливается", http.StatusServiceUnavailable)
+		return
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println("WebSocket upgrade error:", err)
+		s.conns.exit(nil)
+		return
+	}
+	conn.SetReadLimit(readLimit)
+	c := newClient(conn, identity)
+	s.conns.track(c)
+	defer s.conns.exit(c)
+
+	s.mu.Lock()
+	s.names[userID] = identity.Username
+	if s.clients[userID] == nil {
+		s.clients[userID] = make(map[*client]struct{})
+	}
+	s.clients[userID][c] = struct{}{}
+	c.push(direct.Conversations{Type: direct.TypeConversations, Items: s.conversations(userID)})
+	s.mu.Unlock()
+	log.Printf("User %d connected to direct messages", userID)
+
+	for {
+		var req directRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			break
+		}
+
+		s.mu.Lock()
+		switch req.Type {
+		case direct.TypeSend:
+			s.send(c, userID, req)
+		case direct.TypeHistory:
+			c.push(direct.History{Type: direct.TypeHistory, With: req.With, Data: s.history(userID, req)})
+		case direct.TypeRead:
+			delete(s.unread[userID], req.With)
+		}
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	delete(s.clients[userID], c)
+	c.close()
+	s.mu.Unlock()
+	log.Printf("User %d disconnected from direct messages", userID)
+}
+
+// send - сохранение сообщения и доставка автору и получателю, вызывается под блокировкой
+func (s *DirectServer) send(c *client, userID int, req directRequest) {
+	if req.To <= 0 || req.To == userID || req.Content == "" || utf8.RuneCountInString(req.Content) > chatMessageLimit {
+		c.push(direct.Error{Type: direct.TypeError, Message: "некорректное сообщение"})
+		return
+	}
+
+	content, err := s.guard.Check(userID, req.Content)
+	if err != nil {
+		c.push(direct.Error{Type: direct.TypeError, Message: err.Error()})
+		return
+	}
+
+	id, err := s.store.NextID()
+	if err != nil {
+		log.Printf("Ошибка выдачи ID сообщения: %v", err)
+		c.push(direct.Error{Type: direct.TypeError, Message: "сообщение не отправлено, попробуйте позже"})
+		return
+	}
+	msg := direct.Message{
+		Type:      direct.TypeMessage,
+		Id:        fmt.Sprintf("%024x", id),
+		FromId:    userID,
+		FromName:  s.name(userID),
+		ToId:      req.To,
+		ToName:    s.name(req.To),
+		Content:   content,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if err := s.store.AppendDirect(msg); err != nil {
+		log.Printf("Ошибка сохранения личного сообщения: %v", err)
+		c.push(direct.Error{Type: direct.TypeError, Message: "сообщение не сохранено, попробуйте позже"})
+		return
+	}
+	s.messages = append(s.messages, msg)
+	s.metrics.Message(time.Now())
+	if s.unread[req.To] == nil {
+		s.unread[req.To] = make(map[int]int)
+	}
+	s.unread[req.To][userID]++
+	// текст личного сообщения в журнал не пишется
+	log.Printf("Direct message %s from %d to %d", msg.Id, userID, req.To)
+
+	for _, id := range []int{userID, req.To} {
+		for member := range s.clients[id] {
+			member.push(msg)
+		}
+	}
+}
+
+// history - сообщения переписки старше req.Before, старые первыми, вызывается под блокировкой
+func (s *DirectServer) history(userID int, req directRequest) []direct.Message {
+	var messages []direct.Message
+	for _, msg := range s.messages {
+		if msg.Id == req.Before {
+			break
+		}
+		if peer, _ := msg.Peer(userID); peer == req.With && (msg.FromId == userID || msg.ToId == userID) {
+			messages = append(messages, msg)
+		}
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > directHistoryLimit {
+		limit = directHistoryLimit
+	}
+	return append([]direct.Message{}, messages[max(len(messages)-limit, 0):]...)
+}
+
+// conversations - переписки пользователя, последние первыми, вызывается под блокировкой
+func (s *DirectServer) conversations(userID int) []direct.Conversation {
+	last := make(map[int]direct.Message)
+	for _, msg := range s.messages {
+		if msg.FromId == userID || msg.ToId == userID {
+			peer, _ := msg.Peer(userID)
+			last[peer] = msg
+		}
+	}
+	items := []direct.Conversation{}
+	for peer, msg := range last {
+		items = append(items, direct.Conversation{UserId: peer, Username: s.name(peer), Unread: s.unread[userID][peer], Last: msg})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Last.Timestamp > items[j].Last.Timestamp })
+	return items
+}
+
+// name - имя пользователя из токена последнего подключения, вызывается под блокировкой
+func (s *DirectServer) name(userID int) string {
+	if name, ok := s.names[userID]; ok {
+		return name
+	}
+	return fmt.Sprintf("user%d", userID)
+}
+
+// Kick - отключение пользователя userID от личных сообщений с причиной reason, возвращает число закрытых подключений
+func (s *DirectServer) Kick(userID int, reason string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kicked := 0
+	for member := range s.clients[userID] {
+		delete(s.clients[userID], member)
+		member.push(direct.Error{Type: direct.TypeError, Message: reason})
+		member.close()
+		kicked++
+	}
+	return kicked
+}
+
+// Connections - открытые подключения к личным сообщениям
+func (s *DirectServer) Connections() int {
+	return s.conns.count()
+}
+
+// Shutdown - кадр закрытия всем подключениям и ожидание их отключения до отмены ctx
+func (s *DirectServer) Shutdown(ctx context.Context) error {
+	return s.conns.shutdown(ctx)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// metricsWindow - за сколько последних секунд считается частота сообщений
+const metricsWindow = 60
+
+// Metrics - счетчик сообщений во всех чатах для /metrics. nil Metrics ничего не считает.
+type Metrics struct {
+	total   int64
+	seconds [metricsWindow]int64 // сообщений за секунду, индекс - unix время по модулю окна
+	stamps  [metricsWindow]int64 // unix время, к которому относится счетчик seconds
+	mu      sync.Mutex
+}
+
+// NewMetrics - пустые счетчики
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// Message - отправлено сообщение в момент now
+func (m *Metrics) Message(now time.Time) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.total++
+	second := now.Unix()
+	i := second % metricsWindow
+	if m.stamps[i] != second {
+		m.stamps[i], m.seconds[i] = second, 0
+	}
+	m.seconds[i]++
+}
+
+// Total - сообщений с запуска сервера
+func (m *Metrics) Total() int64 {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.total
+}
+
+// Rate - среднее число сообщений в секунду за последние metricsWindow секунд до now
+func (m *Metrics) Rate(now time.Time) float64 {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var sum int64
+	second := now.Unix()
+	for i, stamp := range m.stamps {
+		if stamp > second-metricsWindow && stamp <= second {
+			sum += m.seconds[i]
+		}
+	}
+	return float64(sum) / metricsWindow
+}
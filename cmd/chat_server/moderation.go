@@ -0,0 +1,328 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+	"lesta-start-battleship/cli/storage/token"
+)
+
+// errNoRights - роль пользователя не управляет ролью участника
+var errNoRights = errors.New("недостаточно прав: ваша роль в гильдии не управляет этим участником")
+
+// Roles - участники гильдий с ролями для проверки прав модерации
+type Roles interface {
+	// Member - участник с гильдией и ролью, ok false - пользователь не состоит в гильдии.
+	// header - заголовок Authorization пользователя, от имени которого выполняется запрос.
+	Member(ctx context.Context, header string, userID int) (member guilds.MemberResponse, ok bool, err error)
+}
+
+// guildRoles - роли из сервиса гильдий, запрос выполняется с токеном модератора
+type guildRoles struct {
+	baseURL string
+}
+
+// NewGuildRoles - роли из сервиса гильдий по адресу baseURL
+func NewGuildRoles(baseURL string) Roles {
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return guildRoles{baseURL: baseURL}
+}
+
+func (g guildRoles) Member(ctx context.Context, header string, userID int) (guilds.MemberResponse, bool, error) {
+	tokens := token.NewStorage()
+	tokens.SetTokens(header, "")
+	client, err := guilds.NewClient(g.baseURL, tokens)
+	if err != nil {
+		return guilds.MemberResponse{}, false, err
+	}
+
+	member, err := client.GetMemberByUserID(ctx, userID)
+	var statusErr *guilds.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return guilds.MemberResponse{}, false, nil
+	}
+	if err != nil {
+		return guilds.MemberResponse{}, false, err
+	}
+	if member == nil {
+		return guilds.MemberResponse{}, false, nil
+	}
+	return *member, true, nil
+}
+
+// Filter - проверка текста сообщения перед отправкой: текст с заменами или ошибка, с которой сообщение отклоняется
+type Filter func(content string) (string, error)
+
+// NewWordFilter - замена слов из списка words звездочками. Слова сравниваются целиком без учета регистра.
+func NewWordFilter(words []string) Filter {
+	banned := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		banned[strings.ToLower(word)] = struct{}{}
+	}
+	return func(content string) (string, error) {
+		var b strings.Builder
+		var word []rune
+		flush := func() {
+			if _, ok := banned[strings.ToLower(string(word))]; ok {
+				b.WriteString(strings.Repeat("*", len(word)))
+			} else {
+				b.WriteString(string(word))
+			}
+			word = word[:0]
+		}
+		for _, r := range content {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				word = append(word, r)
+				continue
+			}
+			flush()
+			b.WriteRune(r)
+		}
+		flush()
+		return b.String(), nil
+	}
+}
+
+// LoadWordFilter - NewWordFilter со словами из файла path: слово на строку, пустые строки и строки с # пропускаются
+func LoadWordFilter(path string) (Filter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения списка слов: %w", err)
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" && !strings.HasPrefix(word, "#") {
+			words = append(words, word)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения списка слов: %w", err)
+	}
+	return NewWordFilter(words), nil
+}
+
+// Guard - ограничения сообщений пользователя во всех чатах: частота отправки и фильтр текста.
+// nil Guard пропускает все сообщения.
+type Guard struct {
+	limit  int           // сообщений за window, 0 - без ограничения
+	window time.Duration // окно ограничения частоты
+	filter Filter        // nil - без фильтра
+	sent   map[int][]time.Time
+	swept  time.Time // когда из sent последний раз удалялись пользователи без сообщений в окне
+	mu     sync.Mutex
+}
+
+// NewGuard - не больше limit сообщений пользователя за window, текст проверяется filter
+func NewGuard(limit int, window time.Duration, filter Filter) *Guard {
+	return &Guard{limit: limit, window: window, filter: filter, sent: make(map[int][]time.Time)}
+}
+
+// Check - текст сообщения пользователя userID после фильтра или ошибка для автора
+func (g *Guard) Check(userID int, content string) (string, error) {
+	if g == nil {
+		return content, nil
+	}
+	if !g.allow(userID, time.Now()) {
+		return "", fmt.Errorf("слишком часто: не больше %d сообщений за %s", g.limit, g.window)
+	}
+	if g.filter == nil {
+		return content, nil
+	}
+	return g.filter(content)
+}
+
+// allow - учет сообщения в скользящем окне, false - лимит исчерпан и сообщение не учитывается
+func (g *Guard) allow(userID int, now time.Time) bool {
+	if g.limit <= 0 {
+		return true
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.sweep(now)
+
+	sent := g.sent[userID]
+	for len(sent) > 0 && !now.Before(sent[0].Add(g.window)) {
+		sent = sent[1:]
+	}
+	if len(sent) >= g.limit {
+		g.sent[userID] = sent
+		return false
+	}
+	g.sent[userID] = append(sent, now)
+	return true
+}
+
+// sweep - удаление пользователей, у которых в окне не осталось сообщений, не чаще раза за window.
+// Без этого sent растет на каждого, кто когда-либо писал. Вызывается под блокировкой.
+func (g *Guard) sweep(now time.Time) {
+	if now.Sub(g.swept) < g.window {
+		return
+	}
+	g.swept = now
+	for userID, sent := range g.sent {
+		if len(sent) == 0 || !now.Before(sent[len(sent)-1].Add(g.window)) {
+			delete(g.sent, userID)
+		}
+	}
+}
+
+// sanction - действующая санкция участника в комнате гильдии. Ошибка хранилища не блокирует участника.
+func (s *ChatServer) sanction(roomID, userID int, kind string) (Sanction, bool) {
+	if roomID == lobbyID {
+		return Sanction{}, false
+	}
+	sanction, ok, err := s.store.Sanction(roomID, userID, kind)
+	if err != nil {
+		log.Printf("Ошибка чтения санкций комнаты %d: %v", roomID, err)
+		return Sanction{}, false
+	}
+	return sanction, ok && sanction.Active(time.Now())
+}
+
+// until - срок санкции для сообщения пользователю
+func until(s Sanction) string {
+	if s.Until.IsZero() {
+		return "бессрочно"
+	}
+	return "до " + s.Until.Local().Format("15:04 02.01.2006")
+}
+
+// moderator - имя участника targetID, если автор c может модерировать его в комнате гильдии roomID.
+// Игроком не из гильдии управляет любая роль с правом управлять другими:
+// так бан покинувшего гильдию действует после повторного вступления и его можно снять.
+func (s *ChatServer) moderator(ctx context.Context, c *client, roomID, targetID int) (string, error) {
+	if roomID == lobbyID {
+		return "", errors.New("в лобби нет модерации")
+	}
+	if s.roles == nil {
+		return "", errors.New("модерация не настроена на сервере")
+	}
+
+	self, ok, err := s.roles.Member(ctx, c.identity.Header, c.identity.UserID)
+	if err != nil {
+		log.Printf("Ошибка проверки роли пользователя %d: %v", c.identity.UserID, err)
+		return "", errors.New("сервис гильдий недоступен, попробуйте позже")
+	}
+	if !ok || self.GuildID != roomID {
+		return "", errors.New("вы не состоите в этой гильдии")
+	}
+
+	target, ok, err := s.roles.Member(ctx, c.identity.Header, targetID)
+	if err != nil {
+		log.Printf("Ошибка проверки роли пользователя %d: %v", targetID, err)
+		return "", errors.New("сервис гильдий недоступен, попробуйте позже")
+	}
+	if !ok || target.GuildID != roomID {
+		if len(self.Role.RolePromote) == 0 {
+			return "", errNoRights
+		}
+		return fmt.Sprintf("user%d", targetID), nil
+	}
+	if !self.Role.CanManage(target.Role.ID) {
+		return "", errNoRights
+	}
+	return target.UserName, nil
+}
+
+// moderate - мут, бан или их снятие. Участники комнаты получают Notice, подключения забаненного закрываются.
+func (s *ChatServer) moderate(ctx context.Context, c *client, roomID int, req chatRequest) {
+	if req.UserID == c.identity.UserID {
+		c.push(guild.Error{Type: guild.TypeError, Message: "нельзя применить к себе"})
+		return
+	}
+	if req.Minutes < 0 {
+		c.push(guild.Error{Type: guild.TypeError, Message: "срок должен быть положительным"})
+		return
+	}
+	name, err := s.moderator(ctx, c, roomID, req.UserID)
+	if err != nil {
+		c.push(guild.Error{Type: guild.TypeError, Message: err.Error()})
+		return
+	}
+
+	term := "бессрочно"
+	if req.Minutes > 0 {
+		term = fmt.Sprintf("на %d мин", req.Minutes)
+	}
+	var notice string
+	switch req.Type {
+	case guild.TypeMute, guild.TypeBan:
+		sanction := Sanction{RoomID: roomID, UserID: req.UserID, Kind: SanctionMute, By: c.identity.UserID}
+		notice = fmt.Sprintf("%s запретил %s писать в чат %s", c.identity.Username, name, term)
+		if req.Type == guild.TypeBan {
+			sanction.Kind = SanctionBan
+			notice = fmt.Sprintf("%s заблокировал %s в чате %s", c.identity.Username, name, term)
+		}
+		if req.Minutes > 0 {
+			sanction.Until = time.Now().Add(time.Duration(req.Minutes) * time.Minute)
+		}
+		err = s.store.SetSanction(sanction)
+	case guild.TypeUnmute:
+		notice = fmt.Sprintf("%s разрешил %s писать в чат", c.identity.Username, name)
+		err = s.store.RemoveSanction(roomID, req.UserID, SanctionMute)
+	case guild.TypeUnban:
+		notice = fmt.Sprintf("%s разблокировал %s в чате", c.identity.Username, name)
+		err = s.store.RemoveSanction(roomID, req.UserID, SanctionBan)
+	}
+	if err != nil {
+		log.Printf("Ошибка сохранения санкции в комнате %d: %v", roomID, err)
+		c.push(guild.Error{Type: guild.TypeError, Message: "санкция не сохранена, попробуйте позже"})
+		return
+	}
+	log.Printf("Moderation in room %d: %s", roomID, notice)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	room := s.room(roomID)
+	if req.Type == guild.TypeBan {
+		s.kick(room, req.UserID, "вы заблокированы в чате гильдии "+term)
+	}
+	for member := range room.clients {
+		member.push(guild.Notice{Type: guild.TypeNotice, Message: notice})
+	}
+}
+
+// delete - удаление сообщения автором или модератором, участники комнаты получают Deleted
+func (s *ChatServer) delete(ctx context.Context, c *client, roomID int, id string) {
+	msg, ok, err := s.store.Message(roomID, id)
+	if err != nil {
+		log.Printf("Ошибка чтения сообщения %s: %v", id, err)
+	}
+	if !ok {
+		c.push(guild.Error{Type: guild.TypeError, Message: "сообщение не найдено"})
+		return
+	}
+	if msg.UserId != c.identity.UserID {
+		if _, err := s.moderator(ctx, c, roomID, msg.UserId); err != nil {
+			c.push(guild.Error{Type: guild.TypeError, Message: err.Error()})
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.store.Delete(roomID, id); err != nil {
+		log.Printf("Ошибка удаления сообщения %s: %v", id, err)
+		c.push(guild.Error{Type: guild.TypeError, Message: "сообщение не удалено, попробуйте позже"})
+		return
+	}
+	for member := range s.room(roomID).clients {
+		member.push(guild.Deleted{Type: guild.TypeDeleted, Id: id, By: c.identity.Username})
+	}
+}
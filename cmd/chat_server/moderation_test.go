@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"lesta-start-battleship/cli/internal/api/auth"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+	"lesta-start-battleship/cli/internal/cli/clitest"
+)
+
+// packet - любой пакет чата гильдии
+type packet struct {
+	Type     string `json:"type"`
+	Message  string `json:"message"`
+	Content  string `json:"content"`
+	Id       string `json:"_id"`
+	By       string `json:"by"`
+	Username string `json:"username"`
+}
+
+// next - следующий пакет подключения
+func next(t *testing.T, conn *websocket.Conn) packet {
+	t.Helper()
+	var p packet
+	read(t, conn, &p)
+	return p
+}
+
+// login - заголовок Authorization сессии пользователя mock-сервера
+func login(t *testing.T, backend *clitest.Backend, username string) string {
+	t.Helper()
+	clients := backend.Clients(t)
+	if _, _, err := clients.AuthClient.Login(context.Background(), auth.LoginRequest{Username: username, Password: username}); err != nil {
+		t.Fatalf("ошибка входа %s: %v", username, err)
+	}
+	return clients.AuthClient.AuthHeader().Get("Authorization")
+}
+
+func TestChatServerModeration(t *testing.T) {
+	// роли WOLF: admiral - owner, управляет officer и cabin_boy; bosun - officer, управляет cabin_boy; cabin - cabin_boy
+	backend := clitest.NewBackend(t)
+	server := newServer(t, NewInsecureAuthenticator(), ChatOptions{History: 10, Roles: NewGuildRoles(backend.Endpoints.Guilds)})
+	admiralToken, bosunToken, cabinToken := login(t, backend, "admiral"), login(t, backend, "bosun"), login(t, backend, "cabin")
+
+	var history guild.ChatHistory
+	admiral, _ := dial(t, server, "/ws/guild/1/1", admiralToken)
+	read(t, admiral, &history)
+	bosun, _ := dial(t, server, "/ws/guild/1/2", bosunToken)
+	read(t, bosun, &history)
+	cabin, _ := dial(t, server, "/ws/guild/1/3", cabinToken)
+	read(t, cabin, &history)
+
+	bosun.WriteJSON(guild.Moderation{Type: guild.TypeMute, UserId: 1})
+	if p := next(t, bosun); p.Type != guild.TypeError || p.Message != errNoRights.Error() {
+		t.Errorf("мут владельца офицером: %+v", p)
+	}
+
+	admiral.WriteJSON(guild.Moderation{Type: guild.TypeMute, UserId: 2, Minutes: 10})
+	if p := next(t, cabin); p.Type != guild.TypeNotice || !strings.Contains(p.Message, "bosun") {
+		t.Errorf("уведомление о муте: %+v", p)
+	}
+	next(t, admiral)
+	next(t, bosun)
+	bosun.WriteJSON(guild.ChatMessage{Msg: "Протестую"})
+	if p := next(t, bosun); p.Type != guild.TypeError || !strings.Contains(p.Message, "запрещено писать") {
+		t.Errorf("сообщение под мутом: %+v", p)
+	}
+
+	// офицер удаляет сообщение юнги
+	cabin.WriteJSON(guild.ChatMessage{Msg: "Спам"})
+	var msg guild.ChatHistoryMessage
+	read(t, cabin, &msg)
+	read(t, admiral, &msg)
+	read(t, bosun, &msg)
+	bosun.WriteJSON(guild.Delete{Type: guild.TypeDelete, Id: msg.Id})
+	if p := next(t, cabin); p.Type != guild.TypeDeleted || p.Id != msg.Id || p.By != "user2" {
+		t.Errorf("удаление сообщения: %+v", p)
+	}
+	next(t, admiral)
+	next(t, bosun)
+	reconnected, _ := dial(t, server, "/ws/guild/1/1", admiralToken)
+	if read(t, reconnected, &history); len(history.Data) != 0 {
+		t.Errorf("история после удаления: %+v", history.Data)
+	}
+
+	// бан закрывает подключение и запрещает новое
+	admiral.WriteJSON(guild.Moderation{Type: guild.TypeBan, UserId: 3})
+	if p := next(t, cabin); p.Type != guild.TypeError || !strings.Contains(p.Message, "заблокированы") {
+		t.Errorf("бан участника: %+v", p)
+	}
+	cabin.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, data, err := cabin.ReadMessage(); err == nil {
+		t.Errorf("подключение забаненного открыто: %s", data)
+	}
+	if _, status := dial(t, server, "/ws/guild/1/3", cabinToken); status != http.StatusForbidden {
+		t.Errorf("подключение забаненного: статус %d", status)
+	}
+	next(t, admiral)
+	next(t, bosun)
+
+	admiral.WriteJSON(guild.Moderation{Type: guild.TypeUnban, UserId: 3})
+	next(t, admiral)
+	if _, status := dial(t, server, "/ws/guild/1/3", cabinToken); status != http.StatusSwitchingProtocols {
+		t.Errorf("подключение после разбана: статус %d", status)
+	}
+}
+
+func TestChatServerGuard(t *testing.T) {
+	server := newServer(t, NewInsecureAuthenticator(), ChatOptions{History: 10, Guard: NewGuard(2, time.Minute, NewWordFilter([]string{"Дурак"}))})
+	conn, _ := dial(t, server, "/ws/lobby/1", accessToken(t, "1", "admiral"))
+	var history guild.ChatHistory
+	read(t, conn, &history)
+
+	conn.WriteJSON(guild.ChatMessage{Msg: "сам ДУРАК, дураки"})
+	if p := next(t, conn); p.Content != "сам *****, дураки" {
+		t.Errorf("фильтр слов: %+v", p)
+	}
+	conn.WriteJSON(guild.ChatMessage{Msg: "два"})
+	next(t, conn)
+	conn.WriteJSON(guild.ChatMessage{Msg: "три"})
+	if p := next(t, conn); p.Type != guild.TypeError || !strings.Contains(p.Message, "слишком часто") {
+		t.Errorf("третье сообщение за окно: %+v", p)
+	}
+
+	// в лобби нет модерации
+	conn.WriteJSON(guild.Moderation{Type: guild.TypeMute, UserId: 2})
+	if p := next(t, conn); p.Type != guild.TypeError || p.Message != "в лобби нет модерации" {
+		t.Errorf("мут в лобби: %+v", p)
+	}
+}
+
+// TestGuardForgetsIdleUsers - пользователи без сообщений в окне не копятся в Guard
+func TestGuardForgetsIdleUsers(t *testing.T) {
+	g := NewGuard(2, time.Minute, nil)
+	start := time.Now()
+	for userID := 1; userID <= 100; userID++ {
+		g.allow(userID, start)
+	}
+	if !g.allow(1, start.Add(30*time.Second)) || g.allow(1, start.Add(40*time.Second)) {
+		t.Fatal("третье сообщение за окно должно быть отклонено")
+	}
+
+	// через окно после последнего сообщения пользователь удаляется
+	g.allow(200, start.Add(time.Minute+10*time.Second))
+	if len(g.sent) != 2 {
+		t.Errorf("после окна осталось %d пользователей, ожидались 1 и 200", len(g.sent))
+	}
+	g.allow(200, start.Add(3*time.Minute))
+	if len(g.sent) != 1 {
+		t.Errorf("осталось %d пользователей, ожидался только 200", len(g.sent))
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/direct"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+)
+
+// Виды санкций модерации
+const (
+	SanctionMute = "mute" // участник не может писать в комнату
+	SanctionBan  = "ban"  // участник не может подключиться к комнате
+)
+
+// Sanction - мут или бан участника в комнате гильдии
+type Sanction struct {
+	RoomID int       `json:"room_id"`
+	UserID int       `json:"user_id"`
+	Kind   string    `json:"kind"`  // SanctionMute или SanctionBan
+	Until  time.Time `json:"until"` // нулевое время - бессрочно
+	By     int       `json:"by"`    // модератор
+}
+
+// Active - санкция действует в момент now
+func (s Sanction) Active(now time.Time) bool {
+	return s.Until.IsZero() || now.Before(s.Until)
+}
+
+// Store - хранилище переписки и санкций чат-сервера.
+// Сообщения комнаты упорядочены по ID: ID выдает NextID и записывает в виде %024x.
+type Store interface {
+	// NextID - следующий ID сообщения, не повторяется после перезапуска
+	NextID() (uint64, error)
+	// Append - сообщение комнаты, хранятся только последние keep сообщений
+	Append(roomID int, msg guild.ChatHistoryMessage, keep int) error
+	// Latest - последние limit сообщений комнаты, старые первыми
+	Latest(roomID, limit int) ([]guild.ChatHistoryMessage, error)
+	// Before - до limit сообщений комнаты старше сообщения id, неизвестный id дает пустой результат
+	Before(roomID int, id string, limit int) ([]guild.ChatHistoryMessage, error)
+	// Message - сообщение комнаты по ID
+	Message(roomID int, id string) (guild.ChatHistoryMessage, bool, error)
+	// Delete - удаление сообщения комнаты
+	Delete(roomID int, id string) error
+
+	// AppendDirect - личное сообщение
+	AppendDirect(msg direct.Message) error
+	// Direct - все личные сообщения, старые первыми
+	Direct() ([]direct.Message, error)
+
+	// SetSanction - санкция заменяет прежнюю того же вида
+	SetSanction(s Sanction) error
+	// Sanction - санкция вида kind участника userID в комнате, в том числе истекшая
+	Sanction(roomID, userID int, kind string) (Sanction, bool, error)
+	// RemoveSanction - снятие санкции
+	RemoveSanction(roomID, userID int, kind string) error
+
+	Close() error
+}
+
+// sanctionKey - санкция в хранилище
+type sanctionKey struct {
+	roomID, userID int
+	kind           string
+}
+
+// memoryStore - хранилище в памяти, переписка теряется при перезапуске
+type memoryStore struct {
+	nextID    uint64
+	rooms     map[int][]guild.ChatHistoryMessage
+	direct    []direct.Message
+	sanctions map[sanctionKey]Sanction
+	mu        sync.Mutex
+}
+
+// NewMemoryStore - хранилище в памяти
+func NewMemoryStore() Store {
+	return &memoryStore{
+		rooms:     make(map[int][]guild.ChatHistoryMessage),
+		sanctions: make(map[sanctionKey]Sanction),
+	}
+}
+
+func (s *memoryStore) NextID() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	return s.nextID, nil
+}
+
+func (s *memoryStore) Append(roomID int, msg guild.ChatHistoryMessage, keep int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages := append(s.rooms[roomID], msg)
+	if len(messages) > keep {
+		messages = append([]guild.ChatHistoryMessage(nil), messages[len(messages)-keep:]...)
+	}
+	s.rooms[roomID] = messages
+	return nil
+}
+
+func (s *memoryStore) Latest(roomID, limit int) ([]guild.ChatHistoryMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages := s.rooms[roomID]
+	return append([]guild.ChatHistoryMessage{}, messages[max(len(messages)-limit, 0):]...), nil
+}
+
+func (s *memoryStore) Before(roomID int, id string, limit int) ([]guild.ChatHistoryMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages := s.rooms[roomID]
+	for i, m := range messages {
+		if m.Id == id {
+			return append([]guild.ChatHistoryMessage{}, messages[max(i-limit, 0):i]...), nil
+		}
+	}
+	return []guild.ChatHistoryMessage{}, nil
+}
+
+func (s *memoryStore) Message(roomID int, id string) (guild.ChatHistoryMessage, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range s.rooms[roomID] {
+		if m.Id == id {
+			return m, true, nil
+		}
+	}
+	return guild.ChatHistoryMessage{}, false, nil
+}
+
+func (s *memoryStore) Delete(roomID int, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages := s.rooms[roomID]
+	for i, m := range messages {
+		if m.Id == id {
+			s.rooms[roomID] = append(messages[:i:i], messages[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) AppendDirect(msg direct.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.direct = append(s.direct, msg)
+	return nil
+}
+
+func (s *memoryStore) Direct() ([]direct.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]direct.Message(nil), s.direct...), nil
+}
+
+func (s *memoryStore) SetSanction(sanction Sanction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sanctions[sanctionKey{sanction.RoomID, sanction.UserID, sanction.Kind}] = sanction
+	return nil
+}
+
+func (s *memoryStore) Sanction(roomID, userID int, kind string) (Sanction, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sanction, ok := s.sanctions[sanctionKey{roomID, userID, kind}]
+	return sanction, ok, nil
+}
+
+func (s *memoryStore) RemoveSanction(roomID, userID int, kind string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sanctions, sanctionKey{roomID, userID, kind})
+	return nil
+}
+
+func (s *memoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/direct"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+)
+
+// appendMessages - сообщения комнаты 1 с текстами contents, хранятся последние keep
+func appendMessages(t *testing.T, store Store, keep int, contents ...string) []guild.ChatHistoryMessage {
+	t.Helper()
+	var messages []guild.ChatHistoryMessage
+	for _, content := range contents {
+		id, err := store.NextID()
+		if err != nil {
+			t.Fatalf("ошибка выдачи ID: %v", err)
+		}
+		msg := guild.ChatHistoryMessage{Id: fmt.Sprintf("%024x", id), GuildId: 1, UserId: 1, Username: "admiral", Content: content}
+		if err := store.Append(1, msg, keep); err != nil {
+			t.Fatalf("ошибка сохранения сообщения: %v", err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// contents - тексты сообщений по порядку
+func contents(messages []guild.ChatHistoryMessage) string {
+	texts := make([]string, len(messages))
+	for i, m := range messages {
+		texts[i] = m.Content
+	}
+	return fmt.Sprint(texts)
+}
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(*testing.T) Store { return NewMemoryStore() },
+		"bolt": func(t *testing.T) Store {
+			store, err := OpenBoltStore(filepath.Join(t.TempDir(), "chat.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return store
+		},
+	}
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := open(t)
+			defer store.Close()
+
+			// сообщения сверх keep вытесняются самыми старыми
+			messages := appendMessages(t, store, 3, "раз", "два", "три", "четыре")
+			if latest, err := store.Latest(1, 10); err != nil || contents(latest) != "[два три четыре]" {
+				t.Fatalf("последние сообщения: %v, %v", contents(latest), err)
+			}
+			if latest, _ := store.Latest(1, 2); contents(latest) != "[три четыре]" {
+				t.Errorf("последние 2 сообщения: %v", contents(latest))
+			}
+			if before, _ := store.Before(1, messages[3].Id, 1); contents(before) != "[три]" {
+				t.Errorf("сообщение до четвертого: %v", contents(before))
+			}
+			if before, _ := store.Before(1, messages[0].Id, 10); len(before) != 0 {
+				t.Errorf("сообщения до вытесненного: %v", contents(before))
+			}
+			if latest, _ := store.Latest(2, 10); len(latest) != 0 {
+				t.Errorf("сообщения другой комнаты: %v", contents(latest))
+			}
+
+			if err := store.Delete(1, messages[2].Id); err != nil {
+				t.Fatalf("ошибка удаления: %v", err)
+			}
+			if _, ok, _ := store.Message(1, messages[2].Id); ok {
+				t.Error("удаленное сообщение найдено")
+			}
+			if m, ok, _ := store.Message(1, messages[3].Id); !ok || m.Content != "четыре" {
+				t.Errorf("сообщение по ID: %+v, %v", m, ok)
+			}
+			if latest, _ := store.Latest(1, 10); contents(latest) != "[два четыре]" {
+				t.Errorf("сообщения после удаления: %v", contents(latest))
+			}
+
+			mute := Sanction{RoomID: 1, UserID: 2, Kind: SanctionMute, Until: time.Now().Add(-time.Minute).UTC(), By: 1}
+			if err := store.SetSanction(mute); err != nil {
+				t.Fatalf("ошибка сохранения санкции: %v", err)
+			}
+			if got, ok, _ := store.Sanction(1, 2, SanctionMute); !ok || got.By != 1 || got.Active(time.Now()) {
+				t.Errorf("истекший мут: %+v, %v", got, ok)
+			}
+			if _, ok, _ := store.Sanction(1, 2, SanctionBan); ok {
+				t.Error("бан без санкции")
+			}
+			store.RemoveSanction(1, 2, SanctionMute)
+			if _, ok, _ := store.Sanction(1, 2, SanctionMute); ok {
+				t.Error("снятый мут найден")
+			}
+		})
+	}
+}
+
+func TestBoltStoreRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chat.db")
+	store, err := OpenBoltStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendMessages(t, store, 10, "до перезапуска")
+	store.AppendDirect(direct.Message{Type: direct.TypeMessage, Id: fmt.Sprintf("%024x", 100), FromId: 1, FromName: "admiral", ToId: 2, ToName: "bosun", Content: "лично"})
+	store.SetSanction(Sanction{RoomID: 1, UserID: 3, Kind: SanctionBan, By: 1})
+	store.Close()
+
+	store, err = OpenBoltStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	// ID продолжаются, новые сообщения идут после сохраненных
+	appendMessages(t, store, 10, "после перезапуска")
+	if latest, _ := store.Latest(1, 10); contents(latest) != "[до перезапуска после перезапуска]" {
+		t.Errorf("история после перезапуска: %v", contents(latest))
+	}
+	if messages, err := store.Direct(); err != nil || len(messages) != 1 || messages[0].Content != "лично" {
+		t.Errorf("личные сообщения после перезапуска: %+v, %v", messages, err)
+	}
+	if ban, ok, _ := store.Sanction(1, 3, SanctionBan); !ok || !ban.Active(time.Now()) {
+		t.Errorf("бессрочный бан после перезапуска: %+v, %v", ban, ok)
+	}
+
+	// сервер личных сообщений восстанавливает переписку и имена
+	server, err := NewDirectServer(NewInsecureAuthenticator(), store, ChatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items := server.conversations(2); len(items) != 1 || items[0].Username != "admiral" {
+		t.Errorf("переписки после перезапуска: %+v", items)
+	}
+}
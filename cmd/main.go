@@ -1,13 +1,37 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"lesta-start-battleship/cli/internal/app"
+	"lesta-start-battleship/cli/internal/cli/commands"
+	"lesta-start-battleship/cli/internal/config"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Println("Использование: cli [флаги] [команда]")
+		config.Usage(os.Stdout)
+		fmt.Println()
+		fmt.Println("Без команды запускается интерактивный интерфейс. Список команд: cli help")
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if len(cfg.Args) > 0 {
+		os.Exit(runCommand(cfg))
+	}
+
 	file, err := os.OpenFile("logs/app.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		log.Fatal(err)
@@ -17,14 +41,45 @@ func main() {
 	// Устанавливаем вывод логов в файл
 	log.SetOutput(file)
 
-	app, err := app.New()
+	app, err := app.New(cfg)
 	if err != nil {
 		log.Fatalf("Ошибка инициализации: %v", err)
 	}
 
+	log.Printf("Окружение: %s", cfg.Environment)
 	fmt.Println("CLI клиент запущен.")
 	if err := app.Run(); err != nil {
 		log.Printf("Ошибка выполнения: %v", err)
 		os.Exit(1)
 	}
 }
+
+// runCommand - неинтерактивное выполнение подкоманды.
+// Коды возврата: 0 - успех, 1 - ошибка выполнения, 2 - ошибка вызова.
+func runCommand(cfg *config.Config) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	clients, err := app.NewClients(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	env := &commands.Env{
+		Clients: clients,
+		Stdin:   os.Stdin,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+	}
+
+	if err := commands.Run(ctx, env, cfg.Args); err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		var usageErr *commands.UsageError
+		if errors.As(err, &usageErr) {
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"lesta-start-battleship/cli/internal/mockserver"
+	"log"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8090", "адрес сервера")
+	seedPath := flag.String("seed", "", "JSON файл с начальными данными, по умолчанию встроенные")
+	accessTTL := flag.Duration("access-ttl", mockserver.DefaultOptions().AccessTTL, "время жизни access token")
+	botDelay := flag.Duration("bot-delay", mockserver.DefaultOptions().BotDelay, "задержка подбора бота в матчмейкинге")
+	noAuditLog := flag.Bool("no-audit-log", false, "без API журнала действий гильдий")
+	flag.Parse()
+
+	seed, err := mockserver.DefaultSeed()
+	if *seedPath != "" {
+		seed, err = mockserver.LoadSeed(*seedPath)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := mockserver.DefaultOptions()
+	opts.AccessTTL = *accessTTL
+	opts.BotDelay = *botDelay
+	opts.NoAuditLog = *noAuditLog
+
+	server := mockserver.New(mockserver.NewStore(seed), opts)
+
+	log.Printf("Mock-сервер запущен на %s (окружение клиента: --env local)", *addr)
+	if err := http.ListenAndServe(*addr, server); err != nil {
+		log.Fatal(err)
+	}
+}
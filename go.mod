@@ -8,6 +8,8 @@ require (
 	github.com/golang-jwt/jwt/v5 v5.2.2
 	github.com/gorilla/websocket v1.5.3
 	github.com/lesta-battleship/matchmaking v0.2.0
+	go.etcd.io/bbolt v1.4.3
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 replace github.com/lesta-battleship/server-core => github.com/lesta-start-battleship/server-core v1.0.0
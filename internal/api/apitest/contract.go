@@ -0,0 +1,460 @@
+// Package apitest - контрактные тесты клиентов API.
+//
+// Контракт метода клиента - записанный обмен запросами с сервером: метод, путь, query параметры,
+// заголовки и тело каждого запроса, ответ сервера и результат, который вернул метод.
+// Контракты хранятся в testdata/contracts/<имя>.json пакета клиента.
+//
+// Обычный запуск воспроизводит ответы из контракта и проверяет, что клиент отправляет те же запросы,
+// декодирует ответ в тот же результат, а каждое поле ответа есть в модели и наоборот.
+// Запуск с флагом -update записывает контракты заново по обмену с mock-сервером.
+// Такие контракты не подтверждают совместимость с настоящим API: mock-сервер строит маршруты
+// из тех же констант путей, что и клиенты, поэтому они ловят только регрессии самих клиентов.
+// Флаг есть только в пакетах с контрактами, поэтому они перечисляются явно (just update-contracts):
+//
+//	go test ./internal/api/auth ./internal/api/guilds ./internal/api/inventory ./internal/api/scoreboard ./internal/api/shop -update
+//
+// С флагом -record-env контракты записываются по обмену с сервером окружения, чтобы заметить расхождения
+// mock-сервера с настоящим API; только такие контракты проверяют клиентов против backend. Адреса берутся из файла конфигурации и переменных BATTLESHIP_*,
+// логин и пароль пользователя контракта - из переменной APITEST_USER_<ИМЯ>=логин:пароль.
+// Токены настоящего сервера в контрактах заменяются токенами с теми же claims и тестовой подписью:
+//
+//	APITEST_USER_ADMIRAL=captain:secret go test ./internal/api/shop -update -record-env staging
+package apitest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"lesta-start-battleship/cli/internal/api/auth"
+	"lesta-start-battleship/cli/internal/config"
+	"lesta-start-battleship/cli/internal/mockserver"
+	"lesta-start-battleship/cli/storage/token"
+)
+
+// update - перезапись контрактов по обмену с mock-сервером
+var update = flag.Bool("update", false, "записать контракты заново по обмену с mock-сервером (не проверяет совместимость с настоящим API, см. -record-env)")
+
+// requestHeaders - заголовки запроса, входящие в контракт
+var requestHeaders = []string{"Accept", "Authorization", "Content-Type", "Refresh-Token"}
+
+// responseHeaders - заголовки ответа, которые читают клиенты
+var responseHeaders = []string{"Authorization", "Refresh-Token"}
+
+// Request - запрос клиента
+type Request struct {
+	Method string            `json:"method"`
+	Path   string            `json:"path"`
+	Query  map[string]string `json:"query,omitempty"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   json.RawMessage   `json:"body,omitempty"`
+}
+
+// Response - ответ сервера. Тело не в формате JSON хранится в Text.
+type Response struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   json.RawMessage   `json:"body,omitempty"`
+	Text   string            `json:"text,omitempty"`
+}
+
+// Interaction - запрос и ответ на него
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Session - токены пользователя, под которым выполнялись запросы
+type Session struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Result - значение или ошибка, которые вернул метод клиента
+type Result struct {
+	Value json.RawMessage `json:"value,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// Fixture - контракт метода клиента
+type Fixture struct {
+	Session      *Session      `json:"session,omitempty"`
+	Interactions []Interaction `json:"interactions"`
+	Result       Result        `json:"result"`
+}
+
+// Case - описание контракта
+type Case struct {
+	Name string // имя файла контракта без расширения
+	User string // пользователь mock-сервера, под которым выполняется вызов; пусто - без сессии
+
+	// Call - вызов методов клиента, результат сравнивается с контрактом
+	Call func(ctx context.Context, endpoints config.Endpoints, tokens *token.Storage) (any, error)
+
+	// Models - модели тел ответов по порядку обменов, nil - тело не проверяется
+	Models []any
+}
+
+// Run - проверка или запись контрактов
+func Run(t *testing.T, cases []Case) {
+	t.Helper()
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			path := filepath.Join("testdata", "contracts", c.Name+".json")
+			if *update {
+				record(t, c, path)
+			}
+			replay(t, c, path)
+		})
+	}
+}
+
+// record - запись контракта по обмену с mock-сервером со встроенными данными или с сервером окружения recordEnv
+func record(t *testing.T, c Case, path string) {
+	t.Helper()
+
+	rec := &recorder{handler: source(t)}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	ctx := context.Background()
+	endpoints := mockserver.Endpoints(server.URL)
+	tokens := token.NewStorage()
+
+	var fixture Fixture
+	if c.User != "" {
+		authClient, err := auth.NewClient(endpoints.Auth, tokens)
+		if err != nil {
+			t.Fatalf("ошибка создания клиента авторизации: %v", err)
+		}
+		username, password, err := credentials(c.User)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := authClient.Login(ctx, auth.LoginRequest{Username: username, Password: password}); err != nil {
+			t.Fatalf("ошибка входа %s: %v", c.User, err)
+		}
+		access, refresh := tokens.GetToken()
+		fixture.Session = &Session{AccessToken: access, RefreshToken: refresh}
+	}
+
+	rec.start()
+	_, _ = c.Call(ctx, endpoints, tokens)
+	fixture.Interactions = rec.stop()
+
+	// результат берется из воспроизведения, чтобы ответы отдавались в том же виде, что и при проверке
+	fixture.Result = play(t, c, fixture)
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		t.Fatalf("ошибка кодирования контракта: %v", err)
+	}
+	if *recordEnv != "" {
+		access, refresh := tokens.GetToken()
+		secrets := []string{access, refresh}
+		if fixture.Session != nil {
+			secrets = append(secrets, fixture.Session.AccessToken, fixture.Session.RefreshToken)
+		}
+		data = redact(data, secrets...)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("ошибка создания каталога контрактов: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		t.Fatalf("ошибка записи контракта: %v", err)
+	}
+}
+
+// source - обработчик, с которым записывается контракт
+func source(t *testing.T) http.Handler {
+	t.Helper()
+
+	if *recordEnv != "" {
+		endpoints, err := liveEndpoints()
+		if err != nil {
+			t.Fatalf("ошибка конфигурации окружения %s: %v", *recordEnv, err)
+		}
+		proxy, err := newProxy(endpoints)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return proxy
+	}
+
+	seed, err := mockserver.DefaultSeed()
+	if err != nil {
+		t.Fatalf("ошибка загрузки данных mock-сервера: %v", err)
+	}
+	opts := mockserver.DefaultOptions()
+	opts.Logger = log.New(io.Discard, "", 0)
+	return mockserver.New(mockserver.NewStore(seed), opts)
+}
+
+// replay - проверка клиента на ответах из контракта
+func replay(t *testing.T, c Case, path string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ошибка чтения контракта (запишите его с флагом -update): %v", err)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatalf("ошибка разбора контракта %s: %v", path, err)
+	}
+
+	for i, model := range c.Models {
+		if model == nil || i >= len(fixture.Interactions) {
+			continue
+		}
+		for _, problem := range Shape(fixture.Interactions[i].Response.Body, model) {
+			t.Errorf("обмен %d, ответ %s %s: %s", i+1, fixture.Interactions[i].Request.Method,
+				fixture.Interactions[i].Request.Path, problem)
+		}
+	}
+
+	got := play(t, c, fixture)
+	if got.Error != fixture.Result.Error {
+		t.Errorf("ошибка метода: %q, в контракте: %q", got.Error, fixture.Result.Error)
+	}
+	if !jsonEqual(got.Value, fixture.Result.Value) {
+		t.Errorf("результат метода отличается от контракта\n--- получено ---\n%s\n--- в контракте ---\n%s",
+			got.Value, fixture.Result.Value)
+	}
+}
+
+// play - вызов методов клиента на ответах из контракта
+func play(t *testing.T, c Case, fixture Fixture) Result {
+	t.Helper()
+
+	player := &player{t: t, interactions: fixture.Interactions}
+	server := httptest.NewServer(player)
+	defer server.Close()
+
+	tokens := token.NewStorage()
+	if fixture.Session != nil {
+		tokens.SetTokens(fixture.Session.AccessToken, fixture.Session.RefreshToken)
+	}
+
+	value, err := c.Call(context.Background(), mockserver.Endpoints(server.URL), tokens)
+	if left := player.left(); left > 0 {
+		t.Errorf("клиент не выполнил %d запрос(ов) из контракта", left)
+	}
+	return newResult(t, value, err)
+}
+
+// newResult - результат метода в формате контракта
+func newResult(t *testing.T, value any, err error) Result {
+	t.Helper()
+
+	if err != nil {
+		return Result{Error: err.Error()}
+	}
+	if value == nil {
+		return Result{}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("ошибка кодирования результата: %v", err)
+	}
+	return Result{Value: data}
+}
+
+// recorder - запись обменов с обработчиком
+type recorder struct {
+	handler http.Handler
+
+	mu           sync.Mutex
+	recording    bool
+	interactions []Interaction
+}
+
+func (r *recorder) start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recording = true
+}
+
+func (r *recorder) stop() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recording = false
+	return append([]Interaction{}, r.interactions...)
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	resp := httptest.NewRecorder()
+	r.handler.ServeHTTP(resp, req)
+
+	for key, values := range resp.Header() {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(resp.Code)
+	_, _ = w.Write(resp.Body.Bytes())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.recording {
+		return
+	}
+
+	response := Response{Status: resp.Code, Header: headers(resp.Header(), responseHeaders)}
+	if data := bytes.TrimSpace(resp.Body.Bytes()); json.Valid(data) {
+		response.Body = compact(data)
+	} else {
+		response.Text = string(data)
+	}
+
+	r.interactions = append(r.interactions, Interaction{
+		Request:  newRequest(req, body),
+		Response: response,
+	})
+}
+
+// player - воспроизведение ответов контракта с проверкой запросов
+type player struct {
+	t *testing.T
+
+	mu           sync.Mutex
+	next         int
+	interactions []Interaction
+}
+
+func (p *player) left() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.interactions) - p.next
+}
+
+func (p *player) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	got := newRequest(req, body)
+
+	p.mu.Lock()
+	if p.next >= len(p.interactions) {
+		p.mu.Unlock()
+		p.t.Errorf("запрос вне контракта: %s %s", got.Method, got.Path)
+		http.Error(w, "запрос вне контракта", http.StatusNotImplemented)
+		return
+	}
+	i := p.next
+	want := p.interactions[i]
+	p.next++
+	p.mu.Unlock()
+
+	for _, problem := range compareRequests(got, want.Request) {
+		p.t.Errorf("обмен %d, запрос %s %s: %s", i+1, want.Request.Method, want.Request.Path, problem)
+	}
+
+	for key, value := range want.Response.Header {
+		w.Header().Set(key, value)
+	}
+	body = compact(want.Response.Body)
+	if len(body) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		body = []byte(want.Response.Text)
+	}
+	w.WriteHeader(want.Response.Status)
+	_, _ = w.Write(body)
+}
+
+// newRequest - запрос в формате контракта
+func newRequest(req *http.Request, body []byte) Request {
+	r := Request{
+		Method: req.Method,
+		Path:   req.URL.EscapedPath(),
+		Header: headers(req.Header, requestHeaders),
+	}
+	if query := req.URL.Query(); len(query) > 0 {
+		r.Query = make(map[string]string, len(query))
+		for key := range query {
+			r.Query[key] = query.Get(key)
+		}
+	}
+	if data := bytes.TrimSpace(body); len(data) > 0 {
+		r.Body = compact(data)
+	}
+	return r
+}
+
+// compareRequests - расхождения запроса клиента с контрактом
+func compareRequests(got, want Request) []string {
+	var problems []string
+	if got.Method != want.Method {
+		problems = append(problems, "метод "+got.Method+", в контракте "+want.Method)
+	}
+	if got.Path != want.Path {
+		problems = append(problems, "путь "+got.Path+", в контракте "+want.Path)
+	}
+	if !reflect.DeepEqual(nonNil(got.Query), nonNil(want.Query)) {
+		problems = append(problems, "query "+format(got.Query)+", в контракте "+format(want.Query))
+	}
+	if !reflect.DeepEqual(nonNil(got.Header), nonNil(want.Header)) {
+		problems = append(problems, "заголовки "+format(got.Header)+", в контракте "+format(want.Header))
+	}
+	if !jsonEqual(got.Body, want.Body) {
+		problems = append(problems, "тело "+string(got.Body)+", в контракте "+string(want.Body))
+	}
+	return problems
+}
+
+// headers - значения заголовков names, которые заданы в h
+func headers(h http.Header, names []string) map[string]string {
+	var result map[string]string
+	for _, name := range names {
+		if value := h.Get(name); value != "" {
+			if result == nil {
+				result = make(map[string]string)
+			}
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// jsonEqual - равенство JSON документов без учета форматирования и порядка ключей
+func jsonEqual(a, b json.RawMessage) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func compact(data []byte) json.RawMessage {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return data
+	}
+	return buf.Bytes()
+}
+
+func nonNil(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
+
+func format(m map[string]string) string {
+	data, _ := json.Marshal(nonNil(m))
+	return string(data)
+}
@@ -0,0 +1,111 @@
+package apitest
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"lesta-start-battleship/cli/internal/config"
+	"lesta-start-battleship/cli/internal/mockserver"
+)
+
+// recordEnv - окружение настоящего сервера, с которым записываются контракты вместо mock-сервера
+var recordEnv = flag.String("record-env", "", "с -update: записать контракты по обмену с сервером окружения (prod, staging, из файла конфигурации)")
+
+// EnvVarUserPrefix - префикс переменной окружения с логином и паролем пользователя контракта
+// для записи с настоящим сервером: APITEST_USER_ADMIRAL=login:password
+const EnvVarUserPrefix = "APITEST_USER_"
+
+// liveEndpoints - адреса сервисов окружения recordEnv с учетом файла конфигурации и переменных BATTLESHIP_*
+func liveEndpoints() (config.Endpoints, error) {
+	cfg, err := config.Load(nil)
+	if err != nil {
+		return config.Endpoints{}, err
+	}
+	return cfg.ForEnvironment(*recordEnv)
+}
+
+// credentials - логин и пароль пользователя user контракта.
+// Пользователи mock-сервера входят с паролем, равным имени; для настоящего сервера нужна переменная окружения.
+func credentials(user string) (string, string, error) {
+	if *recordEnv == "" {
+		return user, user, nil
+	}
+	name := EnvVarUserPrefix + strings.ToUpper(user)
+	login, password, ok := strings.Cut(os.Getenv(name), ":")
+	if !ok || login == "" {
+		return "", "", fmt.Errorf("для записи с окружением %s задайте %s=логин:пароль", *recordEnv, name)
+	}
+	return login, password, nil
+}
+
+// route - префикс пути сервиса на mock-сервере и адрес этого сервиса на настоящем сервере
+type route struct {
+	prefix string
+	target *url.URL
+}
+
+// newProxy - обработчик с путями mock-сервера, который пересылает запросы сервисам endpoints.
+// Так записанные пути совпадают с путями при воспроизведении на адресах mockserver.Endpoints.
+func newProxy(endpoints config.Endpoints) (http.Handler, error) {
+	mock := mockserver.Endpoints("http://mock")
+	pairs := [][2]string{
+		{mock.Auth, endpoints.Auth},
+		{mock.Guilds, endpoints.Guilds},
+		{mock.Inventory, endpoints.Inventory},
+		{mock.Scoreboard, endpoints.Scoreboard},
+		{mock.Shop, endpoints.Shop},
+	}
+
+	routes := make([]route, 0, len(pairs))
+	for _, pair := range pairs {
+		from, err := url.Parse(pair[0])
+		if err != nil {
+			return nil, err
+		}
+		target, err := url.Parse(pair[1])
+		if err != nil {
+			return nil, fmt.Errorf("некорректный адрес сервиса %q: %w", pair[1], err)
+		}
+		routes = append(routes, route{prefix: from.Path, target: target})
+	}
+	// сервис авторизации на mock-сервере обслуживает корень, поэтому более длинные префиксы проверяются первыми
+	sort.Slice(routes, func(i, j int) bool { return len(routes[i].prefix) > len(routes[j].prefix) })
+
+	return &httputil.ReverseProxy{Rewrite: func(r *httputil.ProxyRequest) {
+		for _, route := range routes {
+			if rest, ok := strings.CutPrefix(r.In.URL.Path, route.prefix); ok {
+				r.Out.URL.Path, r.Out.URL.RawPath = "/"+rest, ""
+				r.SetURL(route.target)
+				return
+			}
+		}
+	}}, nil
+}
+
+// redact - замена токенов настоящего сервера в контракте токенами с теми же claims и тестовой подписью
+func redact(data []byte, tokens ...string) []byte {
+	text := string(data)
+	for _, tok := range tokens {
+		raw := strings.TrimSpace(strings.TrimPrefix(tok, "Bearer "))
+		if raw == "" || !strings.Contains(text, raw) {
+			continue
+		}
+		claims := jwt.MapClaims{}
+		replacement := "redacted-token"
+		if _, _, err := jwt.NewParser().ParseUnverified(raw, claims); err == nil {
+			if signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("apitest")); err == nil {
+				replacement = signed
+			}
+		}
+		text = strings.ReplaceAll(text, raw, replacement)
+	}
+	return []byte(text)
+}
@@ -0,0 +1,62 @@
+package apitest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"lesta-start-battleship/cli/internal/config"
+)
+
+func TestProxyRoutes(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.RequestURI()))
+	}))
+	defer backend.Close()
+
+	proxy, err := newProxy(config.Endpoints{
+		Auth:       backend.URL + "/api/auth/",
+		Guilds:     backend.URL + "/api/guilds/",
+		Inventory:  backend.URL + "/api/inventory/",
+		Scoreboard: backend.URL + "/api/scoreboard/",
+		Shop:       backend.URL + "/api/shop/",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{
+		"/auth/login/":                 "/api/auth/auth/login/",
+		"/guild/member/member/3":       "/api/guilds/member/member/3",
+		"/shop/item/?limit=10":         "/api/shop/item/?limit=10",
+		"/scoreboard/users/?page=2":    "/api/scoreboard/users/?page=2",
+		"/inventory/user_inventory/1/": "/api/inventory/user_inventory/1/",
+	} {
+		resp := httptest.NewRecorder()
+		proxy.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, nil))
+		if got := resp.Body.String(); got != want {
+			t.Errorf("%s: запрос к серверу %s, ожидалось %s", path, got, want)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "42"}).SignedString([]byte("server-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := redact([]byte(`{"access_token": "`+signed+`", "header": "Bearer `+signed+`", "refresh": "opaque"}`), "Bearer "+signed, "opaque")
+	if strings.Contains(string(data), signed) || strings.Contains(string(data), "opaque") {
+		t.Fatalf("токены остались в контракте: %s", data)
+	}
+
+	claims := jwt.MapClaims{}
+	start := strings.Index(string(data), `"access_token": "`) + len(`"access_token": "`)
+	replaced := string(data)[start : start+strings.Index(string(data)[start:], `"`)]
+	if _, err := jwt.NewParser().ParseWithClaims(replaced, claims, func(*jwt.Token) (any, error) { return []byte("apitest"), nil }); err != nil || claims["sub"] != "42" {
+		t.Errorf("замена токена: %s, claims %v, %v", replaced, claims, err)
+	}
+}
@@ -0,0 +1,140 @@
+package apitest
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var (
+	jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// field - поле модели в JSON
+type field struct {
+	typ      reflect.Type
+	optional bool // omitempty или указатель: поле может отсутствовать в ответе
+}
+
+// Shape - расхождения JSON документа data с моделью model.
+// Поле ответа, которого нет в модели, и обязательное поле модели, которого нет в ответе,
+// после декодирования молча превращаются в нулевые значения.
+func Shape(data json.RawMessage, model any) []string {
+	if len(data) == 0 {
+		return []string{"пустое тело ответа"}
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []string{fmt.Sprintf("тело ответа не JSON: %v", err)}
+	}
+
+	var problems []string
+	shape("$", doc, reflect.TypeOf(model), &problems)
+	return problems
+}
+
+func shape(path string, doc any, typ reflect.Type, problems *[]string) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if doc == nil || isLeaf(typ) {
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			*problems = append(*problems, fmt.Sprintf("%s: ожидался объект %s", path, typ.Name()))
+			return
+		}
+
+		fields := jsonFields(typ)
+		for _, name := range sortedKeys(obj) {
+			if _, ok := fields[name]; !ok {
+				*problems = append(*problems, fmt.Sprintf("%s.%s: поля нет в модели %s", path, name, typ.Name()))
+			}
+		}
+		for _, name := range sortedKeys(fields) {
+			f := fields[name]
+			value, ok := obj[name]
+			if !ok {
+				if !f.optional {
+					*problems = append(*problems, fmt.Sprintf("%s.%s: поле модели %s отсутствует в ответе", path, name, typ.Name()))
+				}
+				continue
+			}
+			shape(path+"."+name, value, f.typ, problems)
+		}
+
+	case reflect.Slice, reflect.Array:
+		items, ok := doc.([]any)
+		if !ok {
+			*problems = append(*problems, fmt.Sprintf("%s: ожидался массив", path))
+			return
+		}
+		for i, item := range items {
+			shape(fmt.Sprintf("%s[%d]", path, i), item, typ.Elem(), problems)
+		}
+	}
+}
+
+// isLeaf - тип декодируется сам и не проверяется по полям (например, time.Time)
+func isLeaf(typ reflect.Type) bool {
+	ptr := reflect.PointerTo(typ)
+	return ptr.Implements(jsonUnmarshaler) || ptr.Implements(textUnmarshaler)
+}
+
+// jsonFields - поля структуры по именам JSON с учетом встроенных структур
+func jsonFields(typ reflect.Type) map[string]field {
+	fields := make(map[string]field)
+	for i := range typ.NumField() {
+		sf := typ.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if sf.Anonymous && name == "" {
+			embedded := sf.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for n, f := range jsonFields(embedded) {
+					if _, ok := fields[n]; !ok {
+						fields[n] = f
+					}
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		fields[name] = field{
+			typ:      sf.Type,
+			optional: strings.Contains(opts, "omitempty") || sf.Type.Kind() == reflect.Pointer,
+		}
+	}
+	return fields
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -39,6 +39,12 @@ func NewClient(baseURL string, tokens *token.Storage) (*Client, error) {
 
 // doRequest HTTP запрос с заданным методом, путем и телом
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
+	return c.doRequestWithHeader(ctx, method, path, body, nil)
+}
+
+// doRequestWithHeader - HTTP запрос, заголовки header заменяют заголовки текущей сессии.
+// Токены из ответа на такой запрос не сохраняются, это решает вызывающий код.
+func (c *Client) doRequestWithHeader(ctx context.Context, method, path string, body interface{}, header http.Header) ([]byte, error) {
 	reqURL := c.baseURL.ResolveReference(&url.URL{Path: path})
 
 	var buf bytes.Buffer
@@ -61,6 +67,9 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 		req.Header.Set("Authorization", access)
 		req.Header.Set("Refresh-Token", refresh)
 	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
 
 	// выполнение запроса
 	resp, err := c.httpClient.Do(req)
@@ -70,7 +79,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 	}
 	defer resp.Body.Close()
 
-	if newAccess := resp.Header.Get("Authorization"); newAccess != "" {
+	if newAccess := resp.Header.Get("Authorization"); newAccess != "" && header == nil {
 		c.tokenStore.SetTokens(newAccess, refresh)
 		if newRefresh := resp.Header.Get("Refresh-Token"); newRefresh != "" {
 			c.tokenStore.SetTokens(newAccess, newRefresh)
@@ -120,6 +129,49 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 	return responseBody, nil
 }
 
+// SetUserID - установка ID пользователя для восстановленной сессии
+func (c *Client) SetUserID(userID int) {
+	c.userID = userID
+}
+
+// UserID - ID текущего пользователя.
+// Берется из sub access token, если он есть, иначе из последнего входа.
+func (c *Client) UserID() int {
+	if claims, ok := c.tokenStore.Claims(); ok && claims.UserID != 0 {
+		return claims.UserID
+	}
+	return c.userID
+}
+
+// Session - claims текущего access token
+func (c *Client) Session() (token.Claims, bool) {
+	return c.tokenStore.Claims()
+}
+
+// AuthHeader - заголовок авторизации текущей сессии для websocket соединений.
+// Без сессии возвращается пустой заголовок.
+func (c *Client) AuthHeader() http.Header {
+	header := http.Header{}
+	if access, _ := c.tokenStore.GetToken(); access != "" {
+		header.Set("Authorization", access)
+	}
+	return header
+}
+
+// RefreshIfExpiring - обновление access token, если он истекает в течение within.
+// Возвращает true, если токен был обновлен.
+func (c *Client) RefreshIfExpiring(ctx context.Context, within time.Duration) (bool, error) {
+	claims, ok := c.tokenStore.Claims()
+	if !ok || !claims.ExpiresWithin(within, time.Now()) {
+		return false, nil
+	}
+
+	if _, err := c.RefreshToken(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // Register - регистрация нового пользователя
 func (c *Client) Register(ctx context.Context, req UserRegRequest) (*TokenResponse, *ProfileResponse, error) {
 	body, err := c.doRequest(ctx, "POST", RegistrationPath, req)
@@ -173,11 +225,13 @@ func (c *Client) RefreshToken(ctx context.Context) (*TokenResponse, error) {
 		return nil, fmt.Errorf("отсутствует refresh token")
 	}
 
-	// использование refresh token как временный access token
-	c.tokenStore.SetTokens(refresh, refresh)
-
-	body, err := c.doRequest(ctx, "POST", RefreshTokenPath, nil)
+	// refresh token передается вместо access token только в этом запросе,
+	// сессия не меняется, пока не получен новый access token
+	header := http.Header{}
+	header.Set("Authorization", refresh)
+	header.Set("Refresh-Token", refresh)
 
+	body, err := c.doRequestWithHeader(ctx, "POST", RefreshTokenPath, nil, header)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка обновления токена: %w", err)
 	}
@@ -186,14 +240,18 @@ func (c *Client) RefreshToken(ctx context.Context) (*TokenResponse, error) {
 	if err := json.Unmarshal(body, &resp); err != nil {
 		return nil, fmt.Errorf("ошибка декодирования ответа: %w", err)
 	}
+	if resp.AccessToken == "" {
+		return nil, fmt.Errorf("сервис не вернул access token")
+	}
 	c.tokenStore.SetTokens(resp.AccessToken, refresh)
 	return &resp, nil
 }
 
 // GetProfile - получение профиля текущего пользователя
 func (c *Client) GetProfile(ctx context.Context) (*ProfileResponse, error) {
-	path := fmt.Sprintf(GetProfilePath, c.userID)
-	if c.userID == 0 {
+	userID := c.UserID()
+	path := fmt.Sprintf(GetProfilePath, userID)
+	if userID == 0 {
 		return nil, fmt.Errorf("user id not set")
 	}
 
@@ -241,7 +299,7 @@ func (c *Client) InitOAuthDeviceFlow(ctx context.Context, provider string) (*Dev
 }
 
 // CheckOAuthDeviceFlow - проверка статуса авторизации
-func (c *Client) CheckOAuthDeviceFlow(ctx context.Context, provider, deviceCode string) (*DeviceCheckResponse2, error) {
+func (c *Client) CheckOAuthDeviceFlow(ctx context.Context, provider, deviceCode string) (*DeviceCheckResponse, error) {
 	var checkPath string
 	switch provider {
 	case "google":
@@ -261,7 +319,7 @@ func (c *Client) CheckOAuthDeviceFlow(ctx context.Context, provider, deviceCode
 		return nil, fmt.Errorf("ошибка проверки статуса OAuth: %w", err)
 	}
 
-	var resp DeviceCheckResponse2
+	var resp DeviceCheckResponse
 	if err := json.Unmarshal(body, &resp); err != nil {
 		return nil, fmt.Errorf("ошибка декодирования ответа проверки: %w", err)
 	}
@@ -0,0 +1,60 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lesta-start-battleship/cli/internal/api/auth"
+	"lesta-start-battleship/cli/storage/token"
+)
+
+func TestRefreshTokenKeepsSession(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		wantErr bool
+		want    string // access token после обновления
+	}{
+		{name: "новый токен", body: `{"access_token": "new-access"}`, status: http.StatusOK, want: "new-access"},
+		{name: "некорректный ответ", body: `{"access_token":`, status: http.StatusOK, wantErr: true, want: "old-access"},
+		{name: "ответ без токена", body: `{}`, status: http.StatusOK, wantErr: true, want: "old-access"},
+		{name: "ошибка сервиса", body: `{"error": "refresh token истек"}`, status: http.StatusUnauthorized, wantErr: true, want: "old-access"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != "refresh" {
+					t.Errorf("Authorization запроса обновления: %q", got)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			tokens := token.NewStorage()
+			tokens.SetTokens("old-access", "refresh")
+			var saved []string
+			tokens.OnChange(func(access, refresh string) { saved = append(saved, access) })
+
+			client, err := auth.NewClient(server.URL+"/", tokens)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.RefreshToken(context.Background()); (err != nil) != tt.wantErr {
+				t.Fatalf("ошибка: %v, ожидалась: %v", err, tt.wantErr)
+			}
+
+			if access, refresh := tokens.GetToken(); access != tt.want || refresh != "refresh" {
+				t.Errorf("токены после обновления: %q, %q", access, refresh)
+			}
+			for _, access := range saved {
+				if access != tt.want {
+					t.Errorf("сохранен промежуточный access token %q", access)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lesta-start-battleship/cli/internal/api/apitest"
+	"lesta-start-battleship/cli/internal/api/auth"
+	"lesta-start-battleship/cli/internal/config"
+	"lesta-start-battleship/cli/storage/token"
+)
+
+// call - вызов метода клиента авторизации
+func call(fn func(ctx context.Context, c *auth.Client) (any, error)) func(context.Context, config.Endpoints, *token.Storage) (any, error) {
+	return func(ctx context.Context, endpoints config.Endpoints, tokens *token.Storage) (any, error) {
+		c, err := auth.NewClient(endpoints.Auth, tokens)
+		if err != nil {
+			return nil, err
+		}
+		return fn(ctx, c)
+	}
+}
+
+func TestContracts(t *testing.T) {
+	apitest.Run(t, []apitest.Case{
+		{
+			Name: "Register",
+			Call: call(func(ctx context.Context, c *auth.Client) (any, error) {
+				tokens, profile, err := c.Register(ctx, auth.UserRegRequest{
+					Email:    "sailor@example.com",
+					Username: "sailor",
+					Password: "sailor123",
+				})
+				return []any{tokens, profile}, err
+			}),
+			Models: []any{auth.TokenResponse{}, auth.ProfileResponse{}},
+		},
+		{
+			Name: "Login",
+			Call: call(func(ctx context.Context, c *auth.Client) (any, error) {
+				tokens, profile, err := c.Login(ctx, auth.LoginRequest{Username: "admiral", Password: "admiral"})
+				return []any{tokens, profile}, err
+			}),
+			Models: []any{auth.TokenResponse{}, auth.ProfileResponse{}},
+		},
+		{
+			Name: "LoginWrongPassword",
+			Call: call(func(ctx context.Context, c *auth.Client) (any, error) {
+				_, _, err := c.Login(ctx, auth.LoginRequest{Username: "admiral", Password: "wrong"})
+				return nil, err
+			}),
+			Models: []any{auth.ErrorResponse{}},
+		},
+		{
+			Name: "RefreshToken",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *auth.Client) (any, error) {
+				return c.RefreshToken(ctx)
+			}),
+			Models: []any{auth.TokenResponse{}},
+		},
+		{
+			Name: "RefreshIfExpiring",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *auth.Client) (any, error) {
+				return c.RefreshIfExpiring(ctx, 24*time.Hour)
+			}),
+			Models: []any{auth.TokenResponse{}},
+		},
+		{
+			Name: "GetProfile",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *auth.Client) (any, error) {
+				return c.GetProfile(ctx)
+			}),
+			Models: []any{auth.ProfileResponse{}},
+		},
+		{
+			Name: "InitOAuthDeviceFlow",
+			Call: call(func(ctx context.Context, c *auth.Client) (any, error) {
+				return c.InitOAuthDeviceFlow(ctx, "google")
+			}),
+			Models: []any{auth.DeviceAuthResponse{}},
+		},
+		{
+			Name: "CheckOAuthDeviceFlow",
+			Call: call(func(ctx context.Context, c *auth.Client) (any, error) {
+				device, err := c.InitOAuthDeviceFlow(ctx, "yandex")
+				if err != nil {
+					return nil, err
+				}
+				pending, err := c.CheckOAuthDeviceFlow(ctx, "yandex", device.DeviceCode)
+				if err != nil {
+					return nil, err
+				}
+				authenticated, err := c.CheckOAuthDeviceFlow(ctx, "yandex", device.DeviceCode)
+				return []any{pending, authenticated}, err
+			}),
+			Models: []any{auth.DeviceAuthResponse{}, auth.DeviceCheckResponse{}, auth.DeviceCheckResponse{}},
+		},
+		{
+			Name: "CompleteOAuthPolling",
+			Call: call(func(ctx context.Context, c *auth.Client) (any, error) {
+				device, err := c.InitOAuthDeviceFlow(ctx, "google")
+				if err != nil {
+					return nil, err
+				}
+				tokens, profile, err := c.CompleteOAuthPolling(ctx, "google", device.DeviceCode, 10, 1)
+				return []any{tokens, profile}, err
+			}),
+			Models: []any{auth.DeviceAuthResponse{}, auth.DeviceCheckResponse{}, auth.DeviceCheckResponse{}},
+		},
+		{
+			Name: "Logout",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *auth.Client) (any, error) {
+				return nil, c.Logout(ctx)
+			}),
+			Models: []any{auth.SuccessResponse{}},
+		},
+		{
+			Name: "UpdateUser",
+			User: "bosun",
+			Call: call(func(ctx context.Context, c *auth.Client) (any, error) {
+				return c.UpdateUser(ctx, 2, auth.UpdateUserRequest{Username: "boatswain"})
+			}),
+			Models: []any{auth.ProfileResponse{}},
+		},
+		{
+			Name: "DeleteUser",
+			User: "newbie",
+			Call: call(func(ctx context.Context, c *auth.Client) (any, error) {
+				return nil, c.DeleteUser(ctx, 6)
+			}),
+			Models: []any{auth.SuccessResponse{}},
+		},
+	})
+}
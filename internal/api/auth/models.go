@@ -62,15 +62,8 @@ type DeviceAuthResponse struct {
 	Interval        int    `json:"interval,omitempty"` // интервал опроса (сек)
 }
 
-// DeviceCheckResponse - ответ на проверку статуса
+// DeviceCheckResponse - ответ на проверку статуса OAuth
 type DeviceCheckResponse struct {
-	Status        string           `json:"status"`                   // Статус: "pending", "authenticated", "expired", "denied"
-	TokenResponse *TokenResponse   `json:"token_response,omitempty"` // Токены, если авторизация успешна
-	User          *ProfileResponse `json:"user,omitempty"`           // Данные пользователя, если есть
-	Error         string           `json:"error,omitempty"`          // Описание ошибки, если есть
-}
-
-type DeviceCheckResponse2 struct {
 	AccessToken  string           `json:"access_token,omitempty"` // Токен доступа, если авторизация успешна
 	RefreshToken string           `json:"refresh_token"`
 	Status       string           `json:"status"`
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type statusKey struct{}
+
+// Status - отметка об ответах из кэша вместо сервера для запросов одного контекста
+type Status struct {
+	mu       sync.Mutex
+	offline  bool
+	storedAt time.Time // время сохранения самого старого ответа из кэша
+}
+
+// Track - контекст, запросы которого отмечают ответы из кэша в Status
+func Track(ctx context.Context) (context.Context, *Status) {
+	status := &Status{}
+	return context.WithValue(ctx, statusKey{}, status), status
+}
+
+// Offline - сервер недоступен и показаны сохраненные данные на момент storedAt
+func (s *Status) Offline() (storedAt time.Time, offline bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.storedAt, s.offline
+}
+
+// markOffline - запрос контекста ctx получил сохраненный ответ
+func markOffline(ctx context.Context, storedAt time.Time) {
+	status, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+
+	status.mu.Lock()
+	defer status.mu.Unlock()
+	if !status.offline || storedAt.Before(status.storedAt) {
+		status.storedAt = storedAt
+	}
+	status.offline = true
+}
+
+// FromContext - Status контекста, созданного Track
+func FromContext(ctx context.Context) (*Status, bool) {
+	status, ok := ctx.Value(statusKey{}).(*Status)
+	return status, ok
+}
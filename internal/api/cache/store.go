@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxStale - максимальный возраст ответа, который показывается без сети
+const MaxStale = 7 * 24 * time.Hour
+
+// Entry - сохраненный ответ сервера
+type Entry struct {
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	ETag     string      `json:"etag,omitempty"`
+	StoredAt time.Time   `json:"stored_at"`
+}
+
+// Store - кэш ответов в файле: URL запроса -> ответ
+type Store struct {
+	path    string
+	entries map[string]Entry
+	mu      sync.RWMutex
+}
+
+// DefaultPath - путь к файлу кэша профиля по умолчанию
+func DefaultPath(profile string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join("cache", profile+".json")
+	}
+	return filepath.Join(dir, "lesta-battleship", profile+".json")
+}
+
+// Open - чтение кэша. Отсутствующий или поврежденный файл дает пустой кэш,
+// пустой path - кэш только в памяти.
+func Open(path string) *Store {
+	s := &Store{
+		path:    path,
+		entries: make(map[string]Entry),
+	}
+	if path == "" {
+		return s
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return s
+	}
+	if err := json.Unmarshal(raw, &s.entries); err != nil || s.entries == nil {
+		s.entries = make(map[string]Entry)
+	}
+	s.prune(time.Now())
+	return s
+}
+
+// Get - ответ по ключу
+func (s *Store) Get(key string) (Entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.entries[key]
+	return e, ok
+}
+
+// Put - сохранение ответа
+func (s *Store) Put(key string, e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = e
+	return s.save()
+}
+
+// Invalidate - удаление ответов с адресами, начинающимися с prefix
+func (s *Store) Invalidate(prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed := false
+	for key := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.entries, key)
+			removed = true
+		}
+	}
+	if !removed {
+		return nil
+	}
+	return s.save()
+}
+
+// prune - удаление ответов старше MaxStale
+func (s *Store) prune(now time.Time) {
+	for key, e := range s.entries {
+		if now.Sub(e.StoredAt) > MaxStale {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// save - запись файла, вызывается под блокировкой
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	raw, err := json.Marshal(s.entries)
+	if err != nil {
+		return fmt.Errorf("ошибка кодирования кэша: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("ошибка создания каталога кэша: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("ошибка записи кэша: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("ошибка записи кэша: %w", err)
+	}
+
+	return nil
+}
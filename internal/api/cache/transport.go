@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Rule - время жизни ответов на GET запросы по адресу URL (без параметров запроса)
+type Rule struct {
+	URL string
+	TTL time.Duration
+}
+
+// privateHeaders - заголовки ответа, которые не сохраняются в кэше
+var privateHeaders = []string{"Authorization", "Refresh-Token", "Set-Cookie"}
+
+// Transport - http.RoundTripper с кэшем ответов.
+// Свежий ответ отдается без запроса к серверу, устаревший перепроверяется по ETag.
+// Если сервер недоступен, отдается сохраненный ответ, а контекст запроса помечается как офлайн.
+// Успешный изменяющий запрос сбрасывает кэш сервиса (первый сегмент пути).
+type Transport struct {
+	base  http.RoundTripper
+	store *Store
+	rules map[string]time.Duration
+	now   func() time.Time
+}
+
+// NewTransport - создание транспорта поверх base (nil - http.DefaultTransport)
+func NewTransport(base http.RoundTripper, store *Store, rules []Rule) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	t := &Transport{
+		base:  base,
+		store: store,
+		rules: make(map[string]time.Duration, len(rules)),
+		now:   time.Now,
+	}
+	for _, rule := range rules {
+		u, err := url.Parse(rule.URL)
+		if err != nil {
+			continue
+		}
+		t.rules[ruleKey(u)] = rule.TTL
+	}
+	return t
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		resp, err := t.base.RoundTrip(req)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			if err := t.store.Invalidate(servicePrefix(req.URL)); err != nil {
+				log.Printf("Ошибка сброса кэша: %v", err)
+			}
+		}
+		return resp, err
+	}
+
+	ttl, ok := t.rules[ruleKey(req.URL)]
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	entry, cached := t.store.Get(key)
+	if cached && t.now().Sub(entry.StoredAt) > MaxStale {
+		cached = false
+	}
+	if cached && t.now().Sub(entry.StoredAt) < ttl {
+		return entry.response(req), nil
+	}
+
+	if cached && entry.ETag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		if !cached {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		markOffline(req.Context(), entry.StoredAt)
+		return entry.response(req), nil
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		resp.Body.Close()
+		entry.StoredAt = t.now()
+		t.put(key, entry)
+
+		// токены, обновленные сервером, передаются клиенту
+		fresh := entry.response(req)
+		for _, name := range privateHeaders {
+			if value := resp.Header.Get(name); value != "" {
+				fresh.Header.Set(name, value)
+			}
+		}
+		return fresh, nil
+
+	case resp.StatusCode == http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения ответа: %w", err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		header := resp.Header.Clone()
+		for _, name := range privateHeaders {
+			header.Del(name)
+		}
+		t.put(key, Entry{
+			Status:   resp.StatusCode,
+			Header:   header,
+			Body:     body,
+			ETag:     resp.Header.Get("ETag"),
+			StoredAt: t.now(),
+		})
+	}
+
+	return resp, nil
+}
+
+func (t *Transport) put(key string, entry Entry) {
+	if err := t.store.Put(key, entry); err != nil {
+		log.Printf("Ошибка сохранения кэша: %v", err)
+	}
+}
+
+// response - ответ из сохраненных данных
+func (e Entry) response(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// servicePrefix - адрес сервиса: хост и первый сегмент пути
+func servicePrefix(u *url.URL) string {
+	prefix := u.Scheme + "://" + u.Host + "/"
+	if segment, _, ok := strings.Cut(strings.TrimPrefix(u.EscapedPath(), "/"), "/"); ok {
+		prefix += segment + "/"
+	}
+	return prefix
+}
+
+// ruleKey - адрес без параметров запроса
+func ruleKey(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + u.EscapedPath()
+}
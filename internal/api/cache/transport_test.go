@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// get - GET запрос через транспорт, тело ответа и статус контекста
+func get(t *testing.T, client *http.Client, url string) (string, *Status) {
+	t.Helper()
+	ctx, status := Track(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body), status
+}
+
+func TestTransport(t *testing.T) {
+	var hits, notModified int
+	body := "v1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.Method != http.MethodGet {
+			body = "v2"
+			return
+		}
+		etag := `"` + body + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Authorization", "token-"+body)
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+
+	path := filepath.Join(t.TempDir(), "cache.json")
+	now := time.Now()
+	transport := NewTransport(nil, Open(path), []Rule{{URL: server.URL + "/guild/", TTL: time.Minute}})
+	transport.now = func() time.Time { return now }
+	client := &http.Client{Transport: transport}
+	url := server.URL + "/guild/?offset=0"
+
+	if got, _ := get(t, client, url); got != "v1" || hits != 1 {
+		t.Fatalf("первый запрос: %q, запросов к серверу %d", got, hits)
+	}
+
+	// свежий ответ отдается без запроса к серверу
+	if got, _ := get(t, client, url); got != "v1" || hits != 1 {
+		t.Fatalf("свежий ответ: %q, запросов к серверу %d", got, hits)
+	}
+
+	// устаревший ответ перепроверяется по ETag
+	now = now.Add(2 * time.Minute)
+	if got, _ := get(t, client, url); got != "v1" || notModified != 1 {
+		t.Fatalf("перепроверка: %q, ответов 304: %d", got, notModified)
+	}
+
+	// изменение данных сервиса сбрасывает кэш
+	resp, err := client.Post(server.URL+"/guild/", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got, _ := get(t, client, url); got != "v2" {
+		t.Fatalf("после изменения: %q, ожидался v2", got)
+	}
+
+	// без сети - сохраненный ответ из файла с отметкой офлайн
+	server.Close()
+	now = now.Add(2 * time.Minute)
+	offline := NewTransport(nil, Open(path), []Rule{{URL: server.URL + "/guild/", TTL: time.Minute}})
+	offline.now = func() time.Time { return now }
+	got, status := get(t, &http.Client{Transport: offline}, url)
+	if got != "v2" {
+		t.Fatalf("офлайн: %q, ожидался v2", got)
+	}
+	if _, ok := status.Offline(); !ok {
+		t.Error("ответ из кэша без сети не отмечен как офлайн")
+	}
+}
+
+func TestTransportPrivateHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Authorization", "access")
+		w.Header().Set("Refresh-Token", "refresh")
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	store := Open("")
+	client := &http.Client{Transport: NewTransport(nil, store, []Rule{{URL: server.URL + "/shop/item/", TTL: time.Minute}})}
+	get(t, client, server.URL+"/shop/item/")
+
+	entry, ok := store.Get(server.URL + "/shop/item/")
+	if !ok {
+		t.Fatal("ответ не сохранен")
+	}
+	for _, name := range privateHeaders {
+		if entry.Header.Get(name) != "" {
+			t.Errorf("заголовок %s сохранен в кэше", name)
+		}
+	}
+}
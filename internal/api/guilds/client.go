@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,6 +15,19 @@ import (
 	"lesta-start-battleship/cli/storage/token"
 )
 
+// ErrAuditUnavailable - сервер гильдий не поддерживает журнал действий
+var ErrAuditUnavailable = errors.New("журнал действий гильдии недоступен на сервере")
+
+// StatusError - ответ сервиса с кодом ошибки HTTP
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+}
+
 // Client - клиент для работы с API гильдий
 type Client struct {
 	baseURL    *url.URL
@@ -37,6 +51,11 @@ func NewClient(baseURL string, tokens *token.Storage) (*Client, error) {
 	}, nil
 }
 
+// SetTransport - замена транспорта HTTP клиента, например на кэширующий
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
+
 // doRequest HTTP запрос с заданным методом, путем и телом и с учётом query-параметров
 func (c *Client) doRequest(
 	ctx context.Context,
@@ -93,7 +112,7 @@ func (c *Client) doRequest(
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(responseBody))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(responseBody)}
 	}
 
 	return responseBody, nil
@@ -149,6 +168,25 @@ func (c *Client) GetGuilds(ctx context.Context, offset, limit int) (*GuildPagina
 	return resp.Value, nil
 }
 
+// GetAllGuilds - получить все гильдии, запрашивая страницы по pageSize
+func (c *Client) GetAllGuilds(ctx context.Context, pageSize int) ([]GuildResponse, error) {
+	var all []GuildResponse
+	for offset := 0; ; offset += pageSize {
+		page, err := c.GetGuilds(ctx, offset, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		if page == nil || len(page.Items) == 0 {
+			break
+		}
+		all = append(all, page.Items...)
+		if len(all) >= page.TotalItems {
+			break
+		}
+	}
+	return all, nil
+}
+
 // SendJoinRequest - отправить запрос на вступление в гильдию
 func (c *Client) SendJoinRequest(ctx context.Context, guildTag string, userID int) error {
 	path := fmt.Sprintf(PathSendJoinRequest, guildTag)
@@ -368,3 +406,42 @@ func (c *Client) GetGuildWarList(
 
 	return &response, nil
 }
+
+// GetAuditLog - журнал действий гильдии (для владельца и офицеров).
+// Если сервер не поддерживает журнал, возвращается ErrAuditUnavailable.
+func (c *Client) GetAuditLog(ctx context.Context, tag string, userID, offset, limit int) (*AuditPagination, error) {
+	path := fmt.Sprintf(PathGetAuditLog, tag)
+	params := map[string]string{
+		"user_id": strconv.Itoa(userID),
+		"offset":  strconv.Itoa(offset),
+		"limit":   strconv.Itoa(limit),
+	}
+	body, err := c.doRequest(ctx, "GET", path, params, nil)
+	if err != nil {
+		if unknownRoute(err) {
+			return nil, ErrAuditUnavailable
+		}
+		return nil, err
+	}
+
+	var resp ResponseAuditPagination
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
+// unknownRoute - сервер не знает адреса запроса: код 404, 405 или 501 без ошибки сервиса гильдий в теле
+func unknownRoute(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	switch statusErr.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+	default:
+		return false
+	}
+	var resp BaseResponse
+	return json.Unmarshal([]byte(statusErr.Body), &resp) != nil || resp.Error == ""
+}
@@ -0,0 +1,203 @@
+package guilds_test
+
+import (
+	"context"
+	"testing"
+
+	"lesta-start-battleship/cli/internal/api/apitest"
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/config"
+	"lesta-start-battleship/cli/storage/token"
+)
+
+// emptyResponse - ответ без данных
+type emptyResponse struct {
+	guilds.BaseResponse
+	Value *struct{} `json:"value"`
+}
+
+// call - вызов метода клиента гильдий
+func call(fn func(ctx context.Context, c *guilds.Client) (any, error)) func(context.Context, config.Endpoints, *token.Storage) (any, error) {
+	return func(ctx context.Context, endpoints config.Endpoints, tokens *token.Storage) (any, error) {
+		c, err := guilds.NewClient(endpoints.Guilds, tokens)
+		if err != nil {
+			return nil, err
+		}
+		return fn(ctx, c)
+	}
+}
+
+func TestContracts(t *testing.T) {
+	pending := guilds.WarStatusPending
+	isTarget := true
+
+	apitest.Run(t, []apitest.Case{
+		{
+			Name: "GetMemberByUserID",
+			User: "bosun",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return c.GetMemberByUserID(ctx, 2)
+			}),
+			Models: []any{guilds.ResponseMember{}},
+		},
+		{
+			Name: "GetMemberByUserIDNotMember",
+			User: "newbie",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return c.GetMemberByUserID(ctx, 6)
+			}),
+			Models: []any{guilds.BaseResponse{}},
+		},
+		{
+			Name: "GetGuildByTag",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return c.GetGuildByTag(ctx, "WOLF")
+			}),
+			Models: []any{guilds.ResponseGuild{}},
+		},
+		{
+			Name: "GetGuilds",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return c.GetGuilds(ctx, 0, 10)
+			}),
+			Models: []any{guilds.ResponseGuildPagination{}},
+		},
+		{
+			Name: "SendJoinRequest",
+			User: "newbie",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return nil, c.SendJoinRequest(ctx, "KRAK", 6)
+			}),
+			Models: []any{emptyResponse{}},
+		},
+		{
+			Name: "GetJoinRequests",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return c.GetJoinRequests(ctx, "WOLF", 1)
+			}),
+			Models: []any{guilds.ResponseRequestPagination{}},
+		},
+		{
+			Name: "ApplyJoinRequest",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return nil, c.ApplyJoinRequest(ctx, "WOLF", 5, 1)
+			}),
+			Models: []any{emptyResponse{}},
+		},
+		{
+			Name: "CancelJoinRequest",
+			User: "bosun",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return nil, c.CancelJoinRequest(ctx, "WOLF", 5, 2)
+			}),
+			Models: []any{emptyResponse{}},
+		},
+		{
+			Name: "CreateGuild",
+			User: "newbie",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return c.CreateGuild(ctx, 6, guilds.CreateGuildRequest{
+					Title:       "Юнги",
+					Description: "Для тех, кто только вышел в море",
+					Tag:         "CADET",
+				})
+			}),
+			Models: []any{guilds.ResponseGuild{}},
+		},
+		{
+			Name: "EditGuild",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return c.EditGuild(ctx, "WOLF", 1, guilds.EditGuildRequest{
+					Title:       "Старые волки",
+					Description: "Старейшая гильдия флота",
+				})
+			}),
+			Models: []any{guilds.ResponseGuild{}},
+		},
+		{
+			Name: "DeleteGuild",
+			User: "corsair",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return nil, c.DeleteGuild(ctx, "KRAK", 4)
+			}),
+			Models: []any{emptyResponse{}},
+		},
+		{
+			Name: "GetGuildMembers",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return c.GetGuildMembers(ctx, "WOLF", 0, 50)
+			}),
+			Models: []any{guilds.ResponseMemberPagination{}},
+		},
+		{
+			Name: "EditMember",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return nil, c.EditMember(ctx, "WOLF", 1, 3, guilds.EditMemberRequest{RoleID: 3})
+			}),
+			Models: []any{emptyResponse{}},
+		},
+		{
+			Name: "DeleteMember",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return nil, c.DeleteMember(ctx, "WOLF", 1, 3)
+			}),
+			Models: []any{emptyResponse{}},
+		},
+		{
+			Name: "ExitGuild",
+			User: "cabin",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return nil, c.ExitGuild(ctx, "WOLF")
+			}),
+			Models: []any{emptyResponse{}},
+		},
+		{
+			Name: "DeclareWar",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return c.DeclareWar(ctx, 1, 2, 1)
+			}),
+			Models: []any{guilds.DeclareWarResponse{}},
+		},
+		{
+			Name: "ConfirmWar",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return c.ConfirmWar(ctx, 1, 1)
+			}),
+			Models: []any{guilds.ConfirmWarResponse{}},
+		},
+		{
+			Name: "CancelWar",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return c.CancelWar(ctx, 1, 1)
+			}),
+			Models: []any{guilds.CancelWarResponse{}},
+		},
+		{
+			Name: "GetGuildWarList",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return c.GetGuildWarList(ctx, 1, 1, nil, &isTarget, &pending, 1, 20)
+			}),
+			Models: []any{guilds.GuildWarListResponse{}},
+		},
+		{
+			Name: "GetAuditLog",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *guilds.Client) (any, error) {
+				return c.GetAuditLog(ctx, "WOLF", 1, 0, 3)
+			}),
+			Models: []any{guilds.ResponseAuditPagination{}},
+		},
+	})
+}
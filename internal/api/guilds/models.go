@@ -1,6 +1,9 @@
 package guilds
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // константы путей API
 const (
@@ -22,6 +25,7 @@ const (
 	PathListGuildWars     = "war/list"
 	PathGetGuilds         = ""
 	PathEditGuild         = "%s"
+	PathGetAuditLog       = "audit/%s"
 )
 
 // Role - роль участника гильдии
@@ -32,6 +36,12 @@ type Role struct {
 	// permissions опущены
 }
 
+// CanManage - может ли роль управлять участником с ролью roleID:
+// назначать ему роли из RolePromote и исключать из гильдии
+func (r Role) CanManage(roleID int) bool {
+	return slices.Contains(r.RolePromote, roleID)
+}
+
 // MemberResponse - информация об участнике гильдии
 type MemberResponse struct {
 	UserID   int    `json:"user_id"`
@@ -203,7 +213,11 @@ type GuildWarItem struct {
 	InitiatorGuildID int       `json:"initiator_guild_id"`
 	TargetGuildID    int       `json:"target_guild_id"`
 	Status           WarStatus `json:"status"`
+	InitiatorScore   int       `json:"initiator_score"`
+	TargetScore      int       `json:"target_score"`
 	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"` // время последней смены статуса
+	ExpiresAt        time.Time `json:"expires_at"` // срок подтверждения или окончания войны
 }
 
 // GuildWarListResponse - ответ со списком войн гильдий
@@ -214,3 +228,41 @@ type GuildWarListResponse struct {
 	TotalPages int            `json:"total_pages"`
 	Results    []GuildWarItem `json:"results"`
 }
+
+// AuditAction - действие управления гильдией в журнале
+type AuditAction string
+
+const (
+	AuditJoinAccepted AuditAction = "join_accepted" // заявка на вступление принята
+	AuditJoinDeclined AuditAction = "join_declined" // заявка на вступление отклонена
+	AuditMemberKicked AuditAction = "member_kicked" // участник исключен
+	AuditRoleChanged  AuditAction = "role_changed"  // участнику назначена роль
+	AuditWarDeclared  AuditAction = "war_declared"  // гильдии объявлена война
+)
+
+// AuditEntry - запись журнала действий гильдии.
+// Для войны цель - гильдия, которой объявлена война, Details - тег гильдии-инициатора,
+// для смены роли Details - название новой роли.
+type AuditEntry struct {
+	ID         int         `json:"id"`
+	Action     AuditAction `json:"action"`
+	ActorID    int         `json:"actor_id"`
+	ActorName  string      `json:"actor_name"`
+	TargetID   int         `json:"target_id"`
+	TargetName string      `json:"target_name"`
+	Details    string      `json:"details"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// AuditPagination - журнал действий гильдии с пагинацией, новые записи первыми
+type AuditPagination struct {
+	Items      []AuditEntry `json:"items"`
+	TotalItems int          `json:"total_items"`
+	TotalPages int          `json:"total_pages"`
+}
+
+// ResponseAuditPagination - ответ с журналом действий гильдии
+type ResponseAuditPagination struct {
+	BaseResponse
+	Value *AuditPagination `json:"value"`
+}
@@ -0,0 +1,39 @@
+package inventory_test
+
+import (
+	"context"
+	"testing"
+
+	"lesta-start-battleship/cli/internal/api/apitest"
+	"lesta-start-battleship/cli/internal/api/inventory"
+	"lesta-start-battleship/cli/internal/config"
+	"lesta-start-battleship/cli/storage/token"
+)
+
+func TestContracts(t *testing.T) {
+	apitest.Run(t, []apitest.Case{
+		{
+			Name: "GetUserInventory",
+			User: "admiral",
+			Call: func(ctx context.Context, endpoints config.Endpoints, tokens *token.Storage) (any, error) {
+				c, err := inventory.NewClient(endpoints.Inventory, tokens)
+				if err != nil {
+					return nil, err
+				}
+				return c.GetUserInventory(ctx)
+			},
+			Models: []any{inventory.UserInventoryResponse{}},
+		},
+		{
+			Name: "GetUserInventoryUnauthorized",
+			Call: func(ctx context.Context, endpoints config.Endpoints, tokens *token.Storage) (any, error) {
+				c, err := inventory.NewClient(endpoints.Inventory, tokens)
+				if err != nil {
+					return nil, err
+				}
+				return c.GetUserInventory(ctx)
+			},
+			Models: []any{inventory.ErrorResponse{}},
+		},
+	})
+}
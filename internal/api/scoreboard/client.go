@@ -12,8 +12,10 @@ import (
 	"time"
 )
 
+// Пути API относительно базового адреса сервиса
 const (
-	basePath = ""
+	UsersPath  = "users"
+	GuildsPath = "guilds"
 )
 
 // Client - клиент для работы с Scoreboard
@@ -39,6 +41,11 @@ func NewClient(baseURL string, tokens *token.Storage) (*Client, error) {
 	}, nil
 }
 
+// SetTransport - замена транспорта HTTP клиента, например на кэширующий
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
+
 // GetUserStats - получение статистики пользователей
 func (c *Client) GetUserStats(
 	ctx context.Context,
@@ -49,11 +56,7 @@ func (c *Client) GetUserStats(
 	limit int,
 	page int,
 ) (*UserListResponse, error) {
-	endpoint := fmt.Sprintf("%s%s/users", c.baseURL.String(), basePath)
-	u, err := url.Parse(endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка формирования URL: %w", err)
-	}
+	u := c.baseURL.ResolveReference(&url.URL{Path: UsersPath})
 
 	// Подготовка параметров запроса
 	q := u.Query()
@@ -130,11 +133,7 @@ func (c *Client) GetGuildStats(
 	limit int,
 	page int,
 ) (*GuildListResponse, error) {
-	endpoint := fmt.Sprintf("%s%s/guilds", c.baseURL.String(), basePath)
-	u, err := url.Parse(endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка формирования URL: %w", err)
-	}
+	u := c.baseURL.ResolveReference(&url.URL{Path: GuildsPath})
 
 	q := u.Query()
 	if guildID != nil {
@@ -0,0 +1,62 @@
+package scoreboard_test
+
+import (
+	"context"
+	"testing"
+
+	"lesta-start-battleship/cli/internal/api/apitest"
+	"lesta-start-battleship/cli/internal/api/scoreboard"
+	"lesta-start-battleship/cli/internal/config"
+	"lesta-start-battleship/cli/storage/token"
+)
+
+// call - вызов метода клиента рейтингов
+func call(fn func(ctx context.Context, c *scoreboard.Client) (any, error)) func(context.Context, config.Endpoints, *token.Storage) (any, error) {
+	return func(ctx context.Context, endpoints config.Endpoints, tokens *token.Storage) (any, error) {
+		c, err := scoreboard.NewClient(endpoints.Scoreboard, tokens)
+		if err != nil {
+			return nil, err
+		}
+		return fn(ctx, c)
+	}
+}
+
+func TestContracts(t *testing.T) {
+	userID := 2
+	guildID := 1
+
+	apitest.Run(t, []apitest.Case{
+		{
+			Name: "GetUserStats",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *scoreboard.Client) (any, error) {
+				return c.GetUserStats(ctx, nil, "a", "rating", true, 3, 1)
+			}),
+			Models: []any{scoreboard.UserListResponse{}},
+		},
+		{
+			Name: "GetCurrentUserStats",
+			User: "bosun",
+			Call: call(func(ctx context.Context, c *scoreboard.Client) (any, error) {
+				return c.GetCurrentUserStats(ctx, userID)
+			}),
+			Models: []any{scoreboard.UserListResponse{}},
+		},
+		{
+			Name: "GetChestLeaders",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *scoreboard.Client) (any, error) {
+				return c.GetChestLeaders(ctx, 5)
+			}),
+			Models: []any{scoreboard.UserListResponse{}},
+		},
+		{
+			Name: "GetGuildStats",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *scoreboard.Client) (any, error) {
+				return c.GetGuildStats(ctx, &guildID, "", "wars_victories", false, 10, 1)
+			}),
+			Models: []any{scoreboard.GuildListResponse{}},
+		},
+	})
+}
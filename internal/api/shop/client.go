@@ -11,6 +11,16 @@ import (
 	"time"
 )
 
+// Пути API относительно базового адреса сервиса
+const (
+	ProductsPath   = "item/"
+	ChestsPath     = "chest/"
+	PromotionsPath = "promotion/"
+	BuyProductPath = "item/%d/buy/"
+	BuyChestPath   = "chest/%d/buy/"
+	OpenChestPath  = "chest/open/"
+)
+
 // Client - клиент для работы с Shop
 type Client struct {
 	baseURL    *url.URL
@@ -32,6 +42,11 @@ func NewClient(baseURL string, tokens *token.Storage) (*Client, error) {
 	}, nil
 }
 
+// SetTransport - замена транспорта HTTP клиента, например на кэширующий
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
+
 // doRequest - шаблон для создания запросов
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
 	reqURL := c.baseURL.ResolveReference(&url.URL{Path: path})
@@ -60,7 +75,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 
 // GetProducts - получение списка предметов
 func (c *Client) GetProducts(ctx context.Context) ([]Product, error) {
-	resp, err := c.doRequest(ctx, "GET", "item/", nil)
+	resp, err := c.doRequest(ctx, "GET", ProductsPath, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +103,7 @@ func (c *Client) GetProducts(ctx context.Context) ([]Product, error) {
 
 // GetChests - получение списка сундуков
 func (c *Client) GetChests(ctx context.Context) ([]Chest, error) {
-	resp, err := c.doRequest(ctx, "GET", "chest/", nil)
+	resp, err := c.doRequest(ctx, "GET", ChestsPath, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +131,7 @@ func (c *Client) GetChests(ctx context.Context) ([]Chest, error) {
 
 // GetPromotions - получение списка акций
 func (c *Client) GetPromotions(ctx context.Context) ([]Promotion, error) {
-	resp, err := c.doRequest(ctx, "GET", "promotion/", nil)
+	resp, err := c.doRequest(ctx, "GET", PromotionsPath, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -144,7 +159,7 @@ func (c *Client) GetPromotions(ctx context.Context) ([]Promotion, error) {
 
 // BuyProduct - покупка предмета
 func (c *Client) BuyProduct(ctx context.Context, itemID int) error {
-	path := fmt.Sprintf("item/%d/buy/", itemID)
+	path := fmt.Sprintf(BuyProductPath, itemID)
 	resp, err := c.doRequest(ctx, "POST", path, nil)
 	if err != nil {
 		return err
@@ -168,7 +183,7 @@ func (c *Client) BuyProduct(ctx context.Context, itemID int) error {
 
 // BuyChest - покупка сундука
 func (c *Client) BuyChest(ctx context.Context, chestID int) error {
-	path := fmt.Sprintf("chest/%d/buy/", chestID)
+	path := fmt.Sprintf(BuyChestPath, chestID)
 	resp, err := c.doRequest(ctx, "POST", path, nil)
 	if err != nil {
 		return err
@@ -197,7 +212,7 @@ func (c *Client) OpenChest(ctx context.Context, chestID, amount int) error {
 		Amount:  amount,
 	}
 
-	resp, err := c.doRequest(ctx, "POST", "chest/open/", requestBody)
+	resp, err := c.doRequest(ctx, "POST", OpenChestPath, requestBody)
 	if err != nil {
 		return err
 	}
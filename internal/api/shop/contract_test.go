@@ -0,0 +1,93 @@
+package shop_test
+
+import (
+	"context"
+	"testing"
+
+	"lesta-start-battleship/cli/internal/api/apitest"
+	"lesta-start-battleship/cli/internal/api/shop"
+	"lesta-start-battleship/cli/internal/config"
+	"lesta-start-battleship/cli/storage/token"
+)
+
+// call - вызов метода клиента магазина
+func call(fn func(ctx context.Context, c *shop.Client) (any, error)) func(context.Context, config.Endpoints, *token.Storage) (any, error) {
+	return func(ctx context.Context, endpoints config.Endpoints, tokens *token.Storage) (any, error) {
+		c, err := shop.NewClient(endpoints.Shop, tokens)
+		if err != nil {
+			return nil, err
+		}
+		return fn(ctx, c)
+	}
+}
+
+func TestContracts(t *testing.T) {
+	apitest.Run(t, []apitest.Case{
+		{
+			Name: "GetProducts",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *shop.Client) (any, error) {
+				return c.GetProducts(ctx)
+			}),
+			Models: []any{[]shop.Product{}},
+		},
+		{
+			Name: "GetChests",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *shop.Client) (any, error) {
+				return c.GetChests(ctx)
+			}),
+			Models: []any{[]shop.Chest{}},
+		},
+		{
+			Name: "GetPromotions",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *shop.Client) (any, error) {
+				return c.GetPromotions(ctx)
+			}),
+			Models: []any{[]shop.Promotion{}},
+		},
+		{
+			Name: "BuyProduct",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *shop.Client) (any, error) {
+				return nil, c.BuyProduct(ctx, 2)
+			}),
+		},
+		{
+			Name: "BuyProductInsufficientFunds",
+			User: "newbie",
+			Call: call(func(ctx context.Context, c *shop.Client) (any, error) {
+				return nil, c.BuyProduct(ctx, 3)
+			}),
+		},
+		{
+			Name: "BuyChest",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *shop.Client) (any, error) {
+				return nil, c.BuyChest(ctx, 1)
+			}),
+		},
+		{
+			Name: "OpenChest",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *shop.Client) (any, error) {
+				return nil, c.OpenChest(ctx, 1, 1)
+			}),
+		},
+		{
+			Name: "GetUserPurchases",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *shop.Client) (any, error) {
+				return c.GetUserPurchases(ctx)
+			}),
+		},
+		{
+			Name: "GetPromotionDetails",
+			User: "admiral",
+			Call: call(func(ctx context.Context, c *shop.Client) (any, error) {
+				return c.GetPromotionDetails(ctx, 1)
+			}),
+		},
+	})
+}
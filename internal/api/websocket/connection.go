@@ -1,9 +1,11 @@
 package websocket
 
 import (
+	"errors"
 	"fmt"
 	"lesta-start-battleship/cli/internal/api/websocket/packets"
 	"net/http"
+	"sync"
 
 	"github.com/gorilla/websocket"
 )
@@ -16,6 +18,9 @@ type Strategy interface {
 
 const maxChanBuffer = 100
 
+// errStopped - соединение разорвано до запуска чтения или записи
+var errStopped = errors.New("соединение закрыто")
+
 // Абстракция над websocket соединением к серверу.
 //
 // Считывает пакеты от сервера в readChan.
@@ -28,11 +33,13 @@ type WebsocketClient struct {
 	writeChan chan packets.Packet
 	errorChan chan error
 
-	strategy    Strategy
-	isConnected bool
+	strategy Strategy
+	dialer   *websocket.Dialer
 
-	dialer *websocket.Dialer
-	conn   *websocket.Conn
+	// mu защищает conn и isConnected: Stop вызывают и клиент, и ReadPump с WritePump
+	mu          sync.Mutex
+	isConnected bool
+	conn        *websocket.Conn
 }
 
 // Конструктор для WebsocketClient. Сразу устанавливает Websocket соединение с сервером.
@@ -62,6 +69,8 @@ func NewWebsocketClient(url string, header http.Header, strategy Strategy) (*Web
 
 // Метод, возвращающий статус подключения WebsocketClient к серверу.
 func (c *WebsocketClient) Connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.isConnected
 }
 
@@ -97,10 +106,19 @@ func (c *WebsocketClient) Connect(url string, header http.Header) error {
 		return fmt.Errorf("WebsocketClient: [%w]", err)
 	}
 
-	c.conn = conn
+	c.mu.Lock()
+	c.conn, c.isConnected = conn, true
+	c.mu.Unlock()
 	return nil
 }
 
+// connection - текущее соединение, nil - соединение разорвано
+func (c *WebsocketClient) connection() *websocket.Conn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn
+}
+
 // Метод для чтения пакета из канала readChan.
 func (c *WebsocketClient) GetPacket() packets.Packet {
 	return <-c.readChan
@@ -126,7 +144,10 @@ func (c *WebsocketClient) ReadPump() {
 		c.Stop()
 	}()
 
-	err := c.strategy.ReadPump(c.readChan, c.conn)
+	err := errStopped
+	if conn := c.connection(); conn != nil {
+		err = c.strategy.ReadPump(c.readChan, conn)
+	}
 	c.errorChan <- fmt.Errorf("WebsocketClient: [%w]", err)
 }
 
@@ -145,21 +166,26 @@ func (c *WebsocketClient) WritePump() {
 		c.Stop()
 	}()
 
-	err := c.strategy.WritePump(c.writeChan, c.conn)
+	err := errStopped
+	if conn := c.connection(); conn != nil {
+		err = c.strategy.WritePump(c.writeChan, conn)
+	}
 	c.errorChan <- fmt.Errorf("WebsocketClient: [%w]", err)
 }
 
 // Метод для разрыва websocket соединения с сервером.
 //
 // При разрыве соединения ReadPump и WritePump также заканчивают свою работу.
+// Безопасен для одновременного вызова: соединение закрывается один раз.
 func (c *WebsocketClient) Stop() {
-	if c.conn != nil {
-		if err := c.conn.Close(); err != nil {
+	c.mu.Lock()
+	conn := c.conn
+	c.conn, c.isConnected = nil, false
+	c.mu.Unlock()
+
+	if conn != nil {
+		if err := conn.Close(); err != nil {
 			c.errorChan <- fmt.Errorf("WebsocketClient: [%w]", err)
 		}
-
-		c.conn = nil
 	}
-
-	c.isConnected = false
 }
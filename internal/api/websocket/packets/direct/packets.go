@@ -0,0 +1,103 @@
+// Package direct - пакеты личных сообщений между игроками.
+// Служебные пакеты различаются по полю type, адресат и собеседник задаются user_id.
+package direct
+
+type Packet interface {
+	isDirectPacket()
+}
+
+// Типы пакетов
+const (
+	TypeMessage       = "message"       // личное сообщение, сервер присылает автору и получателю
+	TypeSend          = "send"          // отправка сообщения
+	TypeHistory       = "history"       // запрос и ответ с перепиской с собеседником
+	TypeConversations = "conversations" // список переписок, первый пакет после подключения
+	TypeRead          = "read"          // переписка с собеседником прочитана
+	TypeError         = "error"         // ошибка обработки пакета клиента
+)
+
+// Message - личное сообщение
+type Message struct {
+	Type      string `json:"type"` // всегда TypeMessage
+	Id        string `json:"_id"`
+	FromId    int    `json:"from_id"`
+	FromName  string `json:"from_name"`
+	ToId      int    `json:"to_id"`
+	ToName    string `json:"to_name"`
+	Content   string `json:"content"`
+	Timestamp string `json:"timestamp"`
+}
+
+func (Message) isDirectPacket() {}
+
+// Peer - собеседник пользователя userID в сообщении
+func (m Message) Peer(userID int) (int, string) {
+	if m.FromId == userID {
+		return m.ToId, m.ToName
+	}
+	return m.FromId, m.FromName
+}
+
+// Send - сообщение игроку To
+type Send struct {
+	Type    string `json:"type"` // всегда TypeSend
+	To      int    `json:"to_id"`
+	Content string `json:"content"`
+}
+
+func (Send) isDirectPacket() {}
+
+// HistoryRequest - запрос сообщений переписки с With старше сообщения Before, пустой Before - последние
+type HistoryRequest struct {
+	Type   string `json:"type"` // всегда TypeHistory
+	With   int    `json:"with_id"`
+	Before string `json:"before,omitempty"`
+	Limit  int    `json:"limit"`
+}
+
+func (HistoryRequest) isDirectPacket() {}
+
+// History - сообщения переписки с With, старые первыми
+type History struct {
+	Type string    `json:"type"` // всегда TypeHistory
+	With int       `json:"with_id"`
+	Data []Message `json:"data"`
+}
+
+func (History) isDirectPacket() {}
+
+// Conversation - переписка с собеседником
+type Conversation struct {
+	UserId   int     `json:"user_id"`
+	Username string  `json:"username"`
+	Unread   int     `json:"unread"` // непрочитанных сообщений от собеседника
+	Last     Message `json:"last"`   // последнее сообщение
+}
+
+// Conversations - переписки пользователя, последние первыми
+type Conversations struct {
+	Type  string         `json:"type"` // всегда TypeConversations
+	Items []Conversation `json:"items"`
+}
+
+func (Conversations) isDirectPacket() {}
+
+// Read - сообщения от With прочитаны
+type Read struct {
+	Type string `json:"type"` // всегда TypeRead
+	With int    `json:"with_id"`
+}
+
+func (Read) isDirectPacket() {}
+
+// Error - отказ сервера выполнить пакет клиента, соединение сохраняется
+type Error struct {
+	Type    string `json:"type"` // всегда TypeError
+	Message string `json:"message"`
+}
+
+func (Error) isDirectPacket() {}
+
+type Disconnect struct{}
+
+func (Disconnect) isDirectPacket() {}
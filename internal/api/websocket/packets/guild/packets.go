@@ -10,6 +10,21 @@ type ChatMessage struct {
 
 func (ChatMessage) isGuildPacket() {}
 
+// Типы пакета ChatHistory
+const (
+	HistoryInitial = "history"      // последние сообщения комнаты, первый пакет после подключения
+	HistoryPage    = "history_page" // ответ на HistoryRequest, пустой Data - старше сообщений нет
+)
+
+// HistoryRequest - запрос сообщений комнаты старше сообщения Before
+type HistoryRequest struct {
+	Type   string `json:"type"` // всегда TypeHistory
+	Before string `json:"before"`
+	Limit  int    `json:"limit"`
+}
+
+func (HistoryRequest) isGuildPacket() {}
+
 type ChatHistory struct {
 	Type string               `json:"type"`
 	Data []ChatHistoryMessage `json:"data"`
@@ -24,10 +39,128 @@ type ChatHistoryMessage struct {
 	Content   string `json:"content"`
 	Timestamp string `json:"timestamp"`
 	Username  string `json:"username"`
+	To        string `json:"to,omitempty"` // получатель шепота, пусто - сообщение всей гильдии
 }
 
 func (ChatHistoryMessage) isGuildPacket() {}
 
+// Типы служебных пакетов чата
+const (
+	TypeHistory = "history" // запрос старых сообщений HistoryRequest
+	TypeWho     = "who"     // запрос участников в комнате
+	TypeOnline  = "online"  // ответ на WhoRequest
+	TypeWhisper = "whisper" // личное сообщение участнику комнаты
+	TypeError   = "error"   // ошибка обработки пакета клиента
+	TypeNotice  = "notice"  // служебное сообщение сервера всем в комнате
+	TypeTyping  = "typing"  // участник набирает сообщение
+	TypeRead    = "read"    // участник прочитал сообщения комнаты до указанного
+)
+
+// Типы пакетов модерации комнаты гильдии
+const (
+	TypeMute    = "mute"    // запрет участнику писать в чат
+	TypeUnmute  = "unmute"  // снятие запрета писать
+	TypeBan     = "ban"     // запрет входа в чат, подключения участника закрываются
+	TypeUnban   = "unban"   // снятие запрета входа
+	TypeDelete  = "delete"  // удаление сообщения
+	TypeDeleted = "deleted" // сообщение удалено, рассылается всем в комнате
+)
+
+// WhoRequest - запрос участников, подключенных к комнате
+type WhoRequest struct {
+	Type string `json:"type"` // всегда TypeWho
+}
+
+func (WhoRequest) isGuildPacket() {}
+
+// OnlineUser - участник, подключенный к комнате
+type OnlineUser struct {
+	UserId   int    `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// Online - участники, подключенные к комнате
+type Online struct {
+	Type  string       `json:"type"` // всегда TypeOnline
+	Users []OnlineUser `json:"users"`
+}
+
+func (Online) isGuildPacket() {}
+
+// Whisper - сообщение, которое видят только автор и получатель To.
+// Сервер присылает обоим ChatHistoryMessage с заполненным To.
+type Whisper struct {
+	Type    string `json:"type"` // всегда TypeWhisper
+	To      string `json:"to"`
+	Content string `json:"content"`
+}
+
+func (Whisper) isGuildPacket() {}
+
+// Error - отказ сервера выполнить пакет клиента, соединение сохраняется
+type Error struct {
+	Type    string `json:"type"` // всегда TypeError
+	Message string `json:"message"`
+}
+
+func (Error) isGuildPacket() {}
+
+// Typing - участник набирает сообщение. Клиент отправляет пакет без автора не чаще раза в несколько секунд,
+// сервер пересылает его остальным в комнате с UserId и Username.
+type Typing struct {
+	Type     string `json:"type"` // всегда TypeTyping
+	UserId   int    `json:"user_id,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+func (Typing) isGuildPacket() {}
+
+// Read - участник прочитал сообщения комнаты до Id включительно.
+// Сервер пересылает пакет остальным в комнате с автором, а подключившемуся - последние отметки всех участников.
+type Read struct {
+	Type     string `json:"type"` // всегда TypeRead
+	Id       string `json:"_id"`
+	UserId   int    `json:"user_id,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+func (Read) isGuildPacket() {}
+
+// Notice - служебное сообщение сервера, например о муте или бане участника
+type Notice struct {
+	Type    string `json:"type"` // всегда TypeNotice
+	Message string `json:"message"`
+}
+
+func (Notice) isGuildPacket() {}
+
+// Moderation - действие модератора над участником комнаты.
+// Права проверяются по ролям гильдии: модератор должен управлять ролью участника.
+type Moderation struct {
+	Type    string `json:"type"` // TypeMute, TypeUnmute, TypeBan или TypeUnban
+	UserId  int    `json:"user_id"`
+	Minutes int    `json:"minutes,omitempty"` // срок мута или бана, 0 - бессрочно
+}
+
+func (Moderation) isGuildPacket() {}
+
+// Delete - удаление сообщения Id: своего или участника, которым управляет роль автора запроса
+type Delete struct {
+	Type string `json:"type"` // всегда TypeDelete
+	Id   string `json:"_id"`
+}
+
+func (Delete) isGuildPacket() {}
+
+// Deleted - сообщение Id удалено пользователем By
+type Deleted struct {
+	Type string `json:"type"` // всегда TypeDeleted
+	Id   string `json:"_id"`
+	By   string `json:"by"`
+}
+
+func (Deleted) isGuildPacket() {}
+
 type Disconnect struct{}
 
 func (Disconnect) isGuildPacket() {}
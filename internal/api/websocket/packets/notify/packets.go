@@ -0,0 +1,41 @@
+package notify
+
+import "time"
+
+type Packet interface {
+	isNotifyPacket()
+}
+
+// Kind - тип события гильдии
+type Kind string
+
+const (
+	KindJoinRequest Kind = "join_request" // заявка на вступление в гильдию пользователя
+	KindWarDeclared Kind = "war_declared" // гильдии пользователя объявлена война
+	KindRoleChanged Kind = "role_changed" // изменена роль пользователя в гильдии
+	KindKicked      Kind = "kicked"       // пользователь исключен из гильдии
+	KindMention     Kind = "mention"      // пользователя упомянули в чате гильдии, событие клиента
+	KindChatInvite  Kind = "chat_invite"  // приглашение в свою игру из чата гильдии, событие клиента
+)
+
+// Event - событие гильдии, адресованное пользователю
+type Event struct {
+	Kind      Kind      `json:"kind"`
+	GuildID   int       `json:"guild_id"`
+	GuildTag  string    `json:"guild_tag"`
+	UserID    int       `json:"user_id,omitempty"`   // автор заявки или сообщения чата
+	UserName  string    `json:"user_name,omitempty"` // имя автора заявки или сообщения чата
+	WarID     int       `json:"war_id,omitempty"`
+	EnemyID   int       `json:"enemy_id,omitempty"`   // гильдия, объявившая войну
+	EnemyTag  string    `json:"enemy_tag,omitempty"`  // тег гильдии, объявившей войну
+	RoleTitle string    `json:"role_title,omitempty"` // новая роль пользователя
+	MessageID string    `json:"message_id,omitempty"` // сообщение чата с упоминанием или приглашением
+	RoomID    string    `json:"room_id,omitempty"`    // комната своей игры из приглашения
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Event) isNotifyPacket() {}
+
+type Disconnect struct{}
+
+func (Disconnect) isNotifyPacket() {}
@@ -2,7 +2,9 @@ package packets
 
 import (
 	"fmt"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/direct"
 	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/notify"
 	"reflect"
 
 	matchmaking "github.com/lesta-battleship/matchmaking/pkg/packets"
@@ -27,6 +29,16 @@ func WrapGuild(packet guild.Packet) Packet {
 	return PacketWrapper{content: packet}
 }
 
+// Заворачивает notify.Packet в packets.Packet.
+func WrapNotify(packet notify.Packet) Packet {
+	return PacketWrapper{content: packet}
+}
+
+// Заворачивает direct.Packet в packets.Packet.
+func WrapDirect(packet direct.Packet) Packet {
+	return PacketWrapper{content: packet}
+}
+
 // Заворачивает matchmaking.Packet в packets.Packet.
 func WrapMatchmaking(packet matchmaking.Packet) Packet {
 	return PacketWrapper{content: packet}
@@ -54,6 +66,50 @@ func UnwrapAsGuild(packet Packet, value any) error {
 	return nil
 }
 
+// Разворачивает packets.Packet в notify.Packet.
+// Результат разворота сохраняется в value.
+//
+// Возвращает ошибку при:
+// 1. Передачи в параметр value не указателя на значение.
+// 2. Передачи в параметр packet пакета, содержимое которого не реализует интерфейс notify.Packet.
+func UnwrapAsNotify(packet Packet, value any) error {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Pointer {
+		return fmt.Errorf("UnwrapAsNotify: Parameter value isn't a pointer")
+	}
+	rv = rv.Elem()
+
+	content, ok := packet.Content().(notify.Packet)
+	if !ok {
+		return fmt.Errorf("UnwrapAsNotify: Can't type assert packet contents as notify.Packet")
+	}
+
+	rv.Set(reflect.ValueOf(content))
+	return nil
+}
+
+// Разворачивает packets.Packet в direct.Packet.
+// Результат разворота сохраняется в value.
+//
+// Возвращает ошибку при:
+// 1. Передачи в параметр value не указателя на значение.
+// 2. Передачи в параметр packet пакета, содержимое которого не реализует интерфейс direct.Packet.
+func UnwrapAsDirect(packet Packet, value any) error {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Pointer {
+		return fmt.Errorf("UnwrapAsDirect: Parameter value isn't a pointer")
+	}
+	rv = rv.Elem()
+
+	content, ok := packet.Content().(direct.Packet)
+	if !ok {
+		return fmt.Errorf("UnwrapAsDirect: Can't type assert packet contents as direct.Packet")
+	}
+
+	rv.Set(reflect.ValueOf(content))
+	return nil
+}
+
 // Разворачивает packets.Packet в matchmaking.Packet.
 // Результат разворота сохраняется в value.
 //
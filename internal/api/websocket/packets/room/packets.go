@@ -0,0 +1,37 @@
+// Package room - сообщения чата комнаты игры.
+// Отдельного соединения у чата нет: сообщение передается через websocket матчмейкинга
+// в поле msg пакета PlayerMessage в виде JSON {"chat": {...}}, сервер пересылает его остальным игрокам комнаты.
+package room
+
+import "encoding/json"
+
+// Message - сообщение игрока в комнате
+type Message struct {
+	From      string `json:"from"`
+	Text      string `json:"text"`
+	Timestamp string `json:"timestamp"`
+}
+
+// envelope - содержимое поля msg с сообщением чата
+type envelope struct {
+	Chat *Message `json:"chat"`
+}
+
+// Encode - сообщение чата для поля msg пакета PlayerMessage
+func Encode(m Message) string {
+	data, err := json.Marshal(envelope{Chat: &m})
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// Decode - сообщение чата из поля msg пакета PlayerMessage.
+// Возвращает false для служебных сообщений сервера, например ID комнаты или игры.
+func Decode(msg string) (Message, bool) {
+	var e envelope
+	if err := json.Unmarshal([]byte(msg), &e); err != nil || e.Chat == nil {
+		return Message{}, false
+	}
+	return *e.Chat, true
+}
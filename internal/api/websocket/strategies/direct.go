@@ -0,0 +1,73 @@
+package strategies
+
+import (
+	"encoding/json"
+	"fmt"
+	"lesta-start-battleship/cli/internal/api/websocket/packets"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/direct"
+
+	"github.com/gorilla/websocket"
+)
+
+// Стратегия для WebsocketClient.
+//
+// Ожидает от сервера пакеты типа direct.Packet, различающиеся по полю type.
+// Пакеты неизвестного типа пропускаются.
+//
+// При получении пакета direct.Disconnect принудительно заканчивает работу.
+type DirectStrategy struct{}
+
+func (c DirectStrategy) ReadPump(readChan chan<- packets.Packet, conn *websocket.Conn) error {
+	for {
+		var raw json.RawMessage
+		if err := conn.ReadJSON(&raw); err != nil {
+			return fmt.Errorf("DirectStrategy.ReadPump: [%w]", err)
+		}
+
+		var head struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(raw, &head); err != nil {
+			return fmt.Errorf("DirectStrategy.ReadPump: [%w]", err)
+		}
+
+		var packet direct.Packet
+		switch head.Type {
+		case direct.TypeMessage:
+			packet = new(direct.Message)
+		case direct.TypeHistory:
+			packet = new(direct.History)
+		case direct.TypeConversations:
+			packet = new(direct.Conversations)
+		case direct.TypeError:
+			packet = new(direct.Error)
+		default:
+			continue
+		}
+		if err := json.Unmarshal(raw, packet); err != nil {
+			return fmt.Errorf("DirectStrategy.ReadPump: [%w]", err)
+		}
+
+		readChan <- packets.WrapDirect(packet)
+	}
+}
+
+func (c DirectStrategy) WritePump(writeChan <-chan packets.Packet, conn *websocket.Conn) error {
+	for packet := range writeChan {
+		var unwrap direct.Packet
+		if err := packets.UnwrapAsDirect(packet, &unwrap); err != nil {
+			return fmt.Errorf("DirectStrategy.WritePump: [%w]", err)
+		}
+
+		switch unwrap.(type) {
+		case direct.Disconnect, *direct.Disconnect:
+			return nil
+		}
+
+		if err := conn.WriteJSON(packet.Content()); err != nil {
+			return fmt.Errorf("DirectStrategy.WritePump: [%w]", err)
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package strategies
 
 import (
+	"encoding/json"
 	"fmt"
 	"lesta-start-battleship/cli/internal/api/websocket/packets"
 	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
@@ -10,7 +11,8 @@ import (
 
 // Стратегия для WebsocketClient.
 //
-// Ожидает от сервера пакеты типа guild.Packet.
+// Ожидает от сервера пакеты типа guild.Packet: служебные пакеты различаются по полю type,
+// пакет без него - guild.ChatHistoryMessage. Первый пакет с неизвестным или пустым type - guild.ChatHistory.
 //
 // При получении пакета guild.Disconnect принудительно заканчивает работу.
 type GuildChatStrategy struct{}
@@ -18,13 +20,44 @@ type GuildChatStrategy struct{}
 func (c GuildChatStrategy) ReadPump(readChan chan<- packets.Packet, conn *websocket.Conn) error {
 	isFirstMessage := true
 	for {
-		var packet guild.Packet = new(guild.ChatHistoryMessage)
-		if isFirstMessage {
-			packet = new(guild.ChatHistory)
-			isFirstMessage = false
+		var raw json.RawMessage
+		if err := conn.ReadJSON(&raw); err != nil {
+			return fmt.Errorf("GuildChatStrategy.ReadPump: [%w]", err)
+		}
+
+		var head struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(raw, &head); err != nil {
+			return fmt.Errorf("GuildChatStrategy.ReadPump: [%w]", err)
 		}
 
-		if err := conn.ReadJSON(&packet); err != nil {
+		var packet guild.Packet
+		switch head.Type {
+		case guild.HistoryInitial, guild.HistoryPage:
+			packet = new(guild.ChatHistory)
+		case guild.TypeOnline:
+			packet = new(guild.Online)
+		case guild.TypeError:
+			packet = new(guild.Error)
+		case guild.TypeNotice:
+			packet = new(guild.Notice)
+		case guild.TypeDeleted:
+			packet = new(guild.Deleted)
+		case guild.TypeTyping:
+			packet = new(guild.Typing)
+		case guild.TypeRead:
+			packet = new(guild.Read)
+		default:
+			// первым сервер присылает историю комнаты, даже если не указывает ее тип
+			if isFirstMessage {
+				packet = new(guild.ChatHistory)
+			} else {
+				packet = new(guild.ChatHistoryMessage)
+			}
+		}
+		isFirstMessage = false
+		if err := json.Unmarshal(raw, packet); err != nil {
 			return fmt.Errorf("GuildChatStrategy.ReadPump: [%w]", err)
 		}
 
@@ -0,0 +1,70 @@
+package strategies
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+)
+
+// readGuild - пакеты, которые ReadPump разбирает из кадров frames
+func readGuild(t *testing.T, frames ...string) []guild.Packet {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for _, frame := range frames {
+			conn.WriteMessage(websocket.TextMessage, []byte(frame))
+		}
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	readChan := make(chan packets.Packet, len(frames))
+	GuildChatStrategy{}.ReadPump(readChan, conn)
+	close(readChan)
+
+	var result []guild.Packet
+	for packet := range readChan {
+		var unwrap guild.Packet
+		if err := packets.UnwrapAsGuild(packet, &unwrap); err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, unwrap)
+	}
+	return result
+}
+
+func TestGuildChatFirstFrameIsHistory(t *testing.T) {
+	for name, first := range map[string]string{
+		"без типа":        `{"data": [{"_id": "1", "content": "привет"}]}`,
+		"неизвестный тип": `{"type": "init", "data": [{"_id": "1", "content": "привет"}]}`,
+		"тип истории":     `{"type": "history", "data": [{"_id": "1", "content": "привет"}]}`,
+	} {
+		got := readGuild(t, first, `{"_id": "2", "content": "еще"}`)
+		if len(got) != 2 {
+			t.Fatalf("%s: пакетов %d", name, len(got))
+		}
+		if history, ok := got[0].(*guild.ChatHistory); !ok || len(history.Data) != 1 || history.Data[0].Content != "привет" {
+			t.Errorf("%s: первый пакет %#v", name, got[0])
+		}
+		if msg, ok := got[1].(*guild.ChatHistoryMessage); !ok || msg.Content != "еще" {
+			t.Errorf("%s: второй пакет %#v", name, got[1])
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package strategies
+
+import (
+	"fmt"
+	"lesta-start-battleship/cli/internal/api/websocket/packets"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/notify"
+
+	"github.com/gorilla/websocket"
+)
+
+// Стратегия для WebsocketClient.
+//
+// Ожидает от сервера пакеты типа notify.Event. Канал уведомлений только читается:
+// клиент ничего не отправляет серверу.
+//
+// При получении пакета notify.Disconnect заканчивает работу.
+type NotifyStrategy struct{}
+
+func (c NotifyStrategy) ReadPump(readChan chan<- packets.Packet, conn *websocket.Conn) error {
+	for {
+		var event notify.Event
+		if err := conn.ReadJSON(&event); err != nil {
+			return fmt.Errorf("NotifyStrategy.ReadPump: [%w]", err)
+		}
+
+		readChan <- packets.WrapNotify(event)
+	}
+}
+
+func (c NotifyStrategy) WritePump(writeChan <-chan packets.Packet, conn *websocket.Conn) error {
+	for packet := range writeChan {
+		var unwrap notify.Packet
+		if err := packets.UnwrapAsNotify(packet, &unwrap); err != nil {
+			return fmt.Errorf("NotifyStrategy.WritePump: [%w]", err)
+		}
+
+		switch unwrap.(type) {
+		case notify.Disconnect:
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -1,33 +1,33 @@
 package app
 
 import (
+	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"lesta-start-battleship/cli/internal/api/auth"
+	"lesta-start-battleship/cli/internal/api/cache"
 	"lesta-start-battleship/cli/internal/api/guilds"
 	"lesta-start-battleship/cli/internal/api/inventory"
 	"lesta-start-battleship/cli/internal/api/scoreboard"
 	"lesta-start-battleship/cli/internal/api/shop"
 	cliModel "lesta-start-battleship/cli/internal/cli/initCli"
 	"lesta-start-battleship/cli/internal/clientdeps"
+	"lesta-start-battleship/cli/internal/config"
+	"lesta-start-battleship/cli/internal/notify"
+	"lesta-start-battleship/cli/storage/audit"
+	"lesta-start-battleship/cli/storage/chat"
+	guildStorage "lesta-start-battleship/cli/storage/guild"
+	"lesta-start-battleship/cli/storage/profile"
 	"lesta-start-battleship/cli/storage/token"
-)
-
-const (
-	authURL       = "https://battleship-lesta-start.ru/"
-	guildsURL     = "https://battleship-lesta-start.ru/guild/"
-	inventoryURL  = "https://battleship-lesta-start.ru/inventory/"
-	scoreboardURL = "https://battleship-lesta-start.ru/scoreboard/"
-	shopURL       = "https://battleship-lesta-start.ru/shop/"
+	"log"
+	"time"
 )
 
 type App struct {
 	program *tea.Program
 }
 
-func New() (*App, error) {
-	tokenStorage := token.NewStorage()
-
-	initialClients, err := initClients(tokenStorage)
+func New(cfg *config.Config) (*App, error) {
+	initialClients, err := NewClients(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -48,28 +48,114 @@ func (a *App) Run() error {
 	return nil
 }
 
-func initClients(tokenStore *token.Storage) (*clientdeps.Client, error) {
-	authClient, err := auth.NewClient(authURL, tokenStore)
+// NewClients - создание клиентов сервисов для профиля из конфигурации или последнего использованного профиля
+func NewClients(cfg *config.Config) (*clientdeps.Client, error) {
+	profiles, err := profile.Open(profile.DefaultPath())
+	if err != nil {
+		return nil, err
+	}
+
+	profileName := cfg.Profile
+	if profileName == "" {
+		profileName = profiles.Current()
+	}
+
+	return openProfile(cfg, profiles, profileName)
+}
+
+// openProfile - создание клиентов для профиля name.
+// Окружение профиля учитывается, если окружение не задано флагом или переменной окружения.
+// Сохраненная сессия профиля восстанавливается в хранилище токенов.
+func openProfile(cfg *config.Config, profiles *profile.Store, name string) (*clientdeps.Client, error) {
+	p, err := profiles.Use(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// новый профиль запоминает явно выбранное окружение
+	if p.Environment == "" && cfg.EnvironmentExplicit() {
+		if err := profiles.SetEnvironment(name, cfg.Environment); err != nil {
+			return nil, err
+		}
+	}
+
+	endpoints := cfg.Endpoints
+	if p.Environment != "" && !cfg.EnvironmentExplicit() {
+		endpoints, err = cfg.ForEnvironment(p.Environment)
+		if err != nil {
+			return nil, fmt.Errorf("профиль %q: %w", name, err)
+		}
+	}
+
+	tokenStorage := token.NewStorage()
+	if p.Session != nil {
+		tokenStorage.SetTokens(p.Session.AccessToken, p.Session.RefreshToken)
+	}
+	tokenStorage.OnChange(func(access, refresh string) {
+		if err := profiles.SaveTokens(name, access, refresh); err != nil {
+			log.Printf("Ошибка сохранения сессии профиля %s: %v", name, err)
+		}
+	})
+
+	clients, err := InitClients(endpoints, tokenStorage)
+	if err != nil {
+		return nil, err
+	}
+	if p.Session != nil {
+		clients.AuthClient.SetUserID(p.Session.UserID)
+	}
+
+	transport := cache.NewTransport(nil, cache.Open(cache.DefaultPath(name)), cacheRules(endpoints))
+	clients.GuildsClient.SetTransport(transport)
+	clients.ScoreboardClient.SetTransport(transport)
+	clients.ShopClient.SetTransport(transport)
+	clients.Audit = audit.Open(audit.DefaultPath(name))
+	clients.Chat = chat.Open(chat.DefaultDir(name))
+
+	clients.Profile = name
+	clients.Profiles = profiles
+	clients.OpenProfile = func(name string) (*clientdeps.Client, error) {
+		return openProfile(cfg, profiles, name)
+	}
+
+	return clients, nil
+}
+
+// cacheRules - время жизни кэшированных ответов: списки, которые экраны запрашивают при каждом открытии
+func cacheRules(endpoints config.Endpoints) []cache.Rule {
+	return []cache.Rule{
+		{URL: endpoints.Guilds, TTL: time.Minute},
+		{URL: endpoints.Scoreboard + scoreboard.UsersPath, TTL: 30 * time.Second},
+		{URL: endpoints.Scoreboard + scoreboard.GuildsPath, TTL: 30 * time.Second},
+		{URL: endpoints.Shop + "item/", TTL: 5 * time.Minute},
+		{URL: endpoints.Shop + "chest/", TTL: 5 * time.Minute},
+		{URL: endpoints.Shop + "promotion/", TTL: 5 * time.Minute},
+	}
+}
+
+// InitClients - создание клиентов всех REST сервисов по адресам endpoints
+func InitClients(endpoints config.Endpoints, tokenStore *token.Storage) (*clientdeps.Client, error) {
+	authClient, err := auth.NewClient(endpoints.Auth, tokenStore)
 	if err != nil {
 		return nil, err
 	}
 
-	guildsClient, err := guilds.NewClient(guildsURL, tokenStore)
+	guildsClient, err := guilds.NewClient(endpoints.Guilds, tokenStore)
 	if err != nil {
 		return nil, err
 	}
 
-	inventoryClient, err := inventory.NewClient(inventoryURL, tokenStore)
+	inventoryClient, err := inventory.NewClient(endpoints.Inventory, tokenStore)
 	if err != nil {
 		return nil, err
 	}
 
-	scoreboardClient, err := scoreboard.NewClient(scoreboardURL, tokenStore)
+	scoreboardClient, err := scoreboard.NewClient(endpoints.Scoreboard, tokenStore)
 	if err != nil {
 		return nil, err
 	}
 
-	shopClient, err := shop.NewClient(shopURL, tokenStore)
+	shopClient, err := shop.NewClient(endpoints.Shop, tokenStore)
 	if err != nil {
 		return nil, err
 	}
@@ -80,5 +166,11 @@ func initClients(tokenStore *token.Storage) (*clientdeps.Client, error) {
 		InventoryClient:  inventoryClient,
 		ScoreboardClient: scoreboardClient,
 		ShopClient:       shopClient,
+		Endpoints:        endpoints,
+		GuildStore:       guildStorage.NewStore(guildStorage.DefaultTTL),
+		Notifications:    notify.NewCenter(),
+		Audit:            audit.Open(""),
+		Chat:             chat.Open(""),
+		Direct:           chat.NewDirect(),
 	}, nil
 }
@@ -0,0 +1,62 @@
+package clitest
+
+import (
+	"io"
+	"log"
+	"net/http/httptest"
+	"testing"
+
+	"lesta-start-battleship/cli/internal/app"
+	"lesta-start-battleship/cli/internal/clientdeps"
+	"lesta-start-battleship/cli/internal/config"
+	"lesta-start-battleship/cli/internal/mockserver"
+	"lesta-start-battleship/cli/storage/token"
+)
+
+// Backend - mock-сервер всех сервисов игры на случайном порту
+type Backend struct {
+	Server    *httptest.Server
+	Store     *mockserver.Store
+	Endpoints config.Endpoints
+}
+
+// NewBackend - запуск mock-сервера со встроенными данными.
+// Сервер останавливается по завершении теста.
+func NewBackend(t testing.TB) *Backend {
+	t.Helper()
+
+	seed, err := mockserver.DefaultSeed()
+	if err != nil {
+		t.Fatalf("ошибка загрузки данных mock-сервера: %v", err)
+	}
+	return NewBackendWithSeed(t, seed)
+}
+
+// NewBackendWithSeed - запуск mock-сервера с данными seed
+func NewBackendWithSeed(t testing.TB, seed mockserver.Seed) *Backend {
+	t.Helper()
+
+	opts := mockserver.DefaultOptions()
+	opts.Logger = log.New(io.Discard, "", 0)
+
+	store := mockserver.NewStore(seed)
+	server := httptest.NewServer(mockserver.New(store, opts))
+	t.Cleanup(server.Close)
+
+	return &Backend{
+		Server:    server,
+		Store:     store,
+		Endpoints: mockserver.Endpoints(server.URL),
+	}
+}
+
+// Clients - клиенты сервисов с пустой сессией, без сохранения профиля на диск
+func (b *Backend) Clients(t testing.TB) *clientdeps.Client {
+	t.Helper()
+
+	clients, err := app.InitClients(b.Endpoints, token.NewStorage())
+	if err != nil {
+		t.Fatalf("ошибка создания клиентов: %v", err)
+	}
+	return clients
+}
@@ -0,0 +1,243 @@
+// Package clitest - запуск моделей Bubble Tea без терминала для тестов.
+//
+// Harness передает модели сообщения клавиатуры, выполняет команды tea.Cmd в фоне
+// и доставляет их результаты в Update, как это делает tea.Program.
+// Проверки строятся на выводе View() и golden файлах в testdata.
+package clitest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// update - перезапись golden файлов текущим выводом: go test ./... -update
+var update = flag.Bool("update", false, "перезаписать golden файлы")
+
+// DefaultTimeout - время ожидания результата асинхронных команд
+const DefaultTimeout = 5 * time.Second
+
+// Размер виртуального терминала
+const (
+	Width  = 120
+	Height = 40
+)
+
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+// Harness - модель, запущенная без терминала
+type Harness struct {
+	t       testing.TB
+	model   tea.Model
+	msgs    chan tea.Msg
+	done    chan struct{}
+	quit    bool
+	Timeout time.Duration // время ожидания в WaitFor
+}
+
+// New - запуск модели: вызов Init и отправка размера окна
+func New(t testing.TB, model tea.Model) *Harness {
+	t.Helper()
+
+	h := &Harness{
+		t:       t,
+		model:   model,
+		msgs:    make(chan tea.Msg, 64),
+		done:    make(chan struct{}),
+		Timeout: DefaultTimeout,
+	}
+	t.Cleanup(func() { close(h.done) })
+
+	h.exec(model.Init())
+	h.Send(tea.WindowSizeMsg{Width: Width, Height: Height})
+	return h
+}
+
+// Model - текущая корневая модель
+func (h *Harness) Model() tea.Model {
+	return h.model
+}
+
+// Quit - была ли выполнена команда tea.Quit
+func (h *Harness) Quit() bool {
+	return h.quit
+}
+
+// Send - передача сообщения модели
+func (h *Harness) Send(msg tea.Msg) {
+	h.t.Helper()
+
+	if _, ok := msg.(tea.QuitMsg); ok {
+		h.quit = true
+		return
+	}
+
+	var cmd tea.Cmd
+	h.model, cmd = h.model.Update(msg)
+	h.exec(cmd)
+}
+
+// Press - нажатие клавиш по очереди
+func (h *Harness) Press(keys ...tea.KeyType) {
+	h.t.Helper()
+	for _, key := range keys {
+		h.Send(tea.KeyMsg{Type: key})
+	}
+}
+
+// Type - ввод текста посимвольно
+func (h *Harness) Type(text string) {
+	h.t.Helper()
+	for _, r := range text {
+		h.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+// View - вывод модели без ANSI последовательностей и пробелов в конце строк
+func (h *Harness) View() string {
+	return Clean(h.model.View())
+}
+
+// WaitFor - обработка результатов команд, пока вывод не будет содержать text
+func (h *Harness) WaitFor(text string) {
+	h.t.Helper()
+	h.WaitUntil(func(view string) bool {
+		return strings.Contains(view, text)
+	}, "вывод содержит "+strconv.Quote(text))
+}
+
+// WaitUntil - обработка результатов команд, пока cond не вернет true.
+// По истечении Timeout тест завершается с последним выводом модели.
+func (h *Harness) WaitUntil(cond func(view string) bool, desc string) {
+	h.t.Helper()
+
+	timeout := time.NewTimer(h.Timeout)
+	defer timeout.Stop()
+
+	for !cond(h.View()) {
+		select {
+		case msg := <-h.msgs:
+			h.Send(msg)
+		case <-timeout.C:
+			h.t.Fatalf("не дождались: %s\n--- вывод ---\n%s", desc, h.View())
+		}
+	}
+}
+
+// Drain - обработка результатов команд, пришедших за время d
+func (h *Harness) Drain(d time.Duration) {
+	h.t.Helper()
+
+	timeout := time.NewTimer(d)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case msg := <-h.msgs:
+			h.Send(msg)
+		case <-timeout.C:
+			return
+		}
+	}
+}
+
+// Golden - сравнение вывода с testdata/<name>.golden.
+// С флагом -update файл перезаписывается.
+func (h *Harness) Golden(name string) {
+	h.t.Helper()
+	AssertGolden(h.t, name, h.View())
+}
+
+// AssertGolden - сравнение got с testdata/<name>.golden
+func AssertGolden(t testing.TB, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("ошибка создания каталога testdata: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("ошибка записи golden файла: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ошибка чтения golden файла (запустите с -update): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("вывод отличается от %s\n--- получено ---\n%s\n--- ожидалось ---\n%s", path, got, want)
+	}
+}
+
+// Clean - удаление ANSI последовательностей и пробелов в конце строк
+func Clean(view string) string {
+	lines := strings.Split(ansiPattern.ReplaceAllString(view, ""), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// exec - выполнение команды в фоне с доставкой результата в msgs
+func (h *Harness) exec(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	go h.run(cmd)
+}
+
+// run - выполнение команды с разбором tea.Batch и tea.Sequence
+func (h *Harness) run(cmd tea.Cmd) {
+	msg := cmd()
+	if msg == nil {
+		return
+	}
+
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			h.exec(c)
+		}
+		return
+	}
+
+	// tea.Sequence возвращает неэкспортируемый срез команд
+	if cmds, ok := sequence(msg); ok {
+		for _, c := range cmds {
+			if c != nil {
+				h.run(c)
+			}
+		}
+		return
+	}
+
+	select {
+	case h.msgs <- msg:
+	case <-h.done:
+	}
+}
+
+// sequence - команды из сообщения tea.Sequence
+func sequence(msg tea.Msg) ([]tea.Cmd, bool) {
+	v := reflect.ValueOf(msg)
+	cmdType := reflect.TypeOf(tea.Cmd(nil))
+	if v.Kind() != reflect.Slice || v.Type().Elem() != cmdType {
+		return nil, false
+	}
+
+	cmds := make([]tea.Cmd, v.Len())
+	for i := range cmds {
+		cmds[i] = v.Index(i).Interface().(tea.Cmd)
+	}
+	return cmds, true
+}
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"lesta-start-battleship/cli/internal/api/auth"
+	"lesta-start-battleship/cli/internal/cli/output"
+	"lesta-start-battleship/cli/storage/profile"
+)
+
+// EnvVarPassword - переменная окружения с паролем для неинтерактивного входа
+const EnvVarPassword = "BATTLESHIP_PASSWORD"
+
+func loginCommand() *Command {
+	var passwordStdin bool
+	return &Command{
+		Name:  "login",
+		Args:  "USERNAME",
+		Short: "вход по логину и паролю, пароль берется из " + EnvVarPassword + " или stdin",
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&passwordStdin, "password-stdin", false, "прочитать пароль из первой строки stdin")
+		},
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args, "USERNAME"); err != nil {
+				return err
+			}
+
+			password := os.Getenv(EnvVarPassword)
+			if passwordStdin || password == "" {
+				line, err := bufio.NewReader(env.Stdin).ReadString('\n')
+				if err != nil && line == "" {
+					return fmt.Errorf("ошибка чтения пароля: %w", err)
+				}
+				password = strings.TrimRight(line, "\r\n")
+			}
+
+			_, user, err := env.Clients.AuthClient.Login(ctx, auth.LoginRequest{
+				Username: args[0],
+				Password: password,
+			})
+			if err != nil {
+				return err
+			}
+			if user == nil {
+				return fmt.Errorf("не удалось получить профиль пользователя")
+			}
+
+			if profiles := env.Clients.Profiles; profiles != nil {
+				if err := profiles.SaveSession(env.Clients.Profile, user.ID, user.Username); err != nil {
+					return err
+				}
+				if err := profiles.UpdateSettings(env.Clients.Profile, func(s *profile.Settings) {
+					s.LastLogin = args[0]
+				}); err != nil {
+					return err
+				}
+			}
+
+			return env.write(output.Message(fmt.Sprintf("Вход выполнен: %s (id %d)", user.Username, user.ID)))
+		},
+	}
+}
+
+func logoutCommand() *Command {
+	return &Command{
+		Name:  "logout",
+		Short: "выход из системы и удаление сохраненной сессии",
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args); err != nil {
+				return err
+			}
+			if err := env.Clients.AuthClient.Logout(ctx); err != nil {
+				return err
+			}
+			return env.write(output.Message("Выход выполнен"))
+		},
+	}
+}
+
+func whoamiCommand() *Command {
+	return &Command{
+		Name:  "whoami",
+		Short: "текущий профиль и сессия",
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args); err != nil {
+				return err
+			}
+			if _, err := requireUser(env); err != nil {
+				return err
+			}
+
+			user, err := env.Clients.AuthClient.GetProfile(ctx)
+			if err != nil {
+				return err
+			}
+
+			var expiresAt *time.Time
+			if claims, ok := env.Clients.AuthClient.Session(); ok && !claims.ExpiresAt.IsZero() {
+				expiresAt = &claims.ExpiresAt
+			}
+			return env.write(output.Session(env.Clients.Profile, user, expiresAt))
+		},
+	}
+}
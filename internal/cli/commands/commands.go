@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"lesta-start-battleship/cli/internal/api/cache"
+	"lesta-start-battleship/cli/internal/cli/output"
+	"lesta-start-battleship/cli/internal/clientdeps"
+)
+
+// EnvVarOutput - переменная окружения с форматом вывода по умолчанию
+const EnvVarOutput = "BATTLESHIP_OUTPUT"
+
+// Env - окружение выполнения подкоманды
+type Env struct {
+	Clients *clientdeps.Client
+	Stdin   io.Reader
+	Stdout  io.Writer
+	Stderr  io.Writer
+	Output  output.Format // формат вывода, задается флагом -output команды
+}
+
+// write - вывод результата команды в выбранном формате
+func (e *Env) write(v output.View) error {
+	return output.Write(e.Stdout, e.Output, v)
+}
+
+// Command - узел дерева подкоманд.
+// У листовой команды задан Run, у группы - Subcommands.
+type Command struct {
+	Name        string
+	Args        string // описание позиционных аргументов для справки
+	Short       string
+	Flags       func(fs *flag.FlagSet)
+	Run         func(ctx context.Context, env *Env, args []string) error
+	Subcommands []*Command
+}
+
+// UsageError - ошибка вызова команды: неизвестная команда, неверные аргументы или флаги
+type UsageError struct {
+	Msg string
+}
+
+func (e *UsageError) Error() string {
+	return e.Msg
+}
+
+// Root - дерево всех подкоманд клиента
+func Root() *Command {
+	return &Command{
+		Name: "cli",
+		Subcommands: []*Command{
+			loginCommand(),
+			logoutCommand(),
+			whoamiCommand(),
+			guildCommand(),
+			shopCommand(),
+			scoreboardCommand(),
+			inventoryCommand(),
+		},
+	}
+}
+
+// Run - выполнение подкоманды по аргументам командной строки
+func Run(ctx context.Context, env *Env, args []string) error {
+	return Root().execute(ctx, env, nil, args)
+}
+
+func (c *Command) execute(ctx context.Context, env *Env, path []string, args []string) error {
+	path = append(path, c.Name)
+
+	if len(c.Subcommands) > 0 {
+		if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+			c.printUsage(env.Stdout, path)
+			return nil
+		}
+		for _, sub := range c.Subcommands {
+			if sub.Name == args[0] {
+				return sub.execute(ctx, env, path, args[1:])
+			}
+		}
+		c.printUsage(env.Stderr, path)
+		return &UsageError{Msg: fmt.Sprintf("неизвестная команда %q", strings.Join(append(path[1:], args[0]), " "))}
+	}
+
+	fs := flag.NewFlagSet(strings.Join(path, " "), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if c.Flags != nil {
+		c.Flags(fs)
+	}
+	format := os.Getenv(EnvVarOutput)
+	if format == "" {
+		format = string(output.FormatTable)
+	}
+	fs.StringVar(&format, "output", format, "формат вывода: table, json, yaml, csv")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			c.printUsage(env.Stdout, path)
+			fs.SetOutput(env.Stdout)
+			fs.PrintDefaults()
+			return nil
+		}
+		return &UsageError{Msg: err.Error()}
+	}
+
+	parsed, err := output.ParseFormat(format)
+	if err != nil {
+		return &UsageError{Msg: err.Error()}
+	}
+	env.Output = parsed
+
+	ctx, status := cache.Track(ctx)
+	err = c.Run(ctx, env, fs.Args())
+	if storedAt, offline := status.Offline(); offline {
+		fmt.Fprintf(env.Stderr, "офлайн: сервер недоступен, показаны данные от %s\n", storedAt.Format("02.01.2006 15:04"))
+	}
+	return err
+}
+
+func (c *Command) printUsage(w io.Writer, path []string) {
+	usage := strings.Join(path, " ")
+	if len(c.Subcommands) > 0 {
+		fmt.Fprintf(w, "Использование: %s <команда> [флаги] [аргументы]\n\nКоманды:\n", usage)
+		for _, sub := range c.Subcommands {
+			name := sub.Name
+			if sub.Args != "" {
+				name += " " + sub.Args
+			}
+			fmt.Fprintf(w, "  %-24s %s\n", name, sub.Short)
+		}
+		return
+	}
+
+	usage += " [флаги]"
+	if c.Args != "" {
+		usage += " " + c.Args
+	}
+	fmt.Fprintf(w, "Использование: %s\n%s\n", usage, c.Short)
+}
+
+// exactArgs - проверка количества позиционных аргументов
+func exactArgs(args []string, names ...string) error {
+	if len(args) != len(names) {
+		return &UsageError{Msg: fmt.Sprintf("ожидаются аргументы: %s", strings.Join(names, " "))}
+	}
+	return nil
+}
+
+// intArg - разбор числового аргумента
+func intArg(value, name string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &UsageError{Msg: fmt.Sprintf("%s должен быть числом: %q", name, value)}
+	}
+	return n, nil
+}
+
+// requireUser - ID пользователя текущей сессии
+func requireUser(env *Env) (int, error) {
+	userID := env.Clients.AuthClient.UserID()
+	if userID == 0 {
+		return 0, fmt.Errorf("нет активной сессии: выполните cli login")
+	}
+	return userID, nil
+}
@@ -0,0 +1,176 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"lesta-start-battleship/cli/internal/cli/clitest"
+	"lesta-start-battleship/cli/internal/cli/commands"
+	"lesta-start-battleship/cli/internal/clientdeps"
+)
+
+// runner - выполнение команд с клиентами mock-сервера и захватом вывода
+type runner struct {
+	t       *testing.T
+	clients *clientdeps.Client
+}
+
+func newRunner(t *testing.T) *runner {
+	t.Setenv(commands.EnvVarOutput, "")
+	t.Setenv(commands.EnvVarPassword, "")
+	backend := clitest.NewBackend(t)
+	return &runner{t: t, clients: backend.Clients(t)}
+}
+
+// run - выполнение команды args, stdin - ввод команды
+func (r *runner) run(stdin string, args ...string) (stdout, stderr string, err error) {
+	r.t.Helper()
+	var out, errOut bytes.Buffer
+	env := &commands.Env{
+		Clients: r.clients,
+		Stdin:   strings.NewReader(stdin),
+		Stdout:  &out,
+		Stderr:  &errOut,
+	}
+	err = commands.Run(context.Background(), env, args)
+	return out.String(), errOut.String(), err
+}
+
+// login - вход пользователем username с паролем из stdin
+func (r *runner) login(username string) {
+	r.t.Helper()
+	if _, _, err := r.run(username+"\n", "login", "-password-stdin", username); err != nil {
+		r.t.Fatalf("ошибка входа %s: %v", username, err)
+	}
+}
+
+func isUsage(err error) bool {
+	var usage *commands.UsageError
+	return errors.As(err, &usage)
+}
+
+func TestUsage(t *testing.T) {
+	r := newRunner(t)
+
+	out, _, err := r.run("", "help")
+	if err != nil || !strings.Contains(out, "guild") || !strings.Contains(out, "inventory") {
+		t.Fatalf("help: %v\n%s", err, out)
+	}
+
+	out, _, err = r.run("", "guild", "members", "-h")
+	if err != nil || !strings.Contains(out, "Использование: cli guild members [флаги] TAG") || !strings.Contains(out, "-limit") {
+		t.Fatalf("guild members -h: %v\n%s", err, out)
+	}
+
+	_, stderr, err := r.run("", "guild", "fleet")
+	if !isUsage(err) || !strings.Contains(err.Error(), `"guild fleet"`) {
+		t.Fatalf("неизвестная команда: ожидалась UsageError, получено %v", err)
+	}
+	if !strings.Contains(stderr, "Команды:") {
+		t.Errorf("справка по группе должна выводиться в stderr:\n%s", stderr)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	r := newRunner(t)
+	r.login("admiral")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"неизвестный флаг", []string{"guild", "list", "-page", "2"}},
+		{"нечисловой флаг", []string{"guild", "list", "-limit", "много"}},
+		{"лишний аргумент", []string{"guild", "list", "WOLF"}},
+		{"нет аргумента", []string{"guild", "members"}},
+		{"нечисловой аргумент", []string{"shop", "buy", "бомба"}},
+		{"неизвестный формат", []string{"guild", "list", "-output", "xml"}},
+		// флаги разбираются только до первого аргумента, остальное - лишние аргументы
+		{"флаг после аргумента", []string{"guild", "members", "WOLF", "-output", "json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _, err := r.run("", tt.args...)
+			if !isUsage(err) {
+				t.Fatalf("%v: ожидалась UsageError, получено %v", tt.args, err)
+			}
+			if out != "" {
+				t.Errorf("при ошибке вызова ничего не выводится в stdout:\n%s", out)
+			}
+		})
+	}
+}
+
+// TestFlagsBeforeArguments - флаги перед аргументом применяются, включая -output
+func TestFlagsBeforeArguments(t *testing.T) {
+	r := newRunner(t)
+	r.login("admiral")
+
+	out, _, err := r.run("", "guild", "members", "-limit", "1", "-output", "csv", "WOLF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || lines[0] != "user_id,user_name,guild_id,guild_tag,role_id,role_title" {
+		t.Fatalf("ожидались заголовок и одна строка CSV:\n%s", out)
+	}
+}
+
+// TestOutputEnv - формат по умолчанию из BATTLESHIP_OUTPUT, флаг -output его переопределяет
+func TestOutputEnv(t *testing.T) {
+	r := newRunner(t)
+	r.login("admiral")
+
+	t.Setenv(commands.EnvVarOutput, "json")
+	out, _, err := r.run("", "guild", "info", "WOLF")
+	if err != nil || !strings.HasPrefix(out, "{") {
+		t.Fatalf("ожидался JSON: %v\n%s", err, out)
+	}
+
+	out, _, err = r.run("", "guild", "info", "-output", "csv", "WOLF")
+	if err != nil || !strings.HasPrefix(out, "id,tag,title") {
+		t.Fatalf("ожидался CSV: %v\n%s", err, out)
+	}
+
+	t.Setenv(commands.EnvVarOutput, "xml")
+	if _, _, err := r.run("", "guild", "info", "WOLF"); !isUsage(err) {
+		t.Fatalf("неизвестный формат в %s: ожидалась UsageError, получено %v", commands.EnvVarOutput, err)
+	}
+}
+
+// TestCommandsGolden - вывод команд в форматах table, json и csv с данными mock-сервера
+func TestCommandsGolden(t *testing.T) {
+	r := newRunner(t)
+	r.login("admiral")
+
+	tests := []struct {
+		name    string
+		command []string // путь команды, флаг -output добавляется после него
+		args    []string
+	}{
+		{"guild_list", []string{"guild", "list"}, nil},
+		{"guild_info", []string{"guild", "info"}, []string{"WOLF"}},
+		{"guild_members", []string{"guild", "members"}, []string{"WOLF"}},
+		{"shop_products", []string{"shop", "products"}, nil},
+		{"shop_chests", []string{"shop", "chests"}, nil},
+		{"inventory", []string{"inventory"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got strings.Builder
+			for _, format := range []string{"table", "json", "csv"} {
+				args := append(append(append([]string{}, tt.command...), "-output", format), tt.args...)
+				out, _, err := r.run("", args...)
+				if err != nil {
+					t.Fatalf("%s: %v", strings.Join(args, " "), err)
+				}
+				got.WriteString("== " + format + " ==\n")
+				got.WriteString(clitest.Clean(out))
+			}
+			clitest.AssertGolden(t, tt.name, got.String())
+		})
+	}
+}
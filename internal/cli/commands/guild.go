@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/cli/output"
+)
+
+func guildCommand() *Command {
+	return &Command{
+		Name:  "guild",
+		Short: "гильдии",
+		Subcommands: []*Command{
+			guildListCommand(),
+			guildInfoCommand(),
+			guildMembersCommand(),
+			guildJoinCommand(),
+			guildLeaveCommand(),
+			guildRequestsCommand(),
+			guildWarsCommand(),
+			guildAuditCommand(),
+		},
+	}
+}
+
+func guildListCommand() *Command {
+	var offset, limit int
+	return &Command{
+		Name:  "list",
+		Short: "список гильдий",
+		Flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&offset, "offset", 0, "смещение")
+			fs.IntVar(&limit, "limit", 20, "количество гильдий")
+		},
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args); err != nil {
+				return err
+			}
+
+			guilds, err := env.Clients.GuildsClient.GetGuilds(ctx, offset, limit)
+			if err != nil {
+				return fmt.Errorf("ошибка получения гильдий: %w", err)
+			}
+
+			return env.write(output.Guilds(guilds))
+		},
+	}
+}
+
+func guildInfoCommand() *Command {
+	return &Command{
+		Name:  "info",
+		Args:  "TAG",
+		Short: "информация о гильдии",
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args, "TAG"); err != nil {
+				return err
+			}
+
+			guild, err := env.Clients.GuildsClient.GetGuildByTag(ctx, args[0])
+			if err != nil {
+				return fmt.Errorf("ошибка получения гильдии: %w", err)
+			}
+			if guild == nil {
+				return fmt.Errorf("гильдия %s не найдена", args[0])
+			}
+
+			return env.write(output.Guild(guild))
+		},
+	}
+}
+
+func guildMembersCommand() *Command {
+	var offset, limit int
+	return &Command{
+		Name:  "members",
+		Args:  "TAG",
+		Short: "участники гильдии",
+		Flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&offset, "offset", 0, "смещение")
+			fs.IntVar(&limit, "limit", 50, "количество участников")
+		},
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args, "TAG"); err != nil {
+				return err
+			}
+
+			members, err := env.Clients.GuildsClient.GetGuildMembers(ctx, args[0], offset, limit)
+			if err != nil {
+				return fmt.Errorf("ошибка получения участников: %w", err)
+			}
+
+			return env.write(output.Members(members))
+		},
+	}
+}
+
+func guildJoinCommand() *Command {
+	return &Command{
+		Name:  "join",
+		Args:  "TAG",
+		Short: "отправить заявку на вступление",
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args, "TAG"); err != nil {
+				return err
+			}
+			userID, err := requireUser(env)
+			if err != nil {
+				return err
+			}
+
+			if err := env.Clients.GuildsClient.SendJoinRequest(ctx, args[0], userID); err != nil {
+				return fmt.Errorf("ошибка отправки заявки: %w", err)
+			}
+			return env.write(output.Message(fmt.Sprintf("Заявка в гильдию %s отправлена", args[0])))
+		},
+	}
+}
+
+func guildLeaveCommand() *Command {
+	return &Command{
+		Name:  "leave",
+		Args:  "TAG",
+		Short: "выйти из гильдии",
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args, "TAG"); err != nil {
+				return err
+			}
+
+			if err := env.Clients.GuildsClient.ExitGuild(ctx, args[0]); err != nil {
+				return fmt.Errorf("ошибка выхода из гильдии: %w", err)
+			}
+			return env.write(output.Message(fmt.Sprintf("Вы вышли из гильдии %s", args[0])))
+		},
+	}
+}
+
+func guildRequestsCommand() *Command {
+	return &Command{
+		Name:  "requests",
+		Args:  "TAG",
+		Short: "заявки на вступление (для владельца и офицеров)",
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args, "TAG"); err != nil {
+				return err
+			}
+			userID, err := requireUser(env)
+			if err != nil {
+				return err
+			}
+
+			requests, err := env.Clients.GuildsClient.GetJoinRequests(ctx, args[0], userID)
+			if err != nil {
+				return fmt.Errorf("ошибка получения заявок: %w", err)
+			}
+
+			return env.write(output.JoinRequests(requests))
+		},
+	}
+}
+
+func guildWarsCommand() *Command {
+	var status string
+	var page, pageSize int
+	return &Command{
+		Name:  "wars",
+		Args:  "TAG",
+		Short: "войны гильдии",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&status, "status", "", "фильтр по статусу войны")
+			fs.IntVar(&page, "page", 1, "страница")
+			fs.IntVar(&pageSize, "page-size", 20, "размер страницы")
+		},
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args, "TAG"); err != nil {
+				return err
+			}
+			userID, err := requireUser(env)
+			if err != nil {
+				return err
+			}
+
+			guild, err := env.Clients.GuildsClient.GetGuildByTag(ctx, args[0])
+			if err != nil {
+				return fmt.Errorf("ошибка получения гильдии: %w", err)
+			}
+			if guild == nil {
+				return fmt.Errorf("гильдия %s не найдена", args[0])
+			}
+
+			var statusFilter *guilds.WarStatus
+			if status != "" {
+				s := guilds.WarStatus(status)
+				statusFilter = &s
+			}
+
+			wars, err := env.Clients.GuildsClient.GetGuildWarList(ctx, userID, guild.ID, nil, nil, statusFilter, page, pageSize)
+			if err != nil {
+				return fmt.Errorf("ошибка получения войн: %w", err)
+			}
+			return env.write(output.Wars(wars))
+		},
+	}
+}
+
+func guildAuditCommand() *Command {
+	var offset, limit int
+	return &Command{
+		Name:  "audit",
+		Args:  "TAG",
+		Short: "журнал действий гильдии (для владельца и офицеров)",
+		Flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&offset, "offset", 0, "смещение")
+			fs.IntVar(&limit, "limit", 20, "количество записей")
+		},
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args, "TAG"); err != nil {
+				return err
+			}
+			userID, err := requireUser(env)
+			if err != nil {
+				return err
+			}
+
+			entries, err := env.Clients.GuildsClient.GetAuditLog(ctx, args[0], userID, offset, limit)
+			if errors.Is(err, guilds.ErrAuditUnavailable) {
+				// сервер без журнала - действия, выполненные с этого устройства
+				entries, err = env.Clients.Audit.Page(args[0], offset, limit), nil
+			}
+			if err != nil {
+				return fmt.Errorf("ошибка получения журнала: %w", err)
+			}
+			return env.write(output.AuditLog(entries))
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"lesta-start-battleship/cli/internal/cli/output"
+)
+
+func inventoryCommand() *Command {
+	return &Command{
+		Name:  "inventory",
+		Short: "инвентарь текущего пользователя",
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args); err != nil {
+				return err
+			}
+			if _, err := requireUser(env); err != nil {
+				return err
+			}
+
+			inventory, err := env.Clients.InventoryClient.GetUserInventory(ctx)
+			if err != nil {
+				return fmt.Errorf("ошибка получения инвентаря: %w", err)
+			}
+
+			return env.write(output.Inventory(inventory))
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"lesta-start-battleship/cli/internal/cli/output"
+)
+
+// scoreboardFlags - общие флаги рейтингов
+type scoreboardFlags struct {
+	name    string
+	order   string
+	reverse bool
+	limit   int
+	page    int
+}
+
+func (f *scoreboardFlags) register(fs *flag.FlagSet, defaultOrder string) {
+	fs.StringVar(&f.name, "name", "", "фильтр по имени")
+	fs.StringVar(&f.order, "order", defaultOrder, "поле сортировки")
+	fs.BoolVar(&f.reverse, "reverse", false, "обратный порядок")
+	fs.IntVar(&f.limit, "limit", 10, "количество записей")
+	fs.IntVar(&f.page, "page", 1, "страница")
+}
+
+func scoreboardCommand() *Command {
+	return &Command{
+		Name:  "scoreboard",
+		Short: "рейтинги",
+		Subcommands: []*Command{
+			scoreboardUsersCommand(),
+			scoreboardGuildsCommand(),
+		},
+	}
+}
+
+func scoreboardUsersCommand() *Command {
+	var f scoreboardFlags
+	return &Command{
+		Name:  "users",
+		Short: "рейтинг игроков (order: rating, gold, experience, chest_opened)",
+		Flags: func(fs *flag.FlagSet) {
+			f.register(fs, "rating")
+		},
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args); err != nil {
+				return err
+			}
+
+			stats, err := env.Clients.ScoreboardClient.GetUserStats(ctx, nil, f.name, f.order, f.reverse, f.limit, f.page)
+			if err != nil {
+				return fmt.Errorf("ошибка получения рейтинга: %w", err)
+			}
+
+			return env.write(output.UserStats(stats))
+		},
+	}
+}
+
+func scoreboardGuildsCommand() *Command {
+	var f scoreboardFlags
+	return &Command{
+		Name:  "guilds",
+		Short: "рейтинг гильдий (order: guild_members, wars_victories)",
+		Flags: func(fs *flag.FlagSet) {
+			f.register(fs, "wars_victories")
+		},
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args); err != nil {
+				return err
+			}
+
+			stats, err := env.Clients.ScoreboardClient.GetGuildStats(ctx, nil, f.name, f.order, f.reverse, f.limit, f.page)
+			if err != nil {
+				return fmt.Errorf("ошибка получения рейтинга: %w", err)
+			}
+
+			return env.write(output.GuildStats(stats))
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"lesta-start-battleship/cli/internal/cli/output"
+)
+
+func shopCommand() *Command {
+	return &Command{
+		Name:  "shop",
+		Short: "магазин",
+		Subcommands: []*Command{
+			shopProductsCommand(),
+			shopChestsCommand(),
+			shopPromotionsCommand(),
+			shopBuyCommand(),
+			shopBuyChestCommand(),
+			shopOpenChestCommand(),
+		},
+	}
+}
+
+func shopProductsCommand() *Command {
+	return &Command{
+		Name:  "products",
+		Short: "список товаров",
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args); err != nil {
+				return err
+			}
+
+			products, err := env.Clients.ShopClient.GetProducts(ctx)
+			if err != nil {
+				return fmt.Errorf("ошибка получения товаров: %w", err)
+			}
+
+			return env.write(output.Products(products))
+		},
+	}
+}
+
+func shopChestsCommand() *Command {
+	return &Command{
+		Name:  "chests",
+		Short: "список сундуков",
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args); err != nil {
+				return err
+			}
+
+			chests, err := env.Clients.ShopClient.GetChests(ctx)
+			if err != nil {
+				return fmt.Errorf("ошибка получения сундуков: %w", err)
+			}
+
+			return env.write(output.Chests(chests))
+		},
+	}
+}
+
+func shopPromotionsCommand() *Command {
+	return &Command{
+		Name:  "promotions",
+		Short: "список акций",
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args); err != nil {
+				return err
+			}
+
+			promotions, err := env.Clients.ShopClient.GetPromotions(ctx)
+			if err != nil {
+				return fmt.Errorf("ошибка получения акций: %w", err)
+			}
+
+			return env.write(output.Promotions(promotions))
+		},
+	}
+}
+
+func shopBuyCommand() *Command {
+	return &Command{
+		Name:  "buy",
+		Args:  "ITEM_ID",
+		Short: "купить товар",
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args, "ITEM_ID"); err != nil {
+				return err
+			}
+			itemID, err := intArg(args[0], "ITEM_ID")
+			if err != nil {
+				return err
+			}
+
+			if err := env.Clients.ShopClient.BuyProduct(ctx, itemID); err != nil {
+				return fmt.Errorf("ошибка покупки: %w", err)
+			}
+			return env.write(output.Message(fmt.Sprintf("Товар %d куплен", itemID)))
+		},
+	}
+}
+
+func shopBuyChestCommand() *Command {
+	return &Command{
+		Name:  "buy-chest",
+		Args:  "CHEST_ID",
+		Short: "купить сундук",
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args, "CHEST_ID"); err != nil {
+				return err
+			}
+			chestID, err := intArg(args[0], "CHEST_ID")
+			if err != nil {
+				return err
+			}
+
+			if err := env.Clients.ShopClient.BuyChest(ctx, chestID); err != nil {
+				return fmt.Errorf("ошибка покупки: %w", err)
+			}
+			return env.write(output.Message(fmt.Sprintf("Сундук %d куплен", chestID)))
+		},
+	}
+}
+
+func shopOpenChestCommand() *Command {
+	var amount int
+	return &Command{
+		Name:  "open-chest",
+		Args:  "CHEST_ID",
+		Short: "открыть сундуки из инвентаря",
+		Flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&amount, "amount", 1, "количество сундуков")
+		},
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := exactArgs(args, "CHEST_ID"); err != nil {
+				return err
+			}
+			chestID, err := intArg(args[0], "CHEST_ID")
+			if err != nil {
+				return err
+			}
+
+			if err := env.Clients.ShopClient.OpenChest(ctx, chestID, amount); err != nil {
+				return fmt.Errorf("ошибка открытия сундука: %w", err)
+			}
+			return env.write(output.Message(fmt.Sprintf("Открыто сундуков %d: %d", chestID, amount)))
+		},
+	}
+}
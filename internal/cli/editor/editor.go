@@ -0,0 +1,293 @@
+// Package editor - редактируемая строка ввода для текстового интерфейса.
+// Буфер хранит руны, поэтому курсор и удаление работают с кириллицей и другими многобайтовыми символами.
+package editor
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HistoryLimit - сколько отправленных строк хранится для вызова по ↑
+const HistoryLimit = 50
+
+// Editor - многострочный буфер ввода с курсором, ограничением длины и историей отправленных строк
+type Editor struct {
+	runes  []rune
+	cursor int // позиция курсора в рунах, от 0 до len(runes)
+	limit  int // максимальная длина в рунах, 0 - без ограничения
+
+	history []string
+	recall  int    // индекс в history, len(history) - редактируется новая строка
+	draft   string // новая строка, сохраненная на время просмотра истории
+}
+
+// New - пустой буфер, limit - максимальная длина в символах, 0 - без ограничения
+func New(limit int) *Editor {
+	return &Editor{limit: limit}
+}
+
+// Value - текст буфера
+func (e *Editor) Value() string {
+	return string(e.runes)
+}
+
+// Len - длина текста в символах
+func (e *Editor) Len() int {
+	return len(e.runes)
+}
+
+// Limit - максимальная длина в символах, 0 - без ограничения
+func (e *Editor) Limit() int {
+	return e.limit
+}
+
+// Cursor - позиция курсора в символах
+func (e *Editor) Cursor() int {
+	return e.cursor
+}
+
+// Empty - в буфере нет ничего, кроме пробелов и переводов строк
+func (e *Editor) Empty() bool {
+	return strings.TrimSpace(e.Value()) == ""
+}
+
+// Insert - вставка текста в позицию курсора.
+// Переводы строк приводятся к \n, табуляция заменяется пробелом, лишнее сверх лимита отбрасывается.
+func (e *Editor) Insert(s string) {
+	s = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\t", " ").Replace(s)
+	insert := []rune(s)
+	if e.limit > 0 {
+		insert = insert[:min(len(insert), max(e.limit-len(e.runes), 0))]
+	}
+	if len(insert) == 0 {
+		return
+	}
+
+	e.runes = append(e.runes[:e.cursor], append(insert, e.runes[e.cursor:]...)...)
+	e.cursor += len(insert)
+}
+
+// BeforeCursor - текст от начала буфера до курсора
+func (e *Editor) BeforeCursor() string {
+	return string(e.runes[:e.cursor])
+}
+
+// Word - слово перед курсором: символы от последнего пробела до курсора
+func (e *Editor) Word() string {
+	return string(e.runes[e.wordStart():e.cursor])
+}
+
+// ReplaceWord - замена слова перед курсором, например при автодополнении
+func (e *Editor) ReplaceWord(s string) {
+	e.delete(e.wordStart(), e.cursor)
+	e.Insert(s)
+}
+
+// Submit - текст буфера для отправки: буфер очищается, текст попадает в историю
+func (e *Editor) Submit() string {
+	value := e.Value()
+	if strings.TrimSpace(value) != "" && (len(e.history) == 0 || e.history[len(e.history)-1] != value) {
+		e.history = append(e.history, value)
+		if len(e.history) > HistoryLimit {
+			e.history = e.history[len(e.history)-HistoryLimit:]
+		}
+	}
+	e.Reset()
+	return value
+}
+
+// Reset - очистка буфера, история сохраняется
+func (e *Editor) Reset() {
+	e.runes = e.runes[:0]
+	e.cursor = 0
+	e.recall = len(e.history)
+	e.draft = ""
+}
+
+// Update - обработка клавиши редактирования. Возвращает false, если клавиша не относится к вводу.
+// Enter не обрабатывается: отправку решает владелец буфера, новая строка - Alt+Enter или Ctrl+J.
+func (e *Editor) Update(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyRunes:
+		if msg.Alt && len(msg.Runes) == 1 {
+			switch msg.Runes[0] {
+			case 'b':
+				e.wordLeft()
+				return true
+			case 'f':
+				e.wordRight()
+				return true
+			}
+			return false
+		}
+		e.Insert(string(msg.Runes))
+	case tea.KeySpace:
+		e.Insert(" ")
+	case tea.KeyCtrlJ:
+		e.Insert("\n")
+	case tea.KeyEnter:
+		if !msg.Alt {
+			return false
+		}
+		e.Insert("\n")
+
+	case tea.KeyBackspace, tea.KeyCtrlH:
+		if msg.Alt {
+			e.deleteWordLeft()
+		} else if e.cursor > 0 {
+			e.delete(e.cursor-1, e.cursor)
+		}
+	case tea.KeyDelete:
+		if e.cursor < len(e.runes) {
+			e.delete(e.cursor, e.cursor+1)
+		}
+	case tea.KeyCtrlW:
+		e.deleteWordLeft()
+	case tea.KeyCtrlU:
+		e.delete(e.lineStart(), e.cursor)
+	case tea.KeyCtrlK:
+		e.delete(e.cursor, e.lineEnd())
+
+	case tea.KeyLeft:
+		if msg.Alt {
+			e.wordLeft()
+		} else if e.cursor > 0 {
+			e.cursor--
+		}
+	case tea.KeyRight:
+		if msg.Alt {
+			e.wordRight()
+		} else if e.cursor < len(e.runes) {
+			e.cursor++
+		}
+	case tea.KeyHome, tea.KeyCtrlA:
+		e.cursor = e.lineStart()
+	case tea.KeyEnd, tea.KeyCtrlE:
+		e.cursor = e.lineEnd()
+
+	case tea.KeyUp:
+		// в многострочном тексте стрелки двигают курсор по строкам, с первой строки - история
+		if start := e.lineStart(); start > 0 {
+			e.moveToLine(e.lineStartAt(start-1), e.cursor-start)
+		} else {
+			e.recallPrev()
+		}
+	case tea.KeyDown:
+		if end := e.lineEnd(); end < len(e.runes) {
+			e.moveToLine(end+1, e.cursor-e.lineStart())
+		} else {
+			e.recallNext()
+		}
+
+	default:
+		return false
+	}
+	return true
+}
+
+// delete - удаление символов [from, to), курсор встает на from
+func (e *Editor) delete(from, to int) {
+	if from >= to {
+		return
+	}
+	e.runes = append(e.runes[:from], e.runes[to:]...)
+	e.cursor = from
+}
+
+// deleteWordLeft - удаление слова перед курсором вместе с пробелами после него
+func (e *Editor) deleteWordLeft() {
+	end := e.cursor
+	e.wordLeft()
+	e.delete(e.cursor, end)
+}
+
+// wordLeft - курсор в начало текущего или предыдущего слова
+func (e *Editor) wordLeft() {
+	for e.cursor > 0 && unicode.IsSpace(e.runes[e.cursor-1]) {
+		e.cursor--
+	}
+	for e.cursor > 0 && !unicode.IsSpace(e.runes[e.cursor-1]) {
+		e.cursor--
+	}
+}
+
+// wordRight - курсор в конец текущего или следующего слова
+func (e *Editor) wordRight() {
+	for e.cursor < len(e.runes) && unicode.IsSpace(e.runes[e.cursor]) {
+		e.cursor++
+	}
+	for e.cursor < len(e.runes) && !unicode.IsSpace(e.runes[e.cursor]) {
+		e.cursor++
+	}
+}
+
+// wordStart - начало слова перед курсором
+func (e *Editor) wordStart() int {
+	pos := e.cursor
+	for pos > 0 && !unicode.IsSpace(e.runes[pos-1]) {
+		pos--
+	}
+	return pos
+}
+
+// lineStart - начало строки с курсором
+func (e *Editor) lineStart() int {
+	return e.lineStartAt(e.cursor)
+}
+
+// lineStartAt - начало строки, в которой находится позиция pos
+func (e *Editor) lineStartAt(pos int) int {
+	for pos > 0 && e.runes[pos-1] != '\n' {
+		pos--
+	}
+	return pos
+}
+
+// lineEnd - конец строки с курсором, позиция перевода строки или конец текста
+func (e *Editor) lineEnd() int {
+	pos := e.cursor
+	for pos < len(e.runes) && e.runes[pos] != '\n' {
+		pos++
+	}
+	return pos
+}
+
+// moveToLine - курсор в строку, начинающуюся с start, на колонку col или в конец более короткой строки
+func (e *Editor) moveToLine(start, col int) {
+	e.cursor = start
+	end := e.lineEnd()
+	e.cursor = min(start+col, end)
+}
+
+// recallPrev - предыдущая отправленная строка, текущий ввод сохраняется
+func (e *Editor) recallPrev() {
+	if e.recall == 0 {
+		return
+	}
+	if e.recall == len(e.history) {
+		e.draft = e.Value()
+	}
+	e.recall--
+	e.show(e.history[e.recall])
+}
+
+// recallNext - следующая строка истории, после последней - сохраненный ввод
+func (e *Editor) recallNext() {
+	if e.recall >= len(e.history) {
+		return
+	}
+	e.recall++
+	if e.recall == len(e.history) {
+		e.show(e.draft)
+		return
+	}
+	e.show(e.history[e.recall])
+}
+
+// show - замена текста без сброса просмотра истории
+func (e *Editor) show(s string) {
+	e.runes = append(e.runes[:0], []rune(s)...)
+	e.cursor = len(e.runes)
+}
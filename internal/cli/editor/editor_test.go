@@ -0,0 +1,117 @@
+package editor
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// press - последовательность клавиш
+func press(e *Editor, keys ...tea.KeyMsg) {
+	for _, key := range keys {
+		e.Update(key)
+	}
+}
+
+func typed(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func key(t tea.KeyType) tea.KeyMsg {
+	return tea.KeyMsg{Type: t}
+}
+
+func alt(t tea.KeyType) tea.KeyMsg {
+	return tea.KeyMsg{Type: t, Alt: true}
+}
+
+func TestEditorCyrillic(t *testing.T) {
+	e := New(0)
+	press(e, typed("Привет"), key(tea.KeySpace), typed("мир"), key(tea.KeyBackspace))
+	if e.Value() != "Привет ми" || e.Len() != 9 || e.Cursor() != 9 {
+		t.Fatalf("ввод: %q, длина %d, курсор %d", e.Value(), e.Len(), e.Cursor())
+	}
+
+	press(e, key(tea.KeyHome), key(tea.KeyRight), key(tea.KeyDelete), typed("Ё"))
+	if e.Value() != "ПЁивет ми" {
+		t.Errorf("вставка в середину: %q", e.Value())
+	}
+
+	press(e, key(tea.KeyEnd), key(tea.KeyCtrlW))
+	if e.Value() != "ПЁивет " {
+		t.Errorf("удаление слова: %q", e.Value())
+	}
+
+	press(e, alt(tea.KeyLeft), key(tea.KeyCtrlK))
+	if e.Value() != "" {
+		t.Errorf("удаление до конца строки: %q", e.Value())
+	}
+}
+
+func TestEditorPasteAndLimit(t *testing.T) {
+	e := New(10)
+	press(e, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("раз\r\nдва\tтри"), Paste: true})
+	if e.Value() != "раз\nдва тр" || e.Len() != e.Limit() {
+		t.Fatalf("вставка: %q", e.Value())
+	}
+
+	press(e, typed("и"))
+	if e.Value() != "раз\nдва тр" {
+		t.Errorf("ввод сверх лимита: %q", e.Value())
+	}
+}
+
+func TestEditorMultiline(t *testing.T) {
+	e := New(0)
+	press(e, typed("первая"), alt(tea.KeyEnter), typed("вт"), key(tea.KeyCtrlJ), typed("третья"))
+	if e.Value() != "первая\nвт\nтретья" {
+		t.Fatalf("многострочный ввод: %q", e.Value())
+	}
+
+	// курсор переходит по строкам, на короткой строке - в ее конец
+	press(e, key(tea.KeyUp))
+	if e.Cursor() != len([]rune("первая\nвт")) {
+		t.Errorf("курсор на второй строке: %d", e.Cursor())
+	}
+	press(e, key(tea.KeyUp), typed("!"))
+	if e.Value() != "пе!рвая\nвт\nтретья" {
+		t.Errorf("ввод на первой строке: %q", e.Value())
+	}
+
+	if handled := e.Update(key(tea.KeyEnter)); handled {
+		t.Error("Enter обработан буфером")
+	}
+}
+
+func TestEditorHistory(t *testing.T) {
+	e := New(0)
+	for _, s := range []string{"один", "два", "два", "  "} {
+		press(e, typed(s))
+		e.Submit()
+	}
+
+	press(e, typed("черновик"), key(tea.KeyUp))
+	if e.Value() != "два" {
+		t.Fatalf("последнее сообщение: %q", e.Value())
+	}
+	press(e, key(tea.KeyUp), key(tea.KeyUp))
+	if e.Value() != "один" {
+		t.Errorf("первое сообщение: %q", e.Value())
+	}
+	press(e, key(tea.KeyDown), key(tea.KeyDown))
+	if e.Value() != "черновик" {
+		t.Errorf("возврат к черновику: %q", e.Value())
+	}
+}
+
+func TestEditorReplaceWord(t *testing.T) {
+	e := New(0)
+	press(e, typed("привет @бо"))
+	if e.Word() != "@бо" {
+		t.Fatalf("слово перед курсором: %q", e.Word())
+	}
+	e.ReplaceWord("@боцман ")
+	if e.Value() != "привет @боцман " || e.Word() != "" {
+		t.Errorf("замена слова: %q", e.Value())
+	}
+}
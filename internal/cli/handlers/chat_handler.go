@@ -28,6 +28,7 @@ type ChatMessage struct {
 }
 
 type WsClient struct {
+	url       string
 	conn      *websocket.Conn
 	Incoming  chan ChatMessage
 	Outgoing  chan ChatMessage
@@ -37,8 +38,9 @@ type WsClient struct {
 	closeOnce sync.Once
 }
 
-func NewWsClient() *WsClient {
+func NewWsClient(url string) *WsClient {
 	return &WsClient{
+		url:       url,
 		Incoming:  make(chan ChatMessage, 100),
 		Outgoing:  make(chan ChatMessage, 10),
 		closeChan: make(chan struct{}),
@@ -58,7 +60,7 @@ func (c *WsClient) Connect(guildID int, username string) error {
 
 	c.closeChan = make(chan struct{})
 
-	conn, _, err := websocket.DefaultDialer.Dial("ws://localhost:8080/ws", nil)
+	conn, _, err := websocket.DefaultDialer.Dial(c.url, nil)
 	if err != nil {
 		return err
 	}
@@ -1,39 +1,322 @@
 package initCli
 
 import (
+	"context"
+	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	notifyPackets "lesta-start-battleship/cli/internal/api/websocket/packets/notify"
 	"lesta-start-battleship/cli/internal/cli/models"
+	"lesta-start-battleship/cli/internal/cli/ui"
 	"lesta-start-battleship/cli/internal/clientdeps"
+	"lesta-start-battleship/cli/internal/notify"
 	guildStorage "lesta-start-battleship/cli/storage/guild"
+	"log"
+	"strings"
+	"time"
 )
 
+const (
+	sessionCheckInterval = 30 * time.Second // период проверки срока действия access token
+	sessionRefreshWindow = 2 * time.Minute  // обновление токена заранее, до истечения
+
+	toastTTL   = 5 * time.Second // время показа всплывающего уведомления
+	toastLimit = 3               // сколько всплывающих уведомлений видно одновременно
+)
+
+// sessionTickMsg - плановая проверка срока действия токена.
+// generation отсекает проверки завершенных сессий.
+type sessionTickMsg struct {
+	generation int
+}
+
+// guildTickMsg - плановое обновление членства пользователя в гильдии
+type guildTickMsg struct {
+	generation int
+}
+
+// notifyConnectedMsg - подключен websocket уведомлений
+type notifyConnectedMsg struct {
+	generation int
+	listener   *notify.Listener
+}
+
+// notifyFallbackMsg - websocket уведомлений недоступен, переход на опрос
+type notifyFallbackMsg struct {
+	generation int
+	err        error
+}
+
+// notifyEventMsg - новые уведомления из websocket или опроса
+type notifyEventMsg struct {
+	generation int
+	items      []notify.Notification
+}
+
+// notifyTickMsg - плановый опрос сервиса гильдий
+type notifyTickMsg struct {
+	generation int
+}
+
+// profileLogoutMsg - сессия завершена перед переключением профиля
+type profileLogoutMsg struct {
+	generation int
+}
+
+// toastExpiredMsg - истекло время показа всплывающего уведомления
+type toastExpiredMsg struct {
+	id int
+}
+
+// toast - всплывающее уведомление
+type toast struct {
+	id   int
+	text string
+}
+
 type CLI struct {
 	currentScreen tea.Model
-	chatComponent *models.ChatComponent
+	chat          *models.ChatPanel       // чаты гильдии, текущей игры и лобби
+	direct        *models.DirectComponent // личные сообщения, соединение держится всю сессию
 	clients       *clientdeps.Client
 	gold          int
 	userID        int
 	username      string
+	sessionGen    int
+	customRoom    string // своя игра пользователя, в которую чат приглашает через /invite
+
+	guildChanges  <-chan guildStorage.Change // подписка на хранилище гильдий клиентов
+	unwatchGuilds func()
+
+	notifyListener *notify.Listener // websocket уведомлений, nil - опрос или нет сессии
+	notifyPoller   *notify.Poller
+	toasts         []toast
+	toastSeq       int
 }
 
 func NewCLI(clients *clientdeps.Client) *CLI {
 	return &CLI{
 		currentScreen: models.NewAuthModel(clients),
-		chatComponent: models.NewChatComponent("", 0),
+		chat:          models.NewChatPanel("", 0, 0, clients),
+		direct:        models.NewDirectComponent("", 0, clients),
 		clients:       clients,
 	}
 }
 
 func (a *CLI) Init() tea.Cmd {
-	return nil
+	return tea.Batch(a.restoreSession(), a.watchGuildStore())
+}
+
+// watchGuildStore - подписка на изменения хранилища гильдий активных клиентов
+func (a *CLI) watchGuildStore() tea.Cmd {
+	if a.unwatchGuilds != nil {
+		a.unwatchGuilds()
+	}
+	a.guildChanges, a.unwatchGuilds = a.clients.GuildStore.Subscribe()
+	return models.ListenGuildStore(a.guildChanges)
+}
+
+// restoreSession - восстановление сохраненной сессии активного профиля
+func (a *CLI) restoreSession() tea.Cmd {
+	if a.clients.Profiles == nil {
+		return nil
+	}
+	p, ok := a.clients.Profiles.Get(a.clients.Profile)
+	if !ok || p.Session == nil {
+		return nil
+	}
+	if p.Session.UserID == 0 && a.clients.AuthClient.UserID() == 0 {
+		return nil
+	}
+
+	authClient := a.clients.AuthClient
+	return func() tea.Msg {
+		profile, err := authClient.GetProfile(context.Background())
+		if err != nil {
+			return models.SessionRestoreFailedMsg{Err: err}
+		}
+		return models.AuthSuccessMsg{
+			ID:       profile.ID,
+			Username: profile.Username,
+			Gold:     profile.Currency.Gold,
+		}
+	}
+}
+
+// scheduleSessionCheck - планирование следующей проверки токена
+func (a *CLI) scheduleSessionCheck() tea.Cmd {
+	generation := a.sessionGen
+	return tea.Tick(sessionCheckInterval, func(time.Time) tea.Msg {
+		return sessionTickMsg{generation: generation}
+	})
+}
+
+// scheduleGuildRefresh - планирование обновления данных гильдии по истечении TTL
+func (a *CLI) scheduleGuildRefresh() tea.Cmd {
+	generation := a.sessionGen
+	return tea.Tick(a.clients.GuildStore.TTL(), func(time.Time) tea.Msg {
+		return guildTickMsg{generation: generation}
+	})
+}
+
+// startNotifications - подключение к websocket уведомлений, без адреса - сразу опрос
+func (a *CLI) startNotifications() tea.Cmd {
+	generation, userID, url := a.sessionGen, a.userID, a.clients.Endpoints.Notifications
+	if url == "" {
+		return a.startPolling()
+	}
+	header := a.clients.AuthClient.AuthHeader()
+	return func() tea.Msg {
+		listener, err := notify.Dial(url, userID, header)
+		if err != nil {
+			return notifyFallbackMsg{generation: generation, err: err}
+		}
+		return notifyConnectedMsg{generation: generation, listener: listener}
+	}
+}
+
+// listenNotifications - ожидание следующего события websocket
+func (a *CLI) listenNotifications() tea.Cmd {
+	generation, listener, center := a.sessionGen, a.notifyListener, a.clients.Notifications
+	return func() tea.Msg {
+		event, err := listener.Next()
+		if err != nil {
+			return notifyFallbackMsg{generation: generation, err: err}
+		}
+		return notifyEventMsg{generation: generation, items: center.Add(event)}
+	}
+}
+
+// startPolling - переход на опрос сервиса гильдий
+func (a *CLI) startPolling() tea.Cmd {
+	a.notifyPoller = notify.NewPoller(a.clients.GuildsClient, a.clients.GuildStore, a.clients.Notifications, a.userID)
+	a.clients.Notifications.SetChannel(notify.ChannelPolling)
+	return a.pollNotifications()
+}
+
+// pollNotifications - опрос сервиса гильдий
+func (a *CLI) pollNotifications() tea.Cmd {
+	generation, poller := a.sessionGen, a.notifyPoller
+	return func() tea.Msg {
+		items, err := poller.Poll(context.Background())
+		if err != nil {
+			log.Printf("Ошибка опроса уведомлений: %v", err)
+		}
+		return notifyEventMsg{generation: generation, items: items}
+	}
+}
+
+// stopNotifications - отключение источника уведомлений
+func (a *CLI) stopNotifications() {
+	if a.notifyListener != nil {
+		a.notifyListener.Close()
+		a.notifyListener = nil
+	}
+	a.notifyPoller = nil
+	a.toasts = nil
+	a.clients.Notifications.Clear()
+}
+
+// handleNotifications - всплывающие сообщения о новых уведомлениях и обновление экрана
+func (a *CLI) handleNotifications(items []notify.Notification) tea.Cmd {
+	if len(items) == 0 {
+		return nil
+	}
+
+	cmds := make([]tea.Cmd, 0, len(items)+2)
+	refresh := false
+	for _, n := range items {
+		cmds = append(cmds, a.addToast(models.NotificationText(n)))
+		refresh = refresh || n.Kind == notifyPackets.KindRoleChanged || n.Kind == notifyPackets.KindKicked
+	}
+	// роль или членство изменились на сервере - хранилище гильдий устарело
+	if refresh {
+		cmds = append(cmds, models.RefreshGuildSelf(a.clients, a.userID))
+	}
+
+	var cmd tea.Cmd
+	a.currentScreen, cmd = a.currentScreen.Update(models.NotificationsMsg{Items: items})
+	return tea.Batch(append(cmds, cmd)...)
+}
+
+// addToast - всплывающее сообщение на время toastTTL, старые сверх toastLimit скрываются
+func (a *CLI) addToast(text string) tea.Cmd {
+	a.toastSeq++
+	id := a.toastSeq
+	a.toasts = append(a.toasts, toast{id: id, text: text})
+	if len(a.toasts) > toastLimit {
+		a.toasts = a.toasts[len(a.toasts)-toastLimit:]
+	}
+	return tea.Tick(toastTTL, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+// refreshSession - обновление токена, если он скоро истекает
+func (a *CLI) refreshSession() tea.Cmd {
+	authClient := a.clients.AuthClient
+	return func() tea.Msg {
+		if _, err := authClient.RefreshIfExpiring(context.Background(), sessionRefreshWindow); err != nil {
+			log.Printf("Ошибка обновления сессии: %v", err)
+		}
+		return nil
+	}
+}
+
+// resetSession - завершение сессии в приложении без выхода на сервере
+func (a *CLI) resetSession() {
+	a.sessionGen++
+	a.userID = 0
+	a.gold = 0
+	a.username = ""
+	a.customRoom = ""
+	a.clients.GuildStore.Clear()
+	a.stopNotifications()
+	a.chat.Close()
+	a.chat = models.NewChatPanel("", 0, 0, a.clients)
+	a.direct.Close()
+	a.direct = models.NewDirectComponent("", 0, a.clients)
+}
+
+// logoutForSwitch - завершение сессии на сервере перед переключением профиля.
+// Сохраненные токены профиля удаляются и при ошибке выхода, чтобы профиль не остался с сессией.
+func (a *CLI) logoutForSwitch() tea.Cmd {
+	clients, generation := a.clients, a.sessionGen
+	return func() tea.Msg {
+		if err := clients.AuthClient.Logout(context.Background()); err != nil && clients.Profiles != nil {
+			clients.Profiles.ClearSession(clients.Profile)
+		}
+		return profileLogoutMsg{generation: generation}
+	}
+}
+
+// selfGuildID - гильдия текущего пользователя из хранилища, 0 - не состоит или еще не загружена
+func (a *CLI) selfGuildID() int {
+	if self, ok := a.clients.GuildStore.Self(); ok {
+		return self.GuildID
+	}
+	return 0
+}
+
+// syncChatRoom - переход чата в комнату новой гильдии после вступления, выхода или исключения.
+// Открытый чат переподключается сразу.
+func (a *CLI) syncChatRoom() tea.Cmd {
+	if a.userID == 0 {
+		return nil
+	}
+	return a.chat.SetGuild(a.selfGuildID())
 }
 
 func (a *CLI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
-		if keyMsg.Type == tea.KeyEsc && a.chatComponent.IsVisible() && a.chatComponent.Focused {
-			a.chatComponent.Close()
-			return a, nil
+		// Esc в активном чате не доходит до экрана: чат закрывает поиск или закрывается сам
+		if keyMsg.Type == tea.KeyEsc && a.chat.Focused() {
+			_, cmd := a.chat.Update(keyMsg)
+			return a, cmd
+		}
+		if keyMsg.Type == tea.KeyEsc && a.direct.IsVisible() && a.direct.Focused {
+			_, cmd := a.direct.Update(keyMsg)
+			return a, cmd
 		}
 	}
 
@@ -45,80 +328,242 @@ func (a *CLI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.userID = msg.ID
 		a.gold = msg.Gold
 		a.username = msg.Username
+		if a.clients.Profiles != nil {
+			if err := a.clients.Profiles.SaveSession(a.clients.Profile, msg.ID, msg.Username); err != nil {
+				log.Printf("Ошибка сохранения сессии профиля %s: %v", a.clients.Profile, err)
+			}
+		}
 		a.currentScreen = models.NewMainMenuModel(a.userID, a.username, a.gold, a.clients)
-		a.chatComponent = models.NewChatComponent(a.username, 1)
+		a.chat.Close()
+		a.chat = models.NewChatPanel(a.username, a.userID, a.selfGuildID(), a.clients)
+		a.chat.SetRoom(a.customRoom)
+		a.direct.Close()
+		a.direct = models.NewDirectComponent(a.username, a.userID, a.clients)
+		a.sessionGen++
+		a.stopNotifications()
+		return a, tea.Batch(a.refreshSession(), a.scheduleSessionCheck(), a.scheduleGuildRefresh(), a.startNotifications(),
+			a.direct.Init())
+
+	case sessionTickMsg:
+		if msg.generation != a.sessionGen || a.userID == 0 {
+			return a, nil
+		}
+		return a, tea.Batch(a.refreshSession(), a.scheduleSessionCheck())
+
+	case guildTickMsg:
+		if msg.generation != a.sessionGen || a.userID == 0 {
+			return a, nil
+		}
+		return a, tea.Batch(models.RefreshGuildSelf(a.clients, a.userID), a.scheduleGuildRefresh())
+
+	case notifyConnectedMsg:
+		if msg.generation != a.sessionGen || a.userID == 0 {
+			msg.listener.Close()
+			return a, nil
+		}
+		a.notifyListener = msg.listener
+		a.clients.Notifications.SetChannel(notify.ChannelWebsocket)
+		return a, a.listenNotifications()
+
+	case notifyFallbackMsg:
+		if msg.generation != a.sessionGen || a.userID == 0 {
+			return a, nil
+		}
+		log.Printf("Уведомления по websocket недоступны, переход на опрос: %v", msg.err)
+		if a.notifyListener != nil {
+			a.notifyListener.Close()
+			a.notifyListener = nil
+		}
+		return a, a.startPolling()
+
+	case notifyEventMsg:
+		if msg.generation != a.sessionGen || a.userID == 0 {
+			return a, nil
+		}
+		next := a.listenNotifications()
+		if a.notifyListener == nil {
+			next = tea.Tick(notify.PollInterval, func(time.Time) tea.Msg {
+				return notifyTickMsg{generation: msg.generation}
+			})
+		}
+		return a, tea.Batch(a.handleNotifications(msg.items), next)
+
+	case notifyTickMsg:
+		if msg.generation != a.sessionGen || a.notifyPoller == nil {
+			return a, nil
+		}
+		return a, a.pollNotifications()
+
+	case toastExpiredMsg:
+		for i, t := range a.toasts {
+			if t.id == msg.id {
+				a.toasts = append(a.toasts[:i], a.toasts[i+1:]...)
+				break
+			}
+		}
 		return a, nil
 
+	case models.GuildStoreChangedMsg:
+		var cmd, chatCmd tea.Cmd
+		a.currentScreen, cmd = a.currentScreen.Update(msg)
+		if msg.Change.Kind == guildStorage.SelfChanged {
+			chatCmd = a.syncChatRoom()
+		}
+		return a, tea.Batch(cmd, chatCmd, models.ListenGuildStore(a.guildChanges))
+
 	case models.LogoutMsg:
-		a.userID = 0
-		a.gold = 0
-		a.username = ""
-		guildStorage.CleanStorage()
+		a.resetSession()
 		a.currentScreen = models.NewAuthModel(a.clients)
-		a.chatComponent.Close()
-		a.chatComponent = models.NewChatComponent("", 0)
 		return a, nil
 
+	case profileLogoutMsg:
+		if msg.generation != a.sessionGen {
+			return a, nil
+		}
+		a.resetSession()
+		authModel := models.NewAuthModel(a.clients)
+		authModel.OpenProfilePicker()
+		a.currentScreen = authModel
+		return a, nil
+
+	case models.ProfileSelectedMsg:
+		clients, err := a.clients.OpenProfile(msg.Name)
+		if err != nil {
+			a.currentScreen, _ = a.currentScreen.Update(models.ProfileErrorMsg{Err: err})
+			return a, nil
+		}
+		a.resetSession()
+		a.clients = clients
+		a.currentScreen = models.NewAuthModel(clients)
+		return a, tea.Batch(a.restoreSession(), a.watchGuildStore())
+
 	case models.UsernameChangeMsg:
 		a.username = msg.NewUsername
 		a.gold = msg.Gold
-		a.chatComponent.Username = msg.NewUsername
+		a.chat.SetUsername(msg.NewUsername)
+		a.direct.Username = msg.NewUsername
 		return a, nil
 
-	case models.OpenChatMsg:
-		// Если GuildID передан, используем его для инициализации чата гильдии
-		guildID := 0
-		if msg.GuildID != 0 {
-			guildID = msg.GuildID
-		}
-		a.chatComponent = models.NewChatComponent(a.username, guildID)
-		a.chatComponent.Toggle()
-		if a.chatComponent.IsVisible() {
-			return a, a.chatComponent.Init()
+	case models.ChatNotifyMsg:
+		return a, a.handleNotifications(msg.Items)
+
+	case models.CustomRoomMsg:
+		a.customRoom = msg.RoomID
+		a.chat.SetRoom(msg.RoomID)
+		return a, nil
+
+	case models.MatchRoomMsg:
+		a.chat.SetMatch(msg)
+		return a, nil
+
+	case models.RoomChatMsg:
+		_, cmd := a.chat.Update(msg)
+		if a.chat.IsVisible() {
+			return a, cmd
 		}
+		return a, tea.Batch(cmd, a.addToast(fmt.Sprintf("💬 %s: %s (Ctrl+G - чат игры)", msg.Message.From, msg.Message.Text)))
+
+	case models.OpenDirectMsg:
+		// панель справа одна: личные сообщения сменяют чат гильдии
+		a.chat.Close()
+		a.direct.Open(msg.UserID, msg.Username)
 		return a, nil
 
+	case models.DirectReceivedMsg:
+		return a, a.addToast(fmt.Sprintf("✉ Личное сообщение от %s", msg.Message.FromName))
+
+	case models.OpenChatMsg:
+		a.direct.Hide()
+		return a, tea.Batch(a.chat.SetGuild(msg.GuildID), a.chat.Open(models.ChatTabGuild))
+
 	case tea.KeyMsg:
 		if msg.Type == tea.KeyCtrlC {
 			return a, tea.Quit
 		}
 
-		if msg.Type == tea.KeyCtrlG && a.chatComponent.IsVisible() {
-			a.chatComponent.Focused = !a.chatComponent.Focused
-			return a, nil
+		// быстрое переключение профиля: выход из текущей сессии, затем выбор профиля
+		if msg.Type == tea.KeyCtrlP && a.userID != 0 && a.clients.OpenProfile != nil {
+			return a, a.logoutForSwitch()
 		}
-	}
 
-	var chatCmd tea.Cmd
-	if a.chatComponent.IsVisible() {
-		var updatedChat tea.Model
-		updatedChat, chatCmd = a.chatComponent.Update(msg)
-		if updatedChat != nil {
-			a.chatComponent = updatedChat.(*models.ChatComponent)
+		if msg.Type == tea.KeyCtrlG && a.chat.IsVisible() {
+			a.chat.ToggleFocus()
+			return a, nil
+		}
+		if msg.Type == tea.KeyCtrlG && a.direct.IsVisible() {
+			a.direct.Focused = !a.direct.Focused
+			return a, nil
+		}
+		// с любого экрана панель чата открывается на вкладке игры, вне игры - на чате гильдии
+		if msg.Type == tea.KeyCtrlG && a.userID != 0 {
+			tab := models.ChatTabGuild
+			if a.chat.InMatch() {
+				tab = models.ChatTabMatch
+			}
+			// членство еще не загружено: чат гильдии переключится по изменению хранилища
+			if _, ok := a.clients.GuildStore.Self(); !ok {
+				return a, tea.Batch(a.chat.Open(tab), models.RefreshGuildSelf(a.clients, a.userID))
+			}
+			return a, a.chat.Open(tab)
 		}
 
-		if a.chatComponent.Focused {
-			return a, chatCmd
+		if (msg.Type == tea.KeyCtrlLeft || msg.Type == tea.KeyCtrlRight) && a.chat.IsVisible() {
+			delta := 1
+			if msg.Type == tea.KeyCtrlLeft {
+				delta = -1
+			}
+			a.chat.Switch(delta)
+			return a, nil
 		}
 	}
 
+	// личные сообщения получают пакеты и при скрытой панели
+	_, directCmd := a.direct.Update(msg)
+	if _, ok := msg.(tea.KeyMsg); ok && a.direct.IsVisible() && a.direct.Focused {
+		return a, directCmd
+	}
+
+	focused := a.chat.Focused()
+	_, chatCmd := a.chat.Update(msg)
+	if _, ok := msg.(tea.KeyMsg); ok && focused {
+		return a, chatCmd
+	}
+
 	var mainCmd tea.Cmd
 	a.currentScreen, mainCmd = a.currentScreen.Update(msg)
 
-	return a, tea.Batch(mainCmd, chatCmd)
+	return a, tea.Batch(mainCmd, chatCmd, directCmd)
 }
 
 func (a *CLI) View() string {
-	mainView := a.currentScreen.View()
+	mainView := a.currentScreen.View() + a.toastsView()
 
-	if a.chatComponent.IsVisible() {
-		chatView := a.chatComponent.View()
+	var panel string
+	switch {
+	case a.chat.IsVisible():
+		panel = a.chat.View()
+	case a.direct.IsVisible():
+		panel = a.direct.View()
+	}
+	if panel != "" {
 		return lipgloss.JoinHorizontal(
 			lipgloss.Top,
 			lipgloss.NewStyle().Width(100).Render(mainView),
-			chatView,
+			panel,
 		)
 	}
 
 	return mainView
 }
+
+// toastsView - всплывающие уведомления под экраном
+func (a *CLI) toastsView() string {
+	if len(a.toasts) == 0 {
+		return ""
+	}
+	lines := make([]string, len(a.toasts))
+	for i, t := range a.toasts {
+		lines[i] = "🔔 " + t.text
+	}
+	return "\n\n" + ui.ToastStyle.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,750 @@
+package initCli_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gorilla/websocket"
+
+	"lesta-start-battleship/cli/internal/api/auth"
+	guildPackets "lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/room"
+	"lesta-start-battleship/cli/internal/cli/clitest"
+	"lesta-start-battleship/cli/internal/cli/initCli"
+	"lesta-start-battleship/cli/internal/cli/models"
+	"lesta-start-battleship/cli/internal/clientdeps"
+	"lesta-start-battleship/cli/internal/mockserver"
+	"lesta-start-battleship/cli/storage/profile"
+)
+
+// start - запуск приложения против mock-сервера
+func start(t *testing.T) (*clitest.Harness, *clitest.Backend, *clientdeps.Client) {
+	t.Helper()
+
+	backend := clitest.NewBackend(t)
+	clients := backend.Clients(t)
+	return clitest.New(t, initCli.NewCLI(clients)), backend, clients
+}
+
+// login - вход через форму логина и пароля
+func login(h *clitest.Harness, username, password string) {
+	h.Type(username)
+	h.Press(tea.KeyEnter)
+	h.Type(password)
+	h.Press(tea.KeyEnter)
+}
+
+func TestLogin(t *testing.T) {
+	h, _, _ := start(t)
+	h.Golden("auth")
+
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+	h.Golden("main_menu")
+}
+
+func TestLoginWrongPassword(t *testing.T) {
+	h, _, _ := start(t)
+
+	login(h, "admiral", "wrong")
+	h.WaitFor("Ошибка авторизации")
+	h.Golden("auth_wrong_password")
+}
+
+func TestJoinGuild(t *testing.T) {
+	h, backend, _ := start(t)
+	login(h, "newbie", "newbie")
+	h.WaitFor("Пользователь: newbie")
+
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("Вы не состоите в гильдии")
+
+	h.Press(tea.KeyEnter)
+	h.Type("KRAK")
+	h.Press(tea.KeyEnter)
+	h.WaitFor("Запрос в гильдию [KRAK] отправлен")
+	h.Golden("guild_join_sent")
+
+	// заявку видит владелец гильдии KRAK
+	ctx := context.Background()
+	owner := backend.Clients(t)
+	if _, _, err := owner.AuthClient.Login(ctx, auth.LoginRequest{Username: "corsair", Password: "corsair"}); err != nil {
+		t.Fatalf("ошибка входа владельца гильдии: %v", err)
+	}
+	requests, err := owner.GuildsClient.GetJoinRequests(ctx, "KRAK", 4)
+	if err != nil {
+		t.Fatalf("ошибка получения заявок: %v", err)
+	}
+	if len(requests.Items) != 1 || requests.Items[0].UserName != "newbie" {
+		t.Fatalf("заявки гильдии KRAK: %+v, ожидалась заявка newbie", requests.Items)
+	}
+}
+
+func TestBuyItem(t *testing.T) {
+	h, _, clients := start(t)
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("Радар")
+	h.Press(tea.KeyDown, tea.KeyEnter)
+	h.Golden("shop_after_buy")
+
+	ctx := context.Background()
+	profile, err := clients.AuthClient.GetProfile(ctx)
+	if err != nil {
+		t.Fatalf("ошибка получения профиля: %v", err)
+	}
+	if profile.Currency.Gold != 4700 {
+		t.Errorf("золото после покупки: %d, ожидалось 4700", profile.Currency.Gold)
+	}
+
+	inventory, err := clients.InventoryClient.GetUserInventory(ctx)
+	if err != nil {
+		t.Fatalf("ошибка получения инвентаря: %v", err)
+	}
+	bought := false
+	for _, item := range inventory.Items {
+		if item.Name == "Радар" && item.Amount == 1 {
+			bought = true
+		}
+	}
+	if !bought {
+		t.Errorf("в инвентаре нет купленного радара: %+v", inventory.Items)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	h, _, _ := start(t)
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+
+	h.Press(tea.KeyEsc)
+	h.WaitFor("Авторизация")
+
+	h.Press(tea.KeyCtrlC)
+	h.WaitUntil(func(string) bool { return h.Quit() }, "выход из приложения")
+}
+
+func TestProfileSwitchLogsOut(t *testing.T) {
+	backend := clitest.NewBackend(t)
+	clients := backend.Clients(t)
+	profiles, err := profile.Open(filepath.Join(t.TempDir(), "profiles.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clients.Profile, clients.Profiles = profile.DefaultName, profiles
+	clients.OpenProfile = func(string) (*clientdeps.Client, error) { return backend.Clients(t), nil }
+	h := clitest.New(t, initCli.NewCLI(clients))
+
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+	h.Press(tea.KeyCtrlP)
+	h.WaitFor("Выбор профиля")
+
+	// сессия завершена до выбора профиля
+	if header := clients.AuthClient.AuthHeader().Get("Authorization"); header != "" {
+		t.Errorf("токен сессии после переключения профиля: %q", header)
+	}
+}
+
+// openMembers - переход к списку участников гильдии из главного меню
+func openMembers(h *clitest.Harness, guildMenuPos int) {
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("Ваша роль")
+	for range guildMenuPos {
+		h.Press(tea.KeyDown)
+	}
+	h.Press(tea.KeyEnter)
+	h.WaitFor("Участники гильдии")
+}
+
+func TestPromoteMember(t *testing.T) {
+	h, _, clients := start(t)
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+	openMembers(h, 3)
+	h.WaitFor("cabin [Юнга]")
+
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("Назначить роль: Офицер")
+	h.Golden("member_actions")
+
+	h.Press(tea.KeyEnter)
+	h.WaitFor("Изменить роль cabin: Юнга -> Офицер?")
+	h.Press(tea.KeyEnter)
+	h.WaitFor("cabin [Офицер]")
+
+	members, err := clients.GuildsClient.GetGuildMembers(context.Background(), "WOLF", 0, 10)
+	if err != nil {
+		t.Fatalf("ошибка получения участников: %v", err)
+	}
+	for _, member := range members.Items {
+		if member.UserName == "cabin" && member.Role.Title != "officer" {
+			t.Errorf("роль cabin на сервере: %s, ожидалась officer", member.Role.Title)
+		}
+	}
+}
+
+func TestManageMemberForbidden(t *testing.T) {
+	h, _, _ := start(t)
+	login(h, "bosun", "bosun")
+	h.WaitFor("Пользователь: bosun")
+	openMembers(h, 1)
+	h.WaitFor("admiral")
+
+	h.Press(tea.KeyEnter)
+	h.WaitFor("Роль Офицер не позволяет управлять участником admiral с ролью Владелец")
+	h.Golden("member_forbidden")
+}
+
+func TestGuildSearchAndJoin(t *testing.T) {
+	h, _, _ := start(t)
+	login(h, "newbie", "newbie")
+	h.WaitFor("Пользователь: newbie")
+
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("Вы не состоите в гильдии")
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("[WOLF]")
+
+	h.Type("крак")
+	h.WaitUntil(func(view string) bool { return !strings.Contains(view, "[WOLF]") }, "поиск по названию")
+	h.Golden("guild_search")
+
+	h.Press(tea.KeyEnter)
+	h.WaitFor("Владелец: corsair")
+	h.Press(tea.KeyEnter)
+	h.WaitFor("Запрос в гильдию [KRAK] отправлен")
+	h.Golden("guild_detail_join")
+}
+
+func TestGuildWars(t *testing.T) {
+	h, _, _ := start(t)
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("Ваша роль")
+	h.Press(tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("[KRAK] Кракены → [WOLF] Морские волки")
+
+	h.Press(tea.KeyTab, tea.KeyTab)
+	h.WaitFor("[WOLF] Морские волки → [KRAK] Кракены  5:3")
+	h.Golden("war_hub_finished")
+
+	h.Press(tea.KeyEnter)
+	h.WaitFor("Счет: 5:3 победа")
+	h.WaitFor("[KRAK] Кракены (1): corsair")
+
+	h.Press(tea.KeyEsc, tea.KeyShiftTab, tea.KeyShiftTab)
+	h.WaitFor("[KRAK] Кракены → [WOLF] Морские волки")
+	h.Press(tea.KeyEnter)
+	h.WaitFor("Срок подтверждения")
+	h.WaitFor("осталось 23:")
+	h.Press(tea.KeyEnter)
+	h.WaitFor("Война с [KRAK] Кракены подтверждена")
+	h.WaitFor("Войн с этим статусом нет")
+}
+
+func TestDeclareWar(t *testing.T) {
+	h, _, _ := start(t)
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("Ваша роль")
+	h.Press(tea.KeyEnter)
+	h.WaitFor("[KRAK] Кракены")
+
+	h.Type("крак")
+	h.Press(tea.KeyEnter)
+	h.WaitFor("Объявить войну гильдии [KRAK] Кракены?")
+	h.Golden("war_target_confirm")
+
+	h.Press(tea.KeyEnter)
+	h.WaitFor("Гильдии [KRAK] объявлена война")
+}
+
+func TestNotifications(t *testing.T) {
+	h, backend, _ := start(t)
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+
+	h.Press(tea.KeyUp, tea.KeyUp, tea.KeyEnter)
+	h.WaitFor("Получение: websocket")
+	h.Press(tea.KeyEsc)
+	h.WaitFor("Пользователь: admiral")
+
+	ctx := context.Background()
+	newbie := backend.Clients(t)
+	if _, _, err := newbie.AuthClient.Login(ctx, auth.LoginRequest{Username: "newbie", Password: "newbie"}); err != nil {
+		t.Fatalf("ошибка входа newbie: %v", err)
+	}
+	if err := newbie.GuildsClient.SendJoinRequest(ctx, "WOLF", 6); err != nil {
+		t.Fatalf("ошибка отправки заявки: %v", err)
+	}
+
+	h.WaitFor("newbie подал заявку в гильдию [WOLF]")
+	h.WaitFor("Уведомления (1)")
+
+	h.Press(tea.KeyEnter)
+	h.WaitFor("• ")
+	h.Press(tea.KeyEsc)
+	h.WaitUntil(func(view string) bool { return !strings.Contains(view, "Уведомления (1)") }, "уведомления прочитаны")
+}
+
+func TestGuildAuditLog(t *testing.T) {
+	h, _, _ := start(t)
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("Ваша роль")
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("corsair [KRAK] объявил войну гильдии [WOLF]")
+	h.WaitFor("admiral назначил bosun роль Офицер")
+	h.Golden("audit_log")
+
+	h.Press(tea.KeyTab)
+	h.Type("cabin")
+	h.WaitFor("Записи 1-1 из 1")
+	h.WaitFor("bosun принял заявку cabin")
+
+	// экспорт пишет файл в текущий каталог
+	t.Chdir(t.TempDir())
+	h.Press(tea.KeyCtrlE)
+	h.WaitFor("Журнал сохранен: audit_WOLF_")
+
+	files, err := filepath.Glob("audit_WOLF_*.csv")
+	if err != nil || len(files) != 1 {
+		t.Fatalf("файлы экспорта: %v, %v", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("ошибка чтения экспорта: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || lines[0] != "id,created_at,action,actor_id,actor_name,target_id,target_name,details" ||
+		!strings.Contains(lines[1], "join_accepted,2,bosun,3,cabin") {
+		t.Errorf("экспорт журнала:\n%s", data)
+	}
+}
+
+func TestGuildChat(t *testing.T) {
+	h, _, _ := start(t)
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("Ваша роль")
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+
+	// история комнаты гильдии WOLF приходит первым пакетом
+	h.WaitFor("bosun: Принято, капитан")
+
+	// удаление и курсор работают с символами, а не байтами
+	h.Type("Поднять якоряя")
+	h.Press(tea.KeyBackspace)
+	h.WaitFor("> Поднять якоря_")
+	h.WaitFor("13/500")
+	h.Press(tea.KeyEnter)
+	h.WaitFor("admiral: Поднять якоря")
+	h.WaitFor("> _")
+
+	// отправленное сообщение возвращается по ↑
+	h.Press(tea.KeyUp)
+	h.WaitFor("> Поднять якоря_")
+}
+
+func TestGuildChatFollowsMembership(t *testing.T) {
+	h, backend, _ := start(t)
+	login(h, "cabin", "cabin")
+	h.WaitFor("Пользователь: cabin")
+
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("Ваша роль")
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("bosun: Принято, капитан")
+
+	ctx := context.Background()
+	admiral := backend.Clients(t)
+	if _, _, err := admiral.AuthClient.Login(ctx, auth.LoginRequest{Username: "admiral", Password: "admiral"}); err != nil {
+		t.Fatalf("ошибка входа admiral: %v", err)
+	}
+	if err := admiral.GuildsClient.DeleteMember(ctx, "WOLF", 1, 3); err != nil {
+		t.Fatalf("ошибка исключения cabin: %v", err)
+	}
+
+	// после исключения чат покидает комнату гильдии
+	h.WaitFor("Вы исключены из гильдии [WOLF]")
+	h.WaitFor("вы не состоите в гильдии")
+	h.WaitUntil(func(view string) bool { return !strings.Contains(view, "Принято, капитан") }, "история чата WOLF скрыта")
+}
+
+// TestRefreshGuildSelfAfterLeaving - выход из гильдии в другом клиенте: на запрос членства сервер отвечает 404
+func TestRefreshGuildSelfAfterLeaving(t *testing.T) {
+	backend := clitest.NewBackend(t)
+	clients, other := backend.Clients(t), backend.Clients(t)
+	ctx := context.Background()
+	for _, c := range []*clientdeps.Client{clients, other} {
+		if _, _, err := c.AuthClient.Login(ctx, auth.LoginRequest{Username: "cabin", Password: "cabin"}); err != nil {
+			t.Fatalf("ошибка входа cabin: %v", err)
+		}
+	}
+
+	models.RefreshGuildSelf(clients, 3)()
+	if self, ok := clients.GuildStore.Self(); !ok || self.GuildTag != "WOLF" {
+		t.Fatalf("ожидалось членство в WOLF, получено %+v, %v", self, ok)
+	}
+
+	if err := other.GuildsClient.ExitGuild(ctx, "WOLF"); err != nil {
+		t.Fatalf("ошибка выхода из гильдии: %v", err)
+	}
+	models.RefreshGuildSelf(clients, 3)()
+	if self, ok := clients.GuildStore.Self(); ok {
+		t.Fatalf("членство не очищено после выхода: %+v", self)
+	}
+}
+
+func TestGuildChatHistory(t *testing.T) {
+	seed, err := mockserver.DefaultSeed()
+	if err != nil {
+		t.Fatalf("ошибка загрузки данных mock-сервера: %v", err)
+	}
+	seed.Chat = nil
+	for i := 1; i <= 70; i++ {
+		seed.Chat = append(seed.Chat, guildPackets.ChatHistoryMessage{
+			Id:        fmt.Sprintf("%024x", i),
+			GuildId:   1,
+			UserId:    2,
+			Username:  "bosun",
+			Content:   fmt.Sprintf("сообщение %d", i),
+			Timestamp: time.Date(2025, 6, 2, 10, i, 0, 0, time.UTC).Format(time.RFC3339),
+		})
+	}
+	backend := clitest.NewBackendWithSeed(t, seed)
+	clients := backend.Clients(t)
+	h := clitest.New(t, initCli.NewCLI(clients))
+
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("Ваша роль")
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("bosun: сообщение 70")
+
+	// сервер присылает 50 последних сообщений, остальные подгружаются прокруткой выше первого
+	pageUp := func(n int) {
+		for range n {
+			h.Press(tea.KeyPgUp)
+		}
+	}
+	pageUp(41)
+	h.WaitFor("bosun: сообщение 20")
+	pageUp(20)
+	h.WaitFor("Начало переписки")
+	if got := len(clients.Chat.Latest(1, 100)); got != 70 {
+		t.Errorf("сохранено сообщений: %d, ожидалось 70", got)
+	}
+
+	h.Type("/search сообщение 7")
+	h.Press(tea.KeyEnter)
+	h.WaitFor("Поиск «сообщение 7»: найдено 2")
+	h.Press(tea.KeyEsc)
+	h.WaitUntil(func(view string) bool { return !strings.Contains(view, "Поиск «") }, "поиск закрыт")
+	h.WaitFor("Чат гильдии (активен)")
+}
+
+func TestGuildChatCommands(t *testing.T) {
+	backend := clitest.NewBackend(t)
+	admiral, cabinClients := backend.Clients(t), backend.Clients(t)
+	h := clitest.New(t, initCli.NewCLI(admiral))
+	cabin := clitest.New(t, initCli.NewCLI(cabinClients))
+
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("Ваша роль")
+	h.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	h.WaitFor("bosun: Принято, капитан")
+
+	login(cabin, "cabin", "cabin")
+	cabin.WaitFor("Пользователь: cabin")
+	cabin.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	cabin.WaitFor("Ваша роль")
+	cabin.Press(tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	cabin.WaitFor("bosun: Принято, капитан")
+	// участники для автодополнения загружаются в хранилище без сообщения модели
+	for deadline := time.Now().Add(cabin.Timeout); len(cabinClients.GuildStore.Members("WOLF")) < 3; cabin.Drain(20 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("участники WOLF не загружены")
+		}
+	}
+
+	// упоминание дополняется по Tab и поднимает уведомление у адресата
+	cabin.Type("капитан @adm")
+	cabin.Press(tea.KeyTab)
+	cabin.WaitFor("> капитан @admiral_")
+	cabin.Press(tea.KeyEnter)
+	h.WaitFor("cabin упомянул вас в чате гильдии")
+	h.WaitFor("cabin: капитан @admiral")
+
+	h.Type("/w cabin Сбор у пирса")
+	h.Press(tea.KeyEnter)
+	h.WaitFor("→ cabin: Сбор у пирса")
+	cabin.WaitFor("← admiral: Сбор у пирса")
+
+	h.Type("/me поднимает флаг")
+	h.Press(tea.KeyEnter)
+	cabin.WaitFor("* admiral поднимает флаг")
+
+	h.Type("/who")
+	h.Press(tea.KeyEnter)
+	h.WaitFor("В чате (2): admiral, cabin")
+
+	// приглашать можно только в свою созданную игру
+	h.Type("/invite cabin")
+	h.Press(tea.KeyEnter)
+	h.WaitFor("сначала создайте свою игру")
+
+	h.Type("/clear")
+	h.Press(tea.KeyEnter)
+	h.WaitUntil(func(view string) bool { return !strings.Contains(view, "Принято, капитан") }, "окно чата очищено")
+}
+
+func TestDirectMessages(t *testing.T) {
+	backend := clitest.NewBackend(t)
+	h := clitest.New(t, initCli.NewCLI(backend.Clients(t)))
+	bosun := clitest.New(t, initCli.NewCLI(backend.Clients(t)))
+
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+	login(bosun, "bosun", "bosun")
+	bosun.WaitFor("Пользователь: bosun")
+
+	// непрочитанное сообщение из начальных данных видно в меню и списке переписок
+	bosun.WaitFor("✉️  Личные сообщения (1)")
+	bosun.Press(tea.KeyUp, tea.KeyEnter)
+	bosun.WaitFor("corsair (1) - Переходи к нам в KRAK")
+	bosun.Press(tea.KeyEnter)
+	bosun.WaitFor("Личные: corsair")
+	bosun.WaitFor("corsair: Переходи к нам в KRAK")
+	bosun.Press(tea.KeyEsc)
+	bosun.WaitFor("corsair - Переходи к нам в KRAK")
+	bosun.Press(tea.KeyEsc)
+	bosun.WaitFor("✉️  Личные сообщения\n")
+
+	// «написать» из списка участников гильдии
+	bosun.Press(tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	bosun.WaitFor("Ваша роль")
+	bosun.Press(tea.KeyDown, tea.KeyEnter)
+	bosun.WaitFor("> admiral")
+	bosun.Press(tea.KeyCtrlN)
+	bosun.WaitFor("Личные: admiral")
+	bosun.Type("Капитан, на связи")
+	bosun.Press(tea.KeyEnter)
+	bosun.WaitFor("bosun: Капитан, на связи")
+
+	h.WaitFor("✉ Личное сообщение от bosun")
+	h.WaitFor("✉️  Личные сообщения (1)")
+	h.Press(tea.KeyUp, tea.KeyEnter)
+	h.WaitFor("bosun (1) - Капитан, на связи")
+	h.Press(tea.KeyEnter)
+	h.WaitFor("bosun: Капитан, на связи")
+	h.Type("Принято")
+	h.Press(tea.KeyEnter)
+	bosun.WaitFor("admiral: Принято")
+	h.WaitFor("✉️  Личные сообщения\n")
+
+	// «написать» из рейтинга игроков
+	h.Press(tea.KeyEsc, tea.KeyEsc, tea.KeyUp, tea.KeyUp, tea.KeyEnter)
+	h.WaitFor("Моя статистика")
+	h.Press(tea.KeyRight)
+	h.WaitFor("> admiral")
+	h.Press(tea.KeyDown)
+	h.WaitFor("> corsair")
+	h.Press(tea.KeyCtrlN)
+	h.WaitFor("Личные: corsair")
+}
+
+func TestChatPanelTabs(t *testing.T) {
+	backend := clitest.NewBackend(t)
+	h := clitest.New(t, initCli.NewCLI(backend.Clients(t)))
+	cabin := clitest.New(t, initCli.NewCLI(backend.Clients(t)))
+
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+	h.Press(tea.KeyCtrlG)
+	h.WaitFor("bosun: Принято, капитан")
+
+	login(cabin, "cabin", "cabin")
+	cabin.WaitFor("Пользователь: cabin")
+	cabin.Press(tea.KeyCtrlG, tea.KeyCtrlRight, tea.KeyCtrlRight)
+	cabin.WaitFor("Лобби (активен)")
+	// оба чата лобби подключены: иначе сообщение придет admiral в истории, а не как новое
+	for deadline := time.Now().Add(cabin.Timeout); !strings.Contains(cabin.View(), "В чате (2): admiral, cabin"); {
+		if time.Now().After(deadline) {
+			t.Fatal("admiral не подключился к лобби")
+		}
+		cabin.Type("/who")
+		cabin.Press(tea.KeyEnter)
+		cabin.Drain(50 * time.Millisecond)
+	}
+	cabin.Type("Всем привет из лобби")
+	cabin.Press(tea.KeyEnter)
+
+	// непрочитанные видны на неактивной вкладке до ее открытия
+	h.WaitFor("Лобби (1)")
+	h.Press(tea.KeyCtrlRight)
+	h.WaitFor("вы не в игре")
+	h.Press(tea.KeyCtrlRight)
+	h.WaitFor("cabin: Всем привет из лобби")
+	if strings.Contains(h.View(), "Лобби (1)") {
+		t.Error("открытая вкладка лобби осталась непрочитанной")
+	}
+
+	// сообщения соперника приходят через матчмейкинг в комнату текущей игры
+	h.Send(models.MatchRoomMsg{RoomID: "R2D2"})
+	h.Send(models.RoomChatMsg{Message: room.Message{From: "corsair", Text: "Готов к бою?"}})
+	h.WaitFor("Игра (1)")
+	h.Press(tea.KeyCtrlLeft)
+	h.WaitFor("Чат игры R2D2 (активен)")
+	h.WaitFor("corsair: Готов к бою?")
+
+	// при закрытой панели сообщение соперника всплывает, Ctrl+G открывает вкладку игры
+	h.Press(tea.KeyEsc)
+	h.WaitUntil(func(view string) bool { return !strings.Contains(view, "Чат игры") }, "панель чата закрыта")
+	h.Send(models.RoomChatMsg{Message: room.Message{From: "corsair", Text: "Ещё тут?"}})
+	h.WaitFor("💬 corsair: Ещё тут?")
+	h.Press(tea.KeyCtrlG)
+	h.WaitFor("Чат игры R2D2 (активен)")
+}
+
+// presence - пакет набора или прочтения, который участник комнаты получает от сервера
+type presence struct {
+	Type     string `json:"type"`
+	Id       string `json:"_id"`
+	Username string `json:"username"`
+}
+
+// observeChat - участник комнаты гильдии без интерфейса: пакеты набора и прочтения других участников
+func observeChat(t *testing.T, backend *clitest.Backend, username string, guildID int) (*websocket.Conn, <-chan presence) {
+	t.Helper()
+
+	clients := backend.Clients(t)
+	_, user, err := clients.AuthClient.Login(context.Background(), auth.LoginRequest{Username: username, Password: username})
+	if err != nil {
+		t.Fatalf("ошибка входа %s: %v", username, err)
+	}
+	url := clients.Endpoints.GuildChat + fmt.Sprintf("ws/guild/%d/%d", guildID, user.ID)
+	conn, _, err := websocket.DefaultDialer.Dial(url, clients.AuthClient.AuthHeader())
+	if err != nil {
+		t.Fatalf("ошибка подключения к чату: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	// первым пакетом приходит история: после него участник в комнате
+	if _, _, err := conn.ReadMessage(); err != nil {
+		t.Fatalf("ошибка чтения истории: %v", err)
+	}
+
+	packets := make(chan presence, 64)
+	go func() {
+		for {
+			var p presence
+			if err := conn.ReadJSON(&p); err != nil {
+				return
+			}
+			if p.Type == guildPackets.TypeTyping || p.Type == guildPackets.TypeRead {
+				packets <- p
+			}
+		}
+	}()
+	return conn, packets
+}
+
+// collect - пакеты, пришедшие за время d
+func collect(packets <-chan presence, d time.Duration) []presence {
+	var got []presence
+	timeout := time.After(d)
+	for {
+		select {
+		case p := <-packets:
+			got = append(got, p)
+		case <-timeout:
+			return got
+		}
+	}
+}
+
+func TestChatPresence(t *testing.T) {
+	backend := clitest.NewBackend(t)
+	h := clitest.New(t, initCli.NewCLI(backend.Clients(t)))
+	cabin := clitest.New(t, initCli.NewCLI(backend.Clients(t)))
+
+	login(h, "admiral", "admiral")
+	h.WaitFor("Пользователь: admiral")
+	h.Press(tea.KeyCtrlG)
+	h.WaitFor("bosun: Принято, капитан")
+	login(cabin, "cabin", "cabin")
+	cabin.WaitFor("Пользователь: cabin")
+	cabin.Press(tea.KeyCtrlG)
+	cabin.WaitFor("bosun: Принято, капитан")
+
+	conn, packets := observeChat(t, backend, "bosun", 1)
+	// отметки о прочтении истории при открытии панелей могли прийти уже после подключения
+	collect(packets, 300*time.Millisecond)
+
+	// набор сообщения отправляется одним пакетом, а не на каждую клавишу
+	cabin.Type("Полный вперед")
+	h.WaitFor("cabin печатает…")
+	if got := collect(packets, 300*time.Millisecond); len(got) != 1 || got[0].Type != guildPackets.TypeTyping || got[0].Username != "cabin" {
+		t.Fatalf("ожидался один пакет набора от cabin, получено %+v", got)
+	}
+
+	// отметка о наборе пропадает без новых пакетов
+	h.Timeout = 10 * time.Second
+	h.WaitUntil(func(view string) bool { return !strings.Contains(view, "печатает…") }, "отметка о наборе истекла")
+
+	// новое сообщение прочитано обоими открытыми чатами, каждый сообщает об этом один раз
+	cabin.Press(tea.KeyEnter)
+	cabin.WaitFor("cabin: Полный вперед")
+	h.WaitFor("cabin: Полный вперед")
+	got := collect(packets, 500*time.Millisecond)
+	if len(got) != 2 || got[0].Type != guildPackets.TypeRead || got[1].Type != guildPackets.TypeRead || got[0].Id != got[1].Id {
+		t.Fatalf("ожидались отметки о прочтении от admiral и cabin, получено %+v", got)
+	}
+	id := got[0].Id
+
+	// ✓N - прочитавшие сообщение участники, кроме автора
+	cabin.WaitFor("cabin: Полный вперед ✓1")
+	if err := conn.WriteJSON(guildPackets.Read{Type: guildPackets.TypeRead, Id: id}); err != nil {
+		t.Fatal(err)
+	}
+	cabin.WaitFor("cabin: Полный вперед ✓2")
+
+	// панель без фокуса ввода не отмечает сообщения прочитанными, до возврата фокуса
+	h.Press(tea.KeyCtrlG)
+	h.WaitUntil(func(view string) bool { return !strings.Contains(view, "Чат гильдии (активен)") }, "фокус ввода снят")
+	cabin.Type("Право руля")
+	cabin.Press(tea.KeyEnter)
+	cabin.WaitFor("cabin: Право руля")
+	h.WaitFor("cabin: Право руля")
+	for _, p := range collect(packets, 500*time.Millisecond) {
+		if p.Type == guildPackets.TypeRead && p.Username == "admiral" {
+			t.Fatalf("admiral без фокуса отметил сообщение %s прочитанным", p.Id)
+		}
+	}
+	h.Press(tea.KeyCtrlG)
+	h.WaitFor("Чат гильдии (активен)")
+	got = collect(packets, 500*time.Millisecond)
+	if len(got) != 1 || got[0].Type != guildPackets.TypeRead || got[0].Username != "admiral" || got[0].Id <= id {
+		t.Fatalf("ожидалась отметка admiral о прочтении нового сообщения, получено %+v", got)
+	}
+}
@@ -0,0 +1,305 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/cli/output"
+	"lesta-start-battleship/cli/internal/cli/ui"
+	"lesta-start-battleship/cli/internal/clientdeps"
+)
+
+const (
+	auditPerPage   = 10  // записей журнала на экране
+	auditFetchSize = 500 // сколько последних записей запрашивается с сервера
+)
+
+// auditFilters - вкладки журнала, пустое действие - все записи
+var auditFilters = []guilds.AuditAction{
+	"",
+	guilds.AuditJoinAccepted,
+	guilds.AuditJoinDeclined,
+	guilds.AuditMemberKicked,
+	guilds.AuditRoleChanged,
+	guilds.AuditWarDeclared,
+}
+
+// auditFilterTitles - названия вкладок журнала
+var auditFilterTitles = map[guilds.AuditAction]string{
+	"":                       "Все",
+	guilds.AuditJoinAccepted: "Приняты",
+	guilds.AuditJoinDeclined: "Отклонены",
+	guilds.AuditMemberKicked: "Исключения",
+	guilds.AuditRoleChanged:  "Роли",
+	guilds.AuditWarDeclared:  "Войны",
+}
+
+// auditLogMsg - загружен журнал действий гильдии
+type auditLogMsg struct {
+	Entries []guilds.AuditEntry
+	Local   bool  // журнал с этого устройства: сервер не отдает журнал или недоступен
+	Err     error // ошибка запроса к серверу, если показан локальный журнал
+}
+
+// auditExportedMsg - журнал сохранен в файл
+type auditExportedMsg struct {
+	Path string
+}
+
+// recordAudit - запись действия в локальный журнал. Ошибка записи не отменяет выполненное действие.
+func recordAudit(clients *clientdeps.Client, tag string, entry guilds.AuditEntry) {
+	if err := clients.Audit.Record(tag, entry); err != nil {
+		log.Printf("Ошибка записи журнала гильдии: %v", err)
+	}
+}
+
+// auditText - описание записи журнала
+func auditText(e guilds.AuditEntry) string {
+	switch e.Action {
+	case guilds.AuditJoinAccepted:
+		return fmt.Sprintf("%s принял заявку %s", e.ActorName, e.TargetName)
+	case guilds.AuditJoinDeclined:
+		return fmt.Sprintf("%s отклонил заявку %s", e.ActorName, e.TargetName)
+	case guilds.AuditMemberKicked:
+		return fmt.Sprintf("%s исключил %s", e.ActorName, e.TargetName)
+	case guilds.AuditRoleChanged:
+		return fmt.Sprintf("%s назначил %s роль %s", e.ActorName, e.TargetName, roleTitle(guilds.Role{Title: e.Details}))
+	case guilds.AuditWarDeclared:
+		return fmt.Sprintf("%s [%s] объявил войну гильдии [%s]", e.ActorName, e.Details, e.TargetName)
+	default:
+		return fmt.Sprintf("%s: %s %s", e.ActorName, e.Action, e.TargetName)
+	}
+}
+
+// AuditLogModel - журнал действий гильдии с фильтрами, поиском и экспортом в CSV
+type AuditLogModel struct {
+	parent     tea.Model
+	id         int
+	guild      guilds.GuildResponse
+	entries    []guilds.AuditEntry
+	matches    []guilds.AuditEntry
+	filter     int // индекс в auditFilters
+	query      string
+	offset     int
+	local      bool
+	loading    bool
+	errorMsg   string
+	successMsg string
+	now        func() time.Time
+	Clients    *clientdeps.Client
+}
+
+func NewAuditLogModel(parent tea.Model, id int, guild guilds.GuildResponse, clients *clientdeps.Client) *AuditLogModel {
+	return &AuditLogModel{
+		parent:  parent,
+		id:      id,
+		guild:   guild,
+		loading: true,
+		now:     time.Now,
+		Clients: clients,
+	}
+}
+
+func (m *AuditLogModel) Init() tea.Cmd {
+	return m.loadAudit
+}
+
+func (m *AuditLogModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyUp:
+			if m.offset > 0 {
+				m.offset--
+			}
+			return m, nil
+
+		case tea.KeyDown:
+			if m.offset < len(m.matches)-auditPerPage {
+				m.offset++
+			}
+			return m, nil
+
+		case tea.KeyTab:
+			m.filter = (m.filter + 1) % len(auditFilters)
+			m.applyFilter()
+			return m, nil
+
+		case tea.KeyShiftTab:
+			m.filter = (m.filter + len(auditFilters) - 1) % len(auditFilters)
+			m.applyFilter()
+			return m, nil
+
+		case tea.KeyRunes:
+			m.query += string(msg.Runes)
+			m.applyFilter()
+			return m, nil
+
+		case tea.KeySpace:
+			m.query += " "
+			m.applyFilter()
+			return m, nil
+
+		case tea.KeyBackspace:
+			if runes := []rune(m.query); len(runes) > 0 {
+				m.query = string(runes[:len(runes)-1])
+				m.applyFilter()
+			}
+			return m, nil
+
+		case tea.KeyCtrlE:
+			if m.loading || len(m.matches) == 0 {
+				return m, nil
+			}
+			m.errorMsg, m.successMsg = "", ""
+			return m, m.export(append([]guilds.AuditEntry(nil), m.matches...))
+
+		case tea.KeyEsc:
+			if m.query != "" {
+				m.query = ""
+				m.applyFilter()
+				return m, nil
+			}
+			return m.parent, nil
+		}
+
+	case auditLogMsg:
+		m.loading = false
+		m.entries = msg.Entries
+		m.local = msg.Local
+		m.errorMsg = ""
+		if msg.Err != nil {
+			m.errorMsg = fmt.Sprintf("Ошибка загрузки журнала: %v", msg.Err)
+		}
+		m.applyFilter()
+		return m, nil
+
+	case auditExportedMsg:
+		m.successMsg = "Журнал сохранен: " + msg.Path
+		return m, nil
+
+	case error:
+		m.errorMsg = msg.Error()
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// applyFilter - отбор записей по вкладке и поиску
+func (m *AuditLogModel) applyFilter() {
+	action := auditFilters[m.filter]
+	query := strings.ToLower(strings.TrimSpace(m.query))
+	m.matches = m.matches[:0]
+	for _, e := range m.entries {
+		if action != "" && e.Action != action {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(auditText(e)), query) {
+			continue
+		}
+		m.matches = append(m.matches, e)
+	}
+	m.offset = 0
+}
+
+func (m *AuditLogModel) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(ui.TitleStyle.Render(fmt.Sprintf("Журнал действий гильдии %s [%s]", m.guild.Title, m.guild.Tag)))
+	sb.WriteString("\n")
+	source := "сервер"
+	if m.local {
+		source = "действия с этого устройства"
+	}
+	sb.WriteString(ui.HelpStyle.Render("Источник: " + source))
+	sb.WriteString("\n\n")
+
+	tabs := make([]string, len(auditFilters))
+	for i, action := range auditFilters {
+		if i == m.filter {
+			tabs[i] = ui.SelectedStyle.Render("[" + auditFilterTitles[action] + "]")
+		} else {
+			tabs[i] = ui.NormalStyle.Render(" " + auditFilterTitles[action] + " ")
+		}
+	}
+	sb.WriteString(strings.Join(tabs, " "))
+	sb.WriteString("\n")
+	sb.WriteString(ui.NormalStyle.Render("Поиск: ") + ui.SelectedStyle.Render(m.query+"_"))
+	sb.WriteString("\n\n")
+
+	if m.errorMsg != "" {
+		sb.WriteString(ui.ErrorStyle.Render(m.errorMsg))
+		sb.WriteString("\n\n")
+	}
+	if m.successMsg != "" {
+		sb.WriteString(ui.SuccessStyle.Render(m.successMsg))
+		sb.WriteString("\n\n")
+	}
+
+	switch {
+	case m.loading:
+		sb.WriteString(ui.NormalStyle.Render("Загрузка журнала..."))
+		sb.WriteString("\n")
+	case len(m.matches) == 0:
+		sb.WriteString(ui.NormalStyle.Render("Записей нет"))
+		sb.WriteString("\n")
+	default:
+		for _, e := range m.matches[m.offset:min(m.offset+auditPerPage, len(m.matches))] {
+			sb.WriteString(ui.NormalStyle.Render(fmt.Sprintf("%s  %s", formatWarTime(e.CreatedAt), auditText(e))))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(ui.HelpStyle.Render(fmt.Sprintf("Записи %d-%d из %d", m.offset+1,
+			min(m.offset+auditPerPage, len(m.matches)), len(m.matches))))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(ui.HelpStyle.Render("Ввод - поиск, Tab/Shift+Tab - действие, ↑/↓ - прокрутка, Ctrl+E - экспорт в CSV, Esc - назад"))
+
+	return sb.String()
+}
+
+func (m *AuditLogModel) loadAudit() tea.Msg {
+	ctx := context.Background()
+	page, err := m.Clients.GuildsClient.GetAuditLog(ctx, m.guild.Tag, m.id, 0, auditFetchSize)
+	if err == nil && page != nil {
+		return auditLogMsg{Entries: page.Items}
+	}
+
+	// сервер без журнала или недоступен - действия, выполненные с этого устройства
+	msg := auditLogMsg{Entries: m.Clients.Audit.Entries(m.guild.Tag), Local: true}
+	if err != nil && !errors.Is(err, guilds.ErrAuditUnavailable) {
+		msg.Err = err
+	}
+	return msg
+}
+
+// export - сохранение записей в CSV файл audit_<тег>_<время>.csv в текущем каталоге
+func (m *AuditLogModel) export(entries []guilds.AuditEntry) tea.Cmd {
+	path := fmt.Sprintf("audit_%s_%s.csv", m.guild.Tag, m.now().Format("20060102_150405"))
+	return func() tea.Msg {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("Ошибка экспорта журнала: %w", err)
+		}
+		defer f.Close()
+
+		view := output.AuditLog(&guilds.AuditPagination{Items: entries, TotalItems: len(entries), TotalPages: 1})
+		if err := output.Write(f, output.FormatCSV, view); err != nil {
+			return fmt.Errorf("Ошибка экспорта журнала: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("Ошибка экспорта журнала: %w", err)
+		}
+		return auditExportedMsg{Path: path}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	authapi "lesta-start-battleship/cli/internal/api/auth"
 	"lesta-start-battleship/cli/internal/cli/ui"
 	"lesta-start-battleship/cli/internal/clientdeps"
+	"lesta-start-battleship/cli/storage/profile"
 	"strings"
 )
 
@@ -20,10 +21,15 @@ type AuthModel struct {
 	authMethod  int // 0 - Логин/Пароль, 1 - Google, 2 - Яндекс
 	errorMsg    string
 	Clients     *clientdeps.Client
+
+	profilePicker   bool // режим выбора профиля
+	profiles        []profile.Profile
+	profileSelected int    // len(profiles) - строка создания нового профиля
+	newProfile      string // имя нового профиля
 }
 
 func NewAuthModel(clients *clientdeps.Client) *AuthModel {
-	return &AuthModel{
+	m := &AuthModel{
 		login:       "",
 		password:    "",
 		email:       "",
@@ -33,6 +39,32 @@ func NewAuthModel(clients *clientdeps.Client) *AuthModel {
 		authMethod:  0,
 		Clients:     clients,
 	}
+
+	// подстановка последних использованных настроек профиля
+	if clients.Profiles != nil {
+		if p, ok := clients.Profiles.Get(clients.Profile); ok {
+			m.login = p.Settings.LastLogin
+			m.authMethod = p.Settings.AuthMethod
+		}
+	}
+
+	return m
+}
+
+// OpenProfilePicker - открытие списка профилей
+func (m *AuthModel) OpenProfilePicker() {
+	if m.Clients.Profiles == nil {
+		return
+	}
+	m.profilePicker = true
+	m.profiles = m.Clients.Profiles.List()
+	m.profileSelected = 0
+	m.newProfile = ""
+	for i, p := range m.profiles {
+		if p.Name == m.Clients.Profile {
+			m.profileSelected = i
+		}
+	}
 }
 
 func (m *AuthModel) Init() tea.Cmd {
@@ -40,9 +72,26 @@ func (m *AuthModel) Init() tea.Cmd {
 }
 
 func (m *AuthModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.profilePicker {
+		return m.handleProfilePicker(msg)
+	}
+
 	switch msg := msg.(type) {
+	case SessionRestoreFailedMsg:
+		m.errorMsg = fmt.Sprintf("Сессия профиля недействительна: %v", msg.Err)
+		return m, nil
+
+	case ProfileErrorMsg:
+		m.errorMsg = msg.Err.Error()
+		return m, nil
+
 	case tea.KeyMsg:
 		switch msg.Type {
+		case tea.KeyCtrlP:
+			m.errorMsg = ""
+			m.OpenProfilePicker()
+			return m, nil
+
 		case tea.KeyEnter:
 			return m.handleEnter()
 
@@ -128,7 +177,17 @@ func (m *AuthModel) View() string {
 	var sb strings.Builder
 
 	sb.WriteString(ui.TitleStyle.Render("Морской Бой"))
-	sb.WriteString("\n\n")
+	sb.WriteString("\n")
+	if m.Clients.Profile != "" {
+		sb.WriteString(ui.NormalStyle.Render(fmt.Sprintf("Профиль: %s (%s)", m.Clients.Profile, m.Clients.Endpoints.Auth)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	if m.profilePicker {
+		sb.WriteString(m.renderProfilePicker())
+		return sb.String()
+	}
 
 	authTab := "Авторизация"
 	regTab := "Регистрация"
@@ -210,7 +269,7 @@ func (m *AuthModel) View() string {
 	}
 
 	sb.WriteString(ui.HelpStyle.Render("\nTab - Авторизация/Регистрация, ←/→ - выбор метода, Enter - подтвердить"))
-	sb.WriteString(ui.HelpStyle.Render("\n↑/↓ - Переключение полей, Esc/Ctrl+C - выход\n"))
+	sb.WriteString(ui.HelpStyle.Render("\n↑/↓ - Переключение полей, Ctrl+P - профили, Esc/Ctrl+C - выход\n"))
 
 	return sb.String()
 }
@@ -239,6 +298,7 @@ func (m *AuthModel) handleEnter() (tea.Model, tea.Cmd) {
 				m.errorMsg = fmt.Sprintf("Ошибка авторизации: %v", err)
 				return m, nil
 			}
+			m.saveAuthMethod()
 			return m, func() tea.Msg {
 				return AuthSuccessMsg{
 					Username: profile.Username,
@@ -282,7 +342,106 @@ func (m *AuthModel) handleEnter() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	m.saveAuthMethod()
 	return NewOAuthModel(m, provider, m.Clients, deviceAuth.VerificationURL, deviceAuth.DeviceCode,
 		deviceAuth.UserCode, deviceAuth.Interval, deviceAuth.ExpiresIn), nil
 	//return NewOAuthModel(m, provider, m.Clients, "deviceAuth.VerificationURL", "ABCD-1234"), nil
 }
+
+func (m *AuthModel) saveAuthMethod() {
+	if m.Clients.Profiles == nil {
+		return
+	}
+	authMethod := m.authMethod
+	_ = m.Clients.Profiles.UpdateSettings(m.Clients.Profile, func(s *profile.Settings) {
+		s.AuthMethod = authMethod
+	})
+}
+
+func (m *AuthModel) handleProfilePicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	isNewRow := m.profileSelected == len(m.profiles)
+
+	switch keyMsg.Type {
+	case tea.KeyUp:
+		m.profileSelected = (m.profileSelected - 1 + len(m.profiles) + 1) % (len(m.profiles) + 1)
+
+	case tea.KeyDown:
+		m.profileSelected = (m.profileSelected + 1) % (len(m.profiles) + 1)
+
+	case tea.KeyEnter:
+		name := m.newProfile
+		if !isNewRow {
+			name = m.profiles[m.profileSelected].Name
+		}
+		if name == "" {
+			return m, nil
+		}
+		m.profilePicker = false
+		return m, func() tea.Msg {
+			return ProfileSelectedMsg{Name: name}
+		}
+
+	case tea.KeyBackspace:
+		if isNewRow && len(m.newProfile) > 0 {
+			runes := []rune(m.newProfile)
+			m.newProfile = string(runes[:len(runes)-1])
+		}
+
+	case tea.KeyRunes:
+		if isNewRow {
+			m.newProfile += string(keyMsg.Runes)
+		}
+
+	case tea.KeyEsc, tea.KeyCtrlP:
+		m.profilePicker = false
+
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+func (m *AuthModel) renderProfilePicker() string {
+	var sb strings.Builder
+
+	sb.WriteString(ui.SubtitleStyle.Render("Выбор профиля"))
+	sb.WriteString("\n\n")
+
+	for i, p := range m.profiles {
+		line := p.Name
+		if p.Environment != "" {
+			line += " [" + p.Environment + "]"
+		}
+		if p.Session != nil && p.Session.Username != "" {
+			line += " - " + p.Session.Username
+		}
+		if p.Name == m.Clients.Profile {
+			line += " (текущий)"
+		}
+
+		if i == m.profileSelected {
+			sb.WriteString(ui.SelectedStyle.Render("> " + line))
+		} else {
+			sb.WriteString(ui.NormalStyle.Render("  " + line))
+		}
+		sb.WriteString("\n")
+	}
+
+	newLine := "+ Новый профиль: " + m.newProfile
+	if m.profileSelected == len(m.profiles) {
+		sb.WriteString(ui.SelectedStyle.Render("> " + newLine + "_"))
+	} else {
+		sb.WriteString(ui.NormalStyle.Render("  " + newLine))
+	}
+	sb.WriteString("\n\n")
+
+	sb.WriteString(ui.HelpStyle.Render("↑/↓ - выбор, Enter - переключиться, Esc - назад"))
+
+	return sb.String()
+}
@@ -0,0 +1,396 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+	notifyPackets "lesta-start-battleship/cli/internal/api/websocket/packets/notify"
+	"lesta-start-battleship/cli/internal/cli/ui"
+)
+
+// chatMembersFetch - сколько участников гильдии загружается для автодополнения
+const chatMembersFetch = 100
+
+// Префиксы содержимого сообщений с особым показом
+const (
+	chatMePrefix     = "/me "     // действие автора
+	chatInvitePrefix = "/invite " // приглашение в свою игру, передается шепотом
+)
+
+// errChatOffline - команда требует соединения с сервером чата
+var errChatOffline = errors.New("нет соединения с чатом")
+
+// chatCommand - команда чата, начинается с /
+type chatCommand struct {
+	name  string
+	usage string
+	help  string
+	run   func(c *ChatComponent, args []string) tea.Cmd
+}
+
+// chatCommands - команды чата в порядке показа в /help
+var chatCommands []chatCommand
+
+func init() {
+	chatCommands = []chatCommand{
+		{"/me", "/me действие", "сообщение от третьего лица", (*ChatComponent).cmdMe},
+		{"/who", "/who", "кто сейчас в чате", (*ChatComponent).cmdWho},
+		{"/w", "/w игрок текст", "шепот игроку", (*ChatComponent).cmdWhisper},
+		{"/invite", "/invite игрок", "пригласить в свою игру", (*ChatComponent).cmdInvite},
+		{"/search", "/search текст", "поиск по сохраненной переписке", (*ChatComponent).cmdSearch},
+		{"/mute", "/mute игрок [минуты]", "запретить писать в чат, без срока - бессрочно", (*ChatComponent).cmdMute},
+		{"/unmute", "/unmute игрок", "снять запрет писать", (*ChatComponent).cmdUnmute},
+		{"/ban", "/ban игрок [минуты]", "заблокировать в чате гильдии", (*ChatComponent).cmdBan},
+		{"/unban", "/unban игрок", "снять блокировку", (*ChatComponent).cmdUnban},
+		{"/del", "/del [игрок]", "удалить последнее сообщение игрока, без имени - свое", (*ChatComponent).cmdDelete},
+		{"/clear", "/clear", "очистить окно чата", (*ChatComponent).cmdClear},
+		{"/help", "/help", "список команд", (*ChatComponent).cmdHelp},
+	}
+}
+
+// chatCompletion - перебор вариантов автодополнения повторным Tab
+type chatCompletion struct {
+	options []string
+	next    int
+}
+
+// runCommand - выполнение команды из поля ввода
+func (c *ChatComponent) runCommand(line string) tea.Cmd {
+	fields := strings.Fields(line)
+	for _, command := range chatCommands {
+		if command.name == fields[0] {
+			c.err, c.notice = nil, ""
+			return command.run(c, fields[1:])
+		}
+	}
+	c.err = fmt.Errorf("неизвестная команда %s, список команд - /help", fields[0])
+	return nil
+}
+
+// send - отправка пакета на сервер чата
+func (c *ChatComponent) send(packet guild.Packet) bool {
+	if c.wsClient == nil {
+		c.err = errChatOffline
+		return false
+	}
+	c.wsClient.WriteChan() <- packets.WrapGuild(packet)
+	return true
+}
+
+func (c *ChatComponent) cmdMe(args []string) tea.Cmd {
+	if len(args) == 0 {
+		c.err = errors.New("использование: /me действие")
+		return nil
+	}
+	if c.send(guild.ChatMessage{Msg: chatMePrefix + strings.Join(args, " ")}) {
+		c.scrollToBottom()
+	}
+	return nil
+}
+
+func (c *ChatComponent) cmdWho([]string) tea.Cmd {
+	if c.send(guild.WhoRequest{Type: guild.TypeWho}) {
+		c.notice = "Запрос участников в чате..."
+	}
+	return nil
+}
+
+func (c *ChatComponent) cmdWhisper(args []string) tea.Cmd {
+	if len(args) < 2 {
+		c.err = errors.New("использование: /w игрок текст")
+		return nil
+	}
+	c.send(guild.Whisper{Type: guild.TypeWhisper, To: strings.TrimPrefix(args[0], "@"), Content: strings.Join(args[1:], " ")})
+	return nil
+}
+
+func (c *ChatComponent) cmdInvite(args []string) tea.Cmd {
+	if len(args) != 1 {
+		c.err = errors.New("использование: /invite игрок")
+		return nil
+	}
+	if c.room == "" {
+		c.err = errors.New("сначала создайте свою игру: Матчмейкинг → Своя игра → Создать")
+		return nil
+	}
+	c.send(guild.Whisper{Type: guild.TypeWhisper, To: strings.TrimPrefix(args[0], "@"), Content: chatInvitePrefix + c.room})
+	return nil
+}
+
+func (c *ChatComponent) cmdSearch(args []string) tea.Cmd {
+	c.search(strings.Join(args, " "))
+	return nil
+}
+
+func (c *ChatComponent) cmdMute(args []string) tea.Cmd {
+	c.moderate(guild.TypeMute, "/mute игрок [минуты]", args, true)
+	return nil
+}
+
+func (c *ChatComponent) cmdUnmute(args []string) tea.Cmd {
+	c.moderate(guild.TypeUnmute, "/unmute игрок", args, false)
+	return nil
+}
+
+func (c *ChatComponent) cmdBan(args []string) tea.Cmd {
+	c.moderate(guild.TypeBan, "/ban игрок [минуты]", args, true)
+	return nil
+}
+
+func (c *ChatComponent) cmdUnban(args []string) tea.Cmd {
+	c.moderate(guild.TypeUnban, "/unban игрок", args, false)
+	return nil
+}
+
+// moderate - пакет модерации kind для игрока args[0], term - команда принимает срок в минутах.
+// Права проверяет сервер чата по ролям гильдии.
+func (c *ChatComponent) moderate(kind, usage string, args []string, term bool) {
+	if len(args) == 0 || len(args) > 2 || (len(args) == 2 && !term) {
+		c.err = errors.New("использование: " + usage)
+		return
+	}
+	name := strings.TrimPrefix(args[0], "@")
+	userID, ok := c.userIDByName(name)
+	if !ok {
+		c.err = fmt.Errorf("игрок %s не найден среди участников гильдии", name)
+		return
+	}
+	minutes := 0
+	if len(args) == 2 {
+		var err error
+		if minutes, err = strconv.Atoi(args[1]); err != nil || minutes <= 0 {
+			c.err = errors.New("срок - число минут больше 0")
+			return
+		}
+	}
+	c.send(guild.Moderation{Type: kind, UserId: userID, Minutes: minutes})
+}
+
+func (c *ChatComponent) cmdDelete(args []string) tea.Cmd {
+	if len(args) > 1 {
+		c.err = errors.New("использование: /del [игрок]")
+		return nil
+	}
+	name := c.Username
+	if len(args) == 1 {
+		name = strings.TrimPrefix(args[0], "@")
+	}
+	for i := len(c.messages) - 1; i >= 0; i-- {
+		if msg := c.messages[i]; msg.Id != "" && msg.To == "" && strings.EqualFold(msg.Username, name) {
+			c.send(guild.Delete{Type: guild.TypeDelete, Id: msg.Id})
+			return nil
+		}
+	}
+	c.err = fmt.Errorf("в окне чата нет сообщений %s", name)
+	return nil
+}
+
+// userIDByName - ID игрока по имени среди участников гильдии и авторов сообщений в окне
+func (c *ChatComponent) userIDByName(name string) (int, bool) {
+	for _, member := range c.clients.GuildStore.Members(c.guildTag()) {
+		if strings.EqualFold(member.UserName, name) {
+			return member.UserID, true
+		}
+	}
+	for _, msg := range c.messages {
+		if strings.EqualFold(msg.Username, name) {
+			return msg.UserId, true
+		}
+	}
+	return 0, false
+}
+
+func (c *ChatComponent) cmdClear([]string) tea.Cmd {
+	c.messages = nil
+	c.scrollOffset = 0
+	c.found = nil
+	return nil
+}
+
+func (c *ChatComponent) cmdHelp([]string) tea.Cmd {
+	lines := make([]string, 0, len(chatCommands)+1)
+	for _, command := range chatCommands {
+		lines = append(lines, fmt.Sprintf("%s - %s", command.usage, command.help))
+	}
+	lines = append(lines, "@имя и Tab - упоминание участника")
+	c.notice = strings.Join(lines, "\n")
+	return nil
+}
+
+// complete - автодополнение слова перед курсором: @участник, команда или игрок в командах с игроком.
+// Повторный Tab перебирает варианты.
+func (c *ChatComponent) complete() tea.Cmd {
+	if c.completion == nil {
+		options := c.completions()
+		if len(options) == 0 {
+			// участники гильдии могли устареть в хранилище
+			return c.loadMembers()
+		}
+		c.completion = &chatCompletion{options: options}
+	}
+	c.input.ReplaceWord(c.completion.options[c.completion.next])
+	c.completion.next = (c.completion.next + 1) % len(c.completion.options)
+	return nil
+}
+
+// completions - варианты для слова перед курсором
+func (c *ChatComponent) completions() []string {
+	word := c.input.Word()
+	before := strings.Fields(c.input.BeforeCursor())
+	position := len(before) // номер слова под курсором
+	if word != "" {
+		position--
+	}
+
+	var options []string
+	switch {
+	case strings.HasPrefix(word, "@"):
+		for _, name := range c.memberNames(strings.TrimPrefix(word, "@")) {
+			options = append(options, "@"+name)
+		}
+	case position == 0 && strings.HasPrefix(word, "/"):
+		for _, command := range chatCommands {
+			if strings.HasPrefix(command.name, word) {
+				options = append(options, command.name)
+			}
+		}
+	case position == 1 && slices.Contains([]string{"/w", "/invite", "/mute", "/unmute", "/ban", "/unban", "/del"}, before[0]):
+		options = c.memberNames(word)
+	}
+	return options
+}
+
+// memberNames - имена участников гильдии из хранилища, начинающиеся с prefix, без текущего пользователя
+func (c *ChatComponent) memberNames(prefix string) []string {
+	prefix = strings.ToLower(prefix)
+	var names []string
+	for _, member := range c.clients.GuildStore.Members(c.guildTag()) {
+		if member.UserID != c.userID && strings.HasPrefix(strings.ToLower(member.UserName), prefix) {
+			names = append(names, member.UserName)
+		}
+	}
+	return names
+}
+
+// guildTag - тег гильдии чата
+func (c *ChatComponent) guildTag() string {
+	if self, ok := c.clients.GuildStore.Self(); ok && self.GuildID == c.guildID {
+		return self.GuildTag
+	}
+	if g, ok := c.clients.GuildStore.GuildByID(c.guildID); ok {
+		return g.Tag
+	}
+	return ""
+}
+
+// loadMembers - загрузка участников гильдии в хранилище для автодополнения
+func (c *ChatComponent) loadMembers() tea.Cmd {
+	tag := c.guildTag()
+	if tag == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		members, err := c.clients.GuildsClient.GetGuildMembers(context.Background(), tag, 0, chatMembersFetch)
+		if err != nil {
+			log.Printf("Ошибка загрузки участников для чата: %v", err)
+			return nil
+		}
+		for _, member := range members.Items {
+			c.clients.GuildStore.SetMember(member)
+		}
+		return nil
+	}
+}
+
+// messageLine - сообщение в окне чата: шепот, приглашение, действие /me, упоминание текущего пользователя
+func (c *ChatComponent) messageLine(msg guild.ChatHistoryMessage) string {
+	own := msg.UserId == c.userID
+	style := ui.OtherMessageStyle
+	switch {
+	case own:
+		style = ui.OwnMessageStyle
+	case !c.Focused:
+		style = ui.NewMessageStyle
+	}
+
+	text := fmt.Sprintf("%s: %s", msg.Username, msg.Content)
+	switch room, invite := strings.CutPrefix(msg.Content, chatInvitePrefix); {
+	case msg.To != "" && invite && own:
+		text, style = fmt.Sprintf("✉ вы пригласили %s в комнату %s", msg.To, room), ui.WhisperStyle
+	case msg.To != "" && invite:
+		text, style = fmt.Sprintf("✉ %s приглашает вас в комнату %s", msg.Username, room), ui.WhisperStyle
+	case msg.To != "" && own:
+		text, style = fmt.Sprintf("→ %s: %s", msg.To, msg.Content), ui.WhisperStyle
+	case msg.To != "":
+		text, style = fmt.Sprintf("← %s: %s", msg.Username, msg.Content), ui.WhisperStyle
+	case strings.HasPrefix(msg.Content, chatMePrefix):
+		text = fmt.Sprintf("* %s %s", msg.Username, strings.TrimPrefix(msg.Content, chatMePrefix))
+	}
+
+	if !own && mentions(msg.Content, c.Username) {
+		style = ui.MentionStyle
+	}
+	if seen := c.seen(msg); seen > 0 {
+		return style.Render(text) + ui.HelpStyle.Render(fmt.Sprintf(" ✓%d", seen))
+	}
+	return style.Render(text)
+}
+
+// notifyMessage - уведомление о новом сообщении, которое упоминает текущего пользователя или приглашает его в игру
+func (c *ChatComponent) notifyMessage(msg guild.ChatHistoryMessage) tea.Cmd {
+	if msg.UserId == c.userID || msg.Id == "" {
+		return nil
+	}
+
+	event := notifyPackets.Event{
+		GuildID:   msg.GuildId,
+		GuildTag:  c.guildTag(),
+		UserID:    msg.UserId,
+		UserName:  msg.Username,
+		MessageID: msg.Id,
+		CreatedAt: time.Now(),
+	}
+	if room, ok := strings.CutPrefix(msg.Content, chatInvitePrefix); ok && msg.To != "" {
+		event.Kind, event.RoomID = notifyPackets.KindChatInvite, room
+	} else if mentions(msg.Content, c.Username) {
+		event.Kind = notifyPackets.KindMention
+	} else {
+		return nil
+	}
+
+	fresh := c.clients.Notifications.Add(event)
+	if len(fresh) == 0 {
+		return nil
+	}
+	return func() tea.Msg { return ChatNotifyMsg{Items: fresh} }
+}
+
+// mentions - текст содержит @username отдельным словом, регистр не учитывается
+func mentions(text, username string) bool {
+	if username == "" {
+		return false
+	}
+	lower, mention := strings.ToLower(text), "@"+strings.ToLower(username)
+	for {
+		i := strings.Index(lower, mention)
+		if i < 0 {
+			return false
+		}
+		rest := []rune(lower[i+len(mention):])
+		if len(rest) == 0 || !(unicode.IsLetter(rest[0]) || unicode.IsDigit(rest[0]) || rest[0] == '_') {
+			return true
+		}
+		lower = lower[i+len(mention):]
+	}
+}
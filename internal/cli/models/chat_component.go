@@ -1,64 +1,178 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"lesta-start-battleship/cli/internal/api/websocket"
 	"lesta-start-battleship/cli/internal/api/websocket/packets"
 	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
 	"lesta-start-battleship/cli/internal/api/websocket/strategies"
-	"lesta-start-battleship/cli/internal/cli/handlers"
+	"lesta-start-battleship/cli/internal/cli/editor"
 	"lesta-start-battleship/cli/internal/cli/ui"
+	"lesta-start-battleship/cli/internal/clientdeps"
+	"lesta-start-battleship/cli/storage/chat"
+	"log"
+	"slices"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-const guildChatUrl = "ws://37.9.53.187:8000/api/v1/chat/ws/guild/%d/%d"
+const guildChatPath = "ws/guild/%d/%d"
 
-func formatGuildChatUrl(guildId, userId int) string {
-	return fmt.Sprintf(guildChatUrl, guildId, userId)
+const lobbyChatPath = "ws/lobby/%d"
+
+// chatReconnectDelay - пауза перед повторным подключением к чату
+const chatReconnectDelay = 5 * time.Second
+
+// chatMessageLimit - максимальная длина сообщения в символах
+const chatMessageLimit = 500
+
+// chatPageSize - сколько старых сообщений подгружается при прокрутке выше первого
+const chatPageSize = 50
+
+// chatVisible - сообщений в окне чата
+const chatVisible = 10
+
+// errNoGuild - чат недоступен без гильдии
+var errNoGuild = errors.New("вы не состоите в гильдии")
+
+func formatGuildChatUrl(baseUrl string, guildId, userId int) string {
+	return baseUrl + fmt.Sprintf(guildChatPath, guildId, userId)
+}
+
+func formatLobbyChatUrl(baseUrl string, userId int) string {
+	return baseUrl + fmt.Sprintf(lobbyChatPath, userId)
+}
+
+// chatPacketMsg - пакет из чата. Сообщения закрытого или замененного чата отбрасываются.
+type chatPacketMsg struct {
+	chat   *ChatComponent
+	packet guild.Packet
+}
+
+// chatErrorMsg - разрыв соединения с чатом
+type chatErrorMsg struct {
+	chat *ChatComponent
+	err  error
+}
+
+// chatReconnectMsg - повторное подключение к чату
+type chatReconnectMsg struct {
+	chat *ChatComponent
+}
+
+// chatPingMsg - за время ожидания сообщений не было
+type chatPingMsg struct {
+	chat *ChatComponent
 }
 
 type ChatComponent struct {
 	Username     string
+	userID       int
 	guildID      int
-	messages     []*guild.ChatHistoryMessage
-	input        string
+	lobby        bool // общий чат лобби вместо комнаты гильдии, история хранится под chat.LobbyID
+	messages     []guild.ChatHistoryMessage
+	olderPending bool // у сервера запрошены старые сообщения
+	olderDone    bool // сервер прислал все старые сообщения
+	searchTerm   string
+	found        []guild.ChatHistoryMessage // результаты /search, nil - поиск закрыт
+	foundOffset  int
+	notice       string               // ответ команды чата
+	room         string               // своя игра пользователя для /invite
+	unread       int                  // новые сообщения других игроков с последнего MarkRead
+	typing       map[string]time.Time // кто печатает: имя - время, до которого показывается отметка
+	typingSent   time.Time            // когда отправлен последний пакет набора
+	reads        map[int]string       // последнее прочитанное сообщение по ID участника
+	readSent     string               // ID последнего сообщения, о прочтении которого сообщено серверу
+	input        *editor.Editor
+	completion   *chatCompletion
 	Focused      bool
 	scrollOffset int
 	Visible      bool
 	Width        int
 	err          error
 	wsClient     *websocket.WebsocketClient
+	clients      *clientdeps.Client
 }
 
-func NewChatComponent(username string, guildID int) *ChatComponent {
-	/*client, err := websocket.NewWebsocketClient(
-		formatGuildChatUrl(1, 13), nil, strategies.GuildChatStrategy{})
-	if err != nil {
-		log.Println(err)
-	}*/
-
+// NewChatComponent - чат гильдии guildID для пользователя userID, 0 - пользователь не в гильдии
+func NewChatComponent(username string, userID, guildID int, clients *clientdeps.Client) *ChatComponent {
 	return &ChatComponent{
 		Username: username,
+		userID:   userID,
 		guildID:  guildID,
+		input:    editor.New(chatMessageLimit),
 		Width:    55,
+		clients:  clients,
+	}
+}
+
+// NewLobbyChatComponent - общий чат лобби для пользователя userID
+func NewLobbyChatComponent(username string, userID int, clients *clientdeps.Client) *ChatComponent {
+	c := NewChatComponent(username, userID, chat.LobbyID, clients)
+	c.lobby = true
+	return c
+}
+
+// GuildID - гильдия, к чату которой подключается компонент
+func (c *ChatComponent) GuildID() int {
+	return c.guildID
+}
+
+// SetRoom - своя игра пользователя, в которую /invite приглашает участников
+func (c *ChatComponent) SetRoom(roomID string) {
+	c.room = roomID
+}
+
+// Unread - сообщений других игроков с последнего MarkRead
+func (c *ChatComponent) Unread() int {
+	return c.unread
+}
+
+// MarkRead - сообщения просмотрены
+func (c *ChatComponent) MarkRead() {
+	c.unread = 0
+	c.sendRead()
+}
+
+// title - заголовок окна чата
+func (c *ChatComponent) title() string {
+	if c.lobby {
+		return "Лобби"
 	}
+	return "Чат гильдии"
 }
 
 func (c *ChatComponent) Init() tea.Cmd {
 	if !c.Visible {
 		return nil
 	}
+	if c.guildID == 0 && !c.lobby {
+		c.err = errNoGuild
+		return nil
+	}
+	// сохраненная переписка видна до подключения и без сети
+	c.messages = c.clients.Chat.Latest(c.guildID, chatPageSize)
+	c.scrollToBottom()
+	return tea.Batch(c.connect(), c.loadMembers())
+}
 
-	client, err := websocket.NewWebsocketClient(formatGuildChatUrl(1, 13), nil, strategies.GuildChatStrategy{})
+// connect - подключение к комнате гильдии с токеном текущей сессии
+func (c *ChatComponent) connect() tea.Cmd {
+	url := formatGuildChatUrl(c.clients.Endpoints.GuildChat, c.guildID, c.userID)
+	if c.lobby {
+		url = formatLobbyChatUrl(c.clients.Endpoints.GuildChat, c.userID)
+	}
+	client, err := websocket.NewWebsocketClient(url, c.clients.AuthClient.AuthHeader(), strategies.GuildChatStrategy{})
 	if err != nil {
 		return func() tea.Msg {
-			return handlers.WsErrorMsg{Err: err}
+			return chatErrorMsg{chat: c, err: err}
 		}
 	}
 	c.wsClient = client
+	c.err = nil
 	go c.wsClient.ReadPump()
 	go c.wsClient.WritePump()
 
@@ -73,79 +187,151 @@ func (c *ChatComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		c.Width = msg.Width / 4
 		return c, nil
 
-	case *guild.ChatHistoryMessage:
-		c.messages = append(c.messages, msg)
+	case chatPacketMsg:
+		if msg.chat != c {
+			return c, nil
+		}
+		switch packet := msg.packet.(type) {
+		case *guild.ChatHistory:
+			c.store(packet.Data...)
+			if packet.Type == guild.HistoryPage {
+				c.olderPending = false
+				if !c.loadOlder() {
+					c.olderDone = true
+				}
+				return c, c.waitForMessage()
+			}
+			// история комнаты приходит первой после каждого подключения и дополняется сохраненной
+			c.messages = c.clients.Chat.Latest(c.guildID, max(chatPageSize, len(c.messages)))
+		case *guild.ChatHistoryMessage:
+			// сообщение без ID не сохранить, но показать можно
+			if packet.Id == "" || len(c.store(*packet)) > 0 {
+				c.messages = append(c.messages, *packet)
+				delete(c.typing, packet.Username)
+				if packet.UserId != c.userID {
+					c.unread++
+				}
+				cmd = c.notifyMessage(*packet)
+			}
+		case *guild.Online:
+			names := make([]string, len(packet.Users))
+			for i, user := range packet.Users {
+				names[i] = user.Username
+			}
+			c.notice = fmt.Sprintf("В чате (%d): %s", len(names), strings.Join(names, ", "))
+			return c, c.waitForMessage()
+		case *guild.Error:
+			c.err = errors.New(packet.Message)
+			return c, c.waitForMessage()
+		case *guild.Notice:
+			c.notice = packet.Message
+			return c, c.waitForMessage()
+		case *guild.Deleted:
+			c.remove(packet.Id)
+			return c, c.waitForMessage()
+		case *guild.Typing:
+			return c, tea.Batch(c.waitForMessage(), c.typingStarted(packet))
+		case *guild.Read:
+			c.readBy(packet)
+			return c, c.waitForMessage()
+		}
 		c.scrollToBottom()
-		return c, c.waitForMessage()
+		return c, tea.Batch(c.waitForMessage(), cmd)
+
+	case chatTypingExpiredMsg:
+		if msg.chat == c {
+			c.pruneTyping(time.Now())
+		}
+		return c, nil
 
-	case handlers.WsConnectedMsg:
+	case chatPingMsg:
+		if msg.chat != c || c.wsClient == nil {
+			return c, nil
+		}
 		return c, c.waitForMessage()
 
-	case handlers.WsErrorMsg:
-		c.err = msg.Err
-		return c, tea.Tick(5*time.Second, func(time.Time) tea.Msg {
-			return handlers.ReconnectMsg{}
+	case chatErrorMsg:
+		if msg.chat != c || !c.Visible {
+			return c, nil
+		}
+		c.err = msg.err
+		return c, tea.Tick(chatReconnectDelay, func(time.Time) tea.Msg {
+			return chatReconnectMsg{chat: c}
 		})
 
-	case handlers.ReconnectMsg:
-		err := c.wsClient.Connect(formatGuildChatUrl(1, 13), nil)
-		if err != nil {
-			c.err = err
-		} else {
-			go c.wsClient.ReadPump()
-			go c.wsClient.WritePump()
+	case chatReconnectMsg:
+		if msg.chat != c || !c.Visible {
+			return c, nil
 		}
-
-		return c, c.waitForMessage()
+		if c.wsClient != nil {
+			c.wsClient.Stop()
+			c.wsClient = nil
+		}
+		return c, c.connect()
 
 	case tea.KeyMsg:
 		if !c.Focused {
 			return c, nil
 		}
 
+		if msg.Type != tea.KeyTab {
+			c.completion = nil
+		}
+
 		switch msg.Type {
+		case tea.KeyTab:
+			return c, tea.Batch(c.complete(), func() tea.Msg { return ChatKeyHandledMsg{} })
+
 		case tea.KeyEnter:
-			if c.input == "" {
-				return c, nil
+			if msg.Alt {
+				break
+			}
+			if strings.HasPrefix(strings.TrimSpace(c.input.Value()), "/") {
+				return c, tea.Batch(c.runCommand(c.input.Submit()), func() tea.Msg { return ChatKeyHandledMsg{} })
 			}
-			newMsg := packets.WrapGuild(guild.ChatMessage{Msg: c.input})
-			c.input = ""
+			if c.input.Empty() || c.wsClient == nil {
+				return c, func() tea.Msg { return ChatKeyHandledMsg{} }
+			}
+			newMsg := packets.WrapGuild(guild.ChatMessage{Msg: c.input.Submit()})
+			c.typingSent = time.Time{}
 			c.scrollToBottom()
 			c.wsClient.WriteChan() <- newMsg
-			return c, tea.Batch(c.waitForMessage(),
-				func() tea.Msg { return ChatKeyHandledMsg{} },
-			)
-
-		case tea.KeyBackspace:
-			if len(c.input) > 0 {
-				c.input = c.input[:len(c.input)-1]
-			}
-
-		case tea.KeySpace:
-			c.input += " "
-
-		case tea.KeyRunes:
-			c.input += string(msg.Runes)
+			return c, func() tea.Msg { return ChatKeyHandledMsg{} }
 
-		case tea.KeyDown:
-			if c.scrollOffset < len(c.messages)-10 {
+		case tea.KeyPgDown:
+			if c.found != nil {
+				c.foundOffset = min(c.foundOffset+1, max(len(c.found)-chatVisible, 0))
+			} else if c.scrollOffset < len(c.messages)-chatVisible {
 				c.scrollOffset++
 			}
 			return c, func() tea.Msg { return ChatKeyHandledMsg{} }
 
-		case tea.KeyUp:
-			if c.scrollOffset > 0 {
+		case tea.KeyPgUp:
+			if c.found != nil {
+				c.foundOffset = max(c.foundOffset-1, 0)
+			} else if c.scrollOffset > 0 {
 				c.scrollOffset--
+			} else {
+				c.older()
 			}
 			return c, func() tea.Msg { return ChatKeyHandledMsg{} }
 
 		case tea.KeyEsc:
+			if c.found != nil {
+				c.found = nil
+				return c, func() tea.Msg { return ChatKeyHandledMsg{} }
+			}
 			c.Close()
 			return c, tea.Sequence(
 				func() tea.Msg { return ChatClosedMsg{} },
 				func() tea.Msg { return ChatKeyHandledMsg{} },
 			)
 		}
+		before := c.input.Value()
+		c.input.Update(msg)
+		if c.input.Value() != before {
+			c.sendTyping()
+		}
 	}
 
 	return c, cmd
@@ -158,41 +344,31 @@ func (c *ChatComponent) View() string {
 
 	var sb strings.Builder
 
-	header := ui.ChatHeaderStyle.Render(fmt.Sprintf(" Чат гильдии "))
+	header := ui.ChatHeaderStyle.Render(fmt.Sprintf(" %s ", c.title()))
 	if c.Focused {
-		header = ui.SelectedStyle.Render(fmt.Sprintf(" Чат гильдии (активен) "))
+		header = ui.SelectedStyle.Render(fmt.Sprintf(" %s (активен) ", c.title()))
 	}
 
 	sb.WriteString(header)
 	sb.WriteString("\n\n")
 
-	start := c.scrollOffset
-	if start < 0 {
-		start = 0
-	}
-	end := start + 10
-	if end > len(c.messages) {
-		end = len(c.messages)
-	}
-
-	for _, msg := range c.messages[start:end] {
-		if msg.Username == c.Username {
-			sb.WriteString(ui.OwnMessageStyle.Render(fmt.Sprintf("%s: %s", msg.Username, msg.Content)))
-		} else if c.Focused {
-			sb.WriteString(ui.OtherMessageStyle.Render(fmt.Sprintf("%s: %s", msg.Username, msg.Content)))
-		} else {
-			sb.WriteString(ui.NewMessageStyle.Render(fmt.Sprintf("%s: %s", msg.Username, msg.Content)))
-		}
-		sb.WriteString("\n")
+	if c.found != nil {
+		sb.WriteString(c.searchView())
+	} else {
+		sb.WriteString(c.messagesView())
 	}
 
 	sb.WriteString("\n")
 	if c.Focused {
-		sb.WriteString(ui.ChatInputStyle.Render("> " + c.input + "_"))
+		sb.WriteString(c.inputView())
 	} else {
 		sb.WriteString(ui.HelpStyle.Render("Нажмите Ctrl+G для ввода"))
 	}
 
+	if c.notice != "" {
+		sb.WriteString("\n\n")
+		sb.WriteString(ui.SystemMessageStyle.Render(c.notice))
+	}
 	if c.err != nil {
 		sb.WriteString("\n\n")
 		sb.WriteString(ui.ErrorStyle.Render("Ошибка: " + c.err.Error()))
@@ -201,16 +377,69 @@ func (c *ChatComponent) View() string {
 	return ui.ChatContainerStyle.Width(c.Width).Render(sb.String())
 }
 
+// inputView - поле ввода с курсором и счетчиком символов
+func (c *ChatComponent) inputView() string {
+	return editorView(c.input, "Alt+Enter - новая строка, ↑/↓ - история, PgUp/PgDn - прокрутка")
+}
+
+// editorView - строки буфера ввода с курсором, счетчик символов и подсказка help
+func editorView(input *editor.Editor, help string) string {
+	value := []rune(input.Value())
+	cursor := input.Cursor()
+
+	var sb strings.Builder
+	lineStart := 0
+	for i := 0; i <= len(value); i++ {
+		if i < len(value) && value[i] != '\n' {
+			continue
+		}
+		prompt := "  "
+		if lineStart == 0 {
+			prompt = "> "
+		}
+		line := value[lineStart:i]
+		sb.WriteString(ui.ChatInputStyle.Render(prompt))
+		switch {
+		case cursor < lineStart || cursor > i:
+			sb.WriteString(ui.ChatInputStyle.Render(string(line)))
+		case cursor == i:
+			// курсор в конце строки
+			sb.WriteString(ui.ChatInputStyle.Render(string(line)) + ui.ChatInputStyle.Render("_"))
+		default:
+			col := cursor - lineStart
+			sb.WriteString(ui.ChatInputStyle.Render(string(line[:col])) + ui.CursorStyle.Render(string(line[col])) +
+				ui.ChatInputStyle.Render(string(line[col+1:])))
+		}
+		sb.WriteString("\n")
+		lineStart = i + 1
+	}
+
+	counter := fmt.Sprintf("%d/%d", input.Len(), input.Limit())
+	if input.Len() >= input.Limit() {
+		sb.WriteString(ui.WarningStyle.Render(counter + " - достигнут предел"))
+	} else {
+		sb.WriteString(ui.HelpStyle.Render(counter))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(ui.HelpStyle.Render(help))
+
+	return sb.String()
+}
+
 func (c *ChatComponent) waitForMessage() tea.Cmd {
+	client := c.wsClient
 	return func() tea.Msg {
 		select {
-		case packet := <-c.wsClient.ReadChan():
+		case packet := <-client.ReadChan():
 			var unwrapped guild.Packet
 			if err := packets.UnwrapAsGuild(packet, &unwrapped); err != nil {
+				return chatErrorMsg{chat: c, err: err}
 			}
-			return unwrapped
+			return chatPacketMsg{chat: c, packet: unwrapped}
+		case err := <-client.ErrorChan():
+			return chatErrorMsg{chat: c, err: err}
 		case <-time.After(30 * time.Second):
-			return handlers.PingMsg{}
+			return chatPingMsg{chat: c}
 		}
 	}
 }
@@ -224,31 +453,143 @@ func (c *ChatComponent) waitForMessage() tea.Cmd {
 // 	return false
 // }
 
+// messagesView - окно переписки
+func (c *ChatComponent) messagesView() string {
+	var sb strings.Builder
+	if c.olderPending {
+		sb.WriteString(ui.HelpStyle.Render("Загрузка истории..."))
+		sb.WriteString("\n")
+	} else if c.olderDone && c.scrollOffset == 0 {
+		sb.WriteString(ui.HelpStyle.Render("Начало переписки"))
+		sb.WriteString("\n")
+	}
+
+	start := c.scrollOffset
+	if start < 0 {
+		start = 0
+	}
+	end := start + chatVisible
+	if end > len(c.messages) {
+		end = len(c.messages)
+	}
+
+	for _, msg := range c.messages[start:end] {
+		sb.WriteString(c.messageLine(msg))
+		sb.WriteString("\n")
+	}
+	if line := c.typingLine(); line != "" {
+		sb.WriteString(ui.HelpStyle.Render(line))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// store - запись сообщений в локальную историю гильдии, возвращает сообщения, которых в ней не было
+func (c *ChatComponent) store(messages ...guild.ChatHistoryMessage) []guild.ChatHistoryMessage {
+	added, err := c.clients.Chat.Append(c.guildID, messages...)
+	if err != nil {
+		log.Printf("Ошибка сохранения истории чата: %v", err)
+	}
+	return added
+}
+
+// remove - сообщение id удалено на сервере: из окна, результатов поиска и локальной истории
+func (c *ChatComponent) remove(id string) {
+	drop := func(messages []guild.ChatHistoryMessage) []guild.ChatHistoryMessage {
+		return slices.DeleteFunc(messages, func(m guild.ChatHistoryMessage) bool { return m.Id == id })
+	}
+	c.messages = drop(c.messages)
+	if c.found != nil {
+		c.found = drop(c.found)
+	}
+	c.scrollOffset = min(c.scrollOffset, max(len(c.messages)-chatVisible, 0))
+	if err := c.clients.Chat.Delete(c.guildID, id); err != nil {
+		log.Printf("Ошибка удаления сообщения из истории чата: %v", err)
+	}
+}
+
+// older - сообщения старше первого в окне: из локальной истории, если там их нет - запрос к серверу.
+// Ответ сервера приходит пакетом guild.HistoryPage.
+func (c *ChatComponent) older() {
+	if len(c.messages) == 0 || c.loadOlder() || c.olderPending || c.olderDone || c.wsClient == nil {
+		return
+	}
+	c.olderPending = true
+	c.wsClient.WriteChan() <- packets.WrapGuild(guild.HistoryRequest{
+		Type:   guild.TypeHistory,
+		Before: c.messages[0].Id,
+		Limit:  chatPageSize,
+	})
+}
+
+// loadOlder - страница сообщений старше первого в окне из локальной истории, окно сдвигается на одно сообщение вверх
+func (c *ChatComponent) loadOlder() bool {
+	older := c.clients.Chat.Before(c.guildID, c.messages[0].Id, chatPageSize)
+	if len(older) == 0 {
+		return false
+	}
+	c.messages = append(older, c.messages...)
+	c.scrollOffset += len(older) - 1
+	return true
+}
+
+// search - поиск по сохраненной переписке гильдии, результаты показываются вместо сообщений
+func (c *ChatComponent) search(term string) {
+	if term == "" {
+		c.err = errors.New("укажите текст для поиска: /search текст")
+		return
+	}
+	c.err = nil
+	c.searchTerm = term
+	c.found = c.clients.Chat.Search(c.guildID, term)
+	if c.found == nil {
+		c.found = []guild.ChatHistoryMessage{}
+	}
+	c.foundOffset = max(len(c.found)-chatVisible, 0)
+}
+
+// searchView - результаты поиска с датой сообщений
+func (c *ChatComponent) searchView() string {
+	var sb strings.Builder
+	sb.WriteString(ui.HelpStyle.Render(fmt.Sprintf("Поиск «%s»: найдено %d, Esc - к переписке", c.searchTerm, len(c.found))))
+	sb.WriteString("\n")
+	for _, msg := range c.found[c.foundOffset:min(c.foundOffset+chatVisible, len(c.found))] {
+		sb.WriteString(ui.OtherMessageStyle.Render(fmt.Sprintf("%s %s: %s", formatChatTime(msg.Timestamp), msg.Username, msg.Content)))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// formatChatTime - время сообщения чата, нераспознанное время показывается как есть
+func formatChatTime(timestamp string) string {
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return t.Local().Format("02.01 15:04")
+}
+
 func (c *ChatComponent) scrollToBottom() {
 	c.scrollOffset = 0
-	if len(c.messages) > 10 {
-		c.scrollOffset = len(c.messages) - 10
+	if len(c.messages) > chatVisible {
+		c.scrollOffset = len(c.messages) - chatVisible
 	}
 }
 
 func (c *ChatComponent) Close() {
 	if c.wsClient != nil {
 		c.wsClient.Stop()
+		c.wsClient = nil
 	}
 	c.Visible = false
 	c.Focused = false
+	// после переподключения сервер заново пришлет отметки о прочтении
+	c.typing, c.reads, c.readSent = nil, nil, ""
 }
 
 func (c *ChatComponent) Toggle() {
 	c.Visible = !c.Visible
-	if c.Visible {
-		c.Focused = true
-		if len(c.messages) == 0 {
-			c.Init()
-		}
-	} else {
-		c.Focused = false
-	}
+	c.Focused = c.Visible
 }
 
 func (c *ChatComponent) Focus() {
@@ -0,0 +1,274 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"lesta-start-battleship/cli/internal/cli/ui"
+	"lesta-start-battleship/cli/internal/clientdeps"
+)
+
+// Вкладки панели чата в порядке показа
+const (
+	ChatTabGuild = iota // чат гильдии пользователя
+	ChatTabMatch        // чат комнаты текущей игры
+	ChatTabLobby        // общий чат лобби
+	chatTabsAmount
+)
+
+var chatTabTitles = [chatTabsAmount]string{"Гильдия", "Игра", "Лобби"}
+
+// ChatPanel - панель чата справа от экрана с вкладками гильдии, текущей игры и лобби.
+// Пока панель открыта, чаты гильдии и лобби подключены и считают непрочитанные на неактивных вкладках.
+// Чат игры получает сообщения и при закрытой панели.
+type ChatPanel struct {
+	guild   *ChatComponent
+	match   *MatchChatComponent
+	lobby   *ChatComponent
+	active  int
+	visible bool
+	focused bool
+
+	username string
+	userID   int
+	room     string // своя игра пользователя для /invite
+	clients  *clientdeps.Client
+}
+
+// NewChatPanel - закрытая панель чата пользователя userID, guildID - его гильдия, 0 - не состоит
+func NewChatPanel(username string, userID, guildID int, clients *clientdeps.Client) *ChatPanel {
+	return &ChatPanel{
+		guild:    NewChatComponent(username, userID, guildID, clients),
+		match:    NewMatchChatComponent(username),
+		lobby:    NewLobbyChatComponent(username, userID, clients),
+		username: username,
+		userID:   userID,
+		clients:  clients,
+	}
+}
+
+// GuildID - гильдия вкладки «Гильдия»
+func (p *ChatPanel) GuildID() int {
+	return p.guild.GuildID()
+}
+
+// SetGuild - переход вкладки «Гильдия» в комнату гильдии guildID. Открытая панель переподключается сразу.
+func (p *ChatPanel) SetGuild(guildID int) tea.Cmd {
+	if guildID == p.guild.GuildID() {
+		return nil
+	}
+	p.guild.Close()
+	p.guild = NewChatComponent(p.username, p.userID, guildID, p.clients)
+	p.guild.SetRoom(p.room)
+	if !p.visible {
+		return nil
+	}
+	p.guild.Visible = true
+	p.sync()
+	return p.guild.Init()
+}
+
+// SetRoom - своя игра пользователя, в которую /invite приглашает из чатов гильдии и лобби
+func (p *ChatPanel) SetRoom(roomID string) {
+	p.room = roomID
+	p.guild.SetRoom(roomID)
+	p.lobby.SetRoom(roomID)
+}
+
+// SetMatch - вход в комнату игры или выход из нее
+func (p *ChatPanel) SetMatch(msg MatchRoomMsg) {
+	p.match.SetRoom(msg)
+}
+
+// InMatch - пользователь в комнате игры
+func (p *ChatPanel) InMatch() bool {
+	return p.match.RoomID() != ""
+}
+
+// SetUsername - новое имя пользователя после редактирования профиля
+func (p *ChatPanel) SetUsername(username string) {
+	p.username = username
+	p.guild.Username = username
+	p.match.Username = username
+	p.lobby.Username = username
+}
+
+// Open - открытие панели на вкладке tab с фокусом ввода
+func (p *ChatPanel) Open(tab int) tea.Cmd {
+	var cmd tea.Cmd
+	if !p.visible {
+		p.visible = true
+		p.guild.Visible, p.lobby.Visible = true, true
+		cmd = tea.Batch(p.guild.Init(), p.lobby.Init())
+	}
+	p.active = tab
+	p.focused = true
+	p.sync()
+	p.markRead()
+	return cmd
+}
+
+// Close - закрытие панели, чаты гильдии и лобби отключаются
+func (p *ChatPanel) Close() {
+	p.guild.Close()
+	p.lobby.Close()
+	p.visible = false
+	p.focused = false
+	p.sync()
+}
+
+// IsVisible - панель открыта
+func (p *ChatPanel) IsVisible() bool {
+	return p.visible
+}
+
+// Focused - клавиши попадают в поле ввода активной вкладки
+func (p *ChatPanel) Focused() bool {
+	return p.visible && p.focused
+}
+
+// ToggleFocus - переключение ввода между панелью и экраном
+func (p *ChatPanel) ToggleFocus() {
+	p.focused = !p.focused
+	p.sync()
+	p.markRead()
+}
+
+// Switch - соседняя вкладка: delta 1 - правее, -1 - левее
+func (p *ChatPanel) Switch(delta int) {
+	p.active = (p.active + delta + chatTabsAmount) % chatTabsAmount
+	p.sync()
+	p.markRead()
+}
+
+// Unread - непрочитанных сообщений на вкладке tab
+func (p *ChatPanel) Unread(tab int) int {
+	switch tab {
+	case ChatTabGuild:
+		return p.guild.Unread()
+	case ChatTabMatch:
+		return p.match.Unread()
+	case ChatTabLobby:
+		return p.lobby.Unread()
+	}
+	return 0
+}
+
+// sync - фокус ввода только у активной вкладки открытой панели
+func (p *ChatPanel) sync() {
+	focused := p.visible && p.focused
+	p.guild.Focused = focused && p.active == ChatTabGuild
+	p.match.Focused = focused && p.active == ChatTabMatch
+	p.lobby.Focused = focused && p.active == ChatTabLobby
+}
+
+// markRead - сообщения активной вкладки прочитаны, если на ней фокус ввода.
+// Панель без фокуса остается на экране, но пользователь смотрит на экран под ней.
+func (p *ChatPanel) markRead() {
+	if !p.Focused() {
+		return
+	}
+	switch p.active {
+	case ChatTabGuild:
+		p.guild.MarkRead()
+	case ChatTabMatch:
+		p.match.MarkRead()
+	case ChatTabLobby:
+		p.lobby.MarkRead()
+	}
+}
+
+func (p *ChatPanel) Init() tea.Cmd {
+	return nil
+}
+
+func (p *ChatPanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if !p.Focused() {
+			return p, nil
+		}
+		cmd := p.updateActive(keyMsg)
+		// Esc закрывает поиск в чате, а без него - всю панель
+		if keyMsg.Type == tea.KeyEsc && !p.activeOpen() {
+			p.Close()
+		}
+		return p, cmd
+	}
+
+	var cmds []tea.Cmd
+	_, cmd := p.match.Update(msg)
+	cmds = append(cmds, cmd)
+	// закрытые чаты не читают соединение, пакеты из очереди отбрасываются
+	if p.visible {
+		_, cmd = p.guild.Update(msg)
+		cmds = append(cmds, cmd)
+		_, cmd = p.lobby.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	p.markRead()
+	return p, tea.Batch(cmds...)
+}
+
+// updateActive - клавиша для активной вкладки
+func (p *ChatPanel) updateActive(msg tea.KeyMsg) tea.Cmd {
+	var cmd tea.Cmd
+	switch p.active {
+	case ChatTabGuild:
+		_, cmd = p.guild.Update(msg)
+	case ChatTabMatch:
+		if msg.Type == tea.KeyEsc {
+			return func() tea.Msg { return ChatKeyHandledMsg{} }
+		}
+		_, cmd = p.match.Update(msg)
+	case ChatTabLobby:
+		_, cmd = p.lobby.Update(msg)
+	}
+	return cmd
+}
+
+// activeOpen - активная вкладка не закрылась по Esc
+func (p *ChatPanel) activeOpen() bool {
+	switch p.active {
+	case ChatTabGuild:
+		return p.guild.IsVisible()
+	case ChatTabLobby:
+		return p.lobby.IsVisible()
+	}
+	return false
+}
+
+func (p *ChatPanel) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	var body string
+	switch p.active {
+	case ChatTabGuild:
+		body = p.guild.View()
+	case ChatTabMatch:
+		body = p.match.View()
+	case ChatTabLobby:
+		body = p.lobby.View()
+	}
+	return p.tabsView() + "\n" + body
+}
+
+// tabsView - вкладки с непрочитанными, активная выделена
+func (p *ChatPanel) tabsView() string {
+	tabs := make([]string, chatTabsAmount)
+	for i, title := range chatTabTitles {
+		style := ui.HelpStyle
+		if unread := p.Unread(i); unread > 0 && i != p.active {
+			title = fmt.Sprintf("%s (%d)", title, unread)
+			style = ui.NewMessageStyle
+		}
+		if i == p.active {
+			style = ui.SelectedStyle
+		}
+		tabs[i] = style.Render(" " + title + " ")
+	}
+	return strings.Join(tabs, "│") + "\n" + ui.HelpStyle.Render("Ctrl+←/→ - вкладки")
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+)
+
+// chatTypingInterval - пакет набора сообщения отправляется не чаще
+const chatTypingInterval = 3 * time.Second
+
+// chatTypingTTL - сколько показывается «печатает…» после последнего пакета участника
+const chatTypingTTL = 5 * time.Second
+
+// chatTypingExpiredMsg - пора убрать участников, которые перестали печатать
+type chatTypingExpiredMsg struct {
+	chat *ChatComponent
+}
+
+// sendTyping - пакет набора сообщения после изменения текста в поле ввода, не чаще chatTypingInterval.
+// Набор команды не показывается.
+func (c *ChatComponent) sendTyping() {
+	text := strings.TrimSpace(c.input.Value())
+	if c.wsClient == nil || text == "" || strings.HasPrefix(text, "/") || time.Since(c.typingSent) < chatTypingInterval {
+		return
+	}
+	c.typingSent = time.Now()
+	c.wsClient.WriteChan() <- packets.WrapGuild(guild.Typing{Type: guild.TypeTyping})
+}
+
+// sendRead - отметка о прочтении последнего сообщения комнаты в окне, если она изменилась
+func (c *ChatComponent) sendRead() {
+	if c.wsClient == nil {
+		return
+	}
+	for i := len(c.messages) - 1; i >= 0; i-- {
+		msg := c.messages[i]
+		// шепот не попадает в историю комнаты, отметка по нему ничего не скажет другим
+		if msg.Id == "" || msg.To != "" {
+			continue
+		}
+		if msg.Id != c.readSent {
+			c.readSent = msg.Id
+			c.wsClient.WriteChan() <- packets.WrapGuild(guild.Read{Type: guild.TypeRead, Id: msg.Id})
+		}
+		return
+	}
+}
+
+// typingStarted - участник печатает, отметка снимается через chatTypingTTL
+func (c *ChatComponent) typingStarted(packet *guild.Typing) tea.Cmd {
+	if packet.UserId == c.userID || packet.Username == "" {
+		return nil
+	}
+	if c.typing == nil {
+		c.typing = make(map[string]time.Time)
+	}
+	c.typing[packet.Username] = time.Now().Add(chatTypingTTL)
+	return tea.Tick(chatTypingTTL, func(time.Time) tea.Msg { return chatTypingExpiredMsg{chat: c} })
+}
+
+// pruneTyping - удаление истекших отметок набора
+func (c *ChatComponent) pruneTyping(now time.Time) {
+	for name, until := range c.typing {
+		if !now.Before(until) {
+			delete(c.typing, name)
+		}
+	}
+}
+
+// readBy - отметка о прочтении участника, более ранняя отметка не заменяет позднюю
+func (c *ChatComponent) readBy(packet *guild.Read) {
+	if packet.UserId == c.userID || packet.Id == "" {
+		return
+	}
+	if c.reads == nil {
+		c.reads = make(map[int]string)
+	}
+	if prev, ok := c.reads[packet.UserId]; ok && len(prev) == len(packet.Id) && prev >= packet.Id {
+		return
+	}
+	c.reads[packet.UserId] = packet.Id
+}
+
+// seen - сколько участников, кроме автора и текущего пользователя, прочитали сообщение.
+// ID сообщений одной длины сравниваются как строки: более поздние сообщения имеют больший ID.
+func (c *ChatComponent) seen(msg guild.ChatHistoryMessage) int {
+	if msg.Id == "" || msg.To != "" {
+		return 0
+	}
+	seen := 0
+	for userID, id := range c.reads {
+		if userID != msg.UserId && len(id) == len(msg.Id) && id >= msg.Id {
+			seen++
+		}
+	}
+	return seen
+}
+
+// typingLine - кто сейчас печатает, пусто - никто
+func (c *ChatComponent) typingLine() string {
+	now := time.Now()
+	var names []string
+	for name, until := range c.typing {
+		if now.Before(until) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	switch {
+	case len(names) == 0:
+		return ""
+	case len(names) == 1:
+		return names[0] + " печатает…"
+	case len(names) <= 3:
+		return fmt.Sprintf("%s и %s печатают…", strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+	}
+	return fmt.Sprintf("%s, %s и еще %d печатают…", names[0], names[1], len(names)-2)
+}
@@ -0,0 +1,448 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"lesta-start-battleship/cli/internal/api/websocket"
+	"lesta-start-battleship/cli/internal/api/websocket/packets"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/direct"
+	"lesta-start-battleship/cli/internal/api/websocket/strategies"
+	"lesta-start-battleship/cli/internal/cli/editor"
+	"lesta-start-battleship/cli/internal/cli/ui"
+	"lesta-start-battleship/cli/internal/clientdeps"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const directPath = "ws/direct/%d"
+
+// directPreviewLength - символов последнего сообщения в списке переписок
+const directPreviewLength = 24
+
+// errDirectUnavailable - адрес личных сообщений не задан в конфигурации
+var errDirectUnavailable = errors.New("личные сообщения недоступны: не задан адрес direct")
+
+// formatDirectUrl - адрес личных сообщений пользователя, имя сервер берет из токена
+func formatDirectUrl(baseUrl string, userId int) string {
+	return baseUrl + fmt.Sprintf(directPath, userId)
+}
+
+// directPacketMsg - пакет личных сообщений. Сообщения закрытого или замененного компонента отбрасываются.
+type directPacketMsg struct {
+	direct *DirectComponent
+	packet direct.Packet
+}
+
+// directErrorMsg - разрыв соединения с сервером личных сообщений
+type directErrorMsg struct {
+	direct *DirectComponent
+	err    error
+}
+
+// directReconnectMsg - повторное подключение к серверу личных сообщений
+type directReconnectMsg struct {
+	direct *DirectComponent
+}
+
+// directPingMsg - за время ожидания сообщений не было
+type directPingMsg struct {
+	direct *DirectComponent
+}
+
+// DirectComponent - панель личных сообщений: список переписок и переписка с одним игроком.
+// Соединение держится всю сессию, чтобы счетчики непрочитанных обновлялись и при скрытой панели.
+type DirectComponent struct {
+	Username     string
+	userID       int
+	peerID       int // собеседник открытой переписки, 0 - список переписок
+	peerName     string
+	selected     int // выбранная переписка в списке
+	scrollOffset int
+	input        *editor.Editor
+	Focused      bool
+	Visible      bool
+	Width        int
+	err          error
+	wsClient     *websocket.WebsocketClient
+	clients      *clientdeps.Client
+}
+
+// NewDirectComponent - личные сообщения пользователя userID, 0 - сессии нет
+func NewDirectComponent(username string, userID int, clients *clientdeps.Client) *DirectComponent {
+	clients.Direct.Reset(userID)
+	return &DirectComponent{
+		Username: username,
+		userID:   userID,
+		input:    editor.New(chatMessageLimit),
+		Width:    55,
+		clients:  clients,
+	}
+}
+
+// Init - подключение к серверу личных сообщений на время сессии
+func (c *DirectComponent) Init() tea.Cmd {
+	if c.userID == 0 {
+		return nil
+	}
+	if c.clients.Endpoints.Direct == "" {
+		c.err = errDirectUnavailable
+		return nil
+	}
+	return c.connect()
+}
+
+// connect - подключение с токеном текущей сессии
+func (c *DirectComponent) connect() tea.Cmd {
+	address := formatDirectUrl(c.clients.Endpoints.Direct, c.userID)
+	client, err := websocket.NewWebsocketClient(address, c.clients.AuthClient.AuthHeader(), strategies.DirectStrategy{})
+	if err != nil {
+		return func() tea.Msg {
+			return directErrorMsg{direct: c, err: err}
+		}
+	}
+	c.wsClient = client
+	c.err = nil
+	go c.wsClient.ReadPump()
+	go c.wsClient.WritePump()
+
+	if c.peerID != 0 {
+		c.requestHistory()
+	}
+	return c.waitForMessage()
+}
+
+// Open - показ панели: переписка с игроком userID или список переписок, если userID 0
+func (c *DirectComponent) Open(userID int, username string) {
+	c.Visible = true
+	c.Focused = true
+	if userID == 0 || userID == c.userID {
+		c.showList()
+		return
+	}
+	c.showConversation(userID, username)
+}
+
+// Hide - скрытие панели, соединение сохраняется
+func (c *DirectComponent) Hide() {
+	c.Visible = false
+	c.Focused = false
+	c.clients.Direct.Open(0, "")
+}
+
+// Close - отключение от сервера в конце сессии
+func (c *DirectComponent) Close() {
+	if c.wsClient != nil {
+		c.wsClient.Stop()
+		c.wsClient = nil
+	}
+	c.Hide()
+}
+
+func (c *DirectComponent) IsVisible() bool {
+	return c.Visible
+}
+
+// Unread - непрочитанных личных сообщений
+func (c *DirectComponent) Unread() int {
+	return c.clients.Direct.Unread()
+}
+
+// showList - список переписок вместо открытой переписки
+func (c *DirectComponent) showList() {
+	c.peerID, c.peerName = 0, ""
+	c.selected = 0
+	c.err = nil
+	c.clients.Direct.Open(0, "")
+}
+
+// showConversation - переписка с собеседником, сообщения отмечаются прочитанными
+func (c *DirectComponent) showConversation(userID int, username string) {
+	c.peerID, c.peerName = userID, username
+	c.err = nil
+	c.input.Reset()
+	c.clients.Direct.Open(userID, username)
+	c.scrollToBottom()
+	c.requestHistory()
+	c.send(direct.Read{Type: direct.TypeRead, With: userID})
+}
+
+// requestHistory - последние сообщения открытой переписки с сервера
+func (c *DirectComponent) requestHistory() {
+	c.send(direct.HistoryRequest{Type: direct.TypeHistory, With: c.peerID, Limit: chatPageSize})
+}
+
+// send - отправка пакета, без соединения пакет отбрасывается
+func (c *DirectComponent) send(packet direct.Packet) bool {
+	if c.wsClient == nil {
+		return false
+	}
+	c.wsClient.WriteChan() <- packets.WrapDirect(packet)
+	return true
+}
+
+func (c *DirectComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.Width = msg.Width / 4
+		return c, nil
+
+	case directPacketMsg:
+		if msg.direct != c {
+			return c, nil
+		}
+		return c, tea.Batch(c.handlePacket(msg.packet), c.waitForMessage())
+
+	case directPingMsg:
+		if msg.direct != c || c.wsClient == nil {
+			return c, nil
+		}
+		return c, c.waitForMessage()
+
+	case directErrorMsg:
+		if msg.direct != c || c.userID == 0 {
+			return c, nil
+		}
+		c.err = msg.err
+		return c, tea.Tick(chatReconnectDelay, func(time.Time) tea.Msg {
+			return directReconnectMsg{direct: c}
+		})
+
+	case directReconnectMsg:
+		if msg.direct != c || c.userID == 0 {
+			return c, nil
+		}
+		if c.wsClient != nil {
+			c.wsClient.Stop()
+			c.wsClient = nil
+		}
+		return c, c.connect()
+
+	case tea.KeyMsg:
+		if !c.Visible || !c.Focused {
+			return c, nil
+		}
+		if c.peerID == 0 {
+			return c, c.updateList(msg)
+		}
+		return c, c.updateConversation(msg)
+	}
+
+	return c, nil
+}
+
+// handlePacket - пакет сервера личных сообщений
+func (c *DirectComponent) handlePacket(packet direct.Packet) tea.Cmd {
+	switch packet := packet.(type) {
+	case *direct.Conversations:
+		c.clients.Direct.SetConversations(packet.Items)
+	case *direct.History:
+		c.clients.Direct.Add(packet.Data...)
+		if packet.With == c.peerID {
+			c.scrollToBottom()
+		}
+	case *direct.Message:
+		if !c.clients.Direct.Received(*packet) {
+			return nil
+		}
+		peerID, _ := packet.Peer(c.userID)
+		if peerID == c.peerID {
+			c.scrollToBottom()
+			if c.Visible && packet.FromId != c.userID {
+				c.send(direct.Read{Type: direct.TypeRead, With: peerID})
+			}
+		}
+		if packet.FromId != c.userID && (!c.Visible || peerID != c.peerID) {
+			message := *packet
+			return func() tea.Msg { return DirectReceivedMsg{Message: message} }
+		}
+	case *direct.Error:
+		c.err = errors.New(packet.Message)
+	}
+	return nil
+}
+
+// updateList - клавиши в списке переписок
+func (c *DirectComponent) updateList(msg tea.KeyMsg) tea.Cmd {
+	items := c.clients.Direct.Conversations()
+	switch msg.Type {
+	case tea.KeyUp:
+		if c.selected > 0 {
+			c.selected--
+		}
+	case tea.KeyDown:
+		if c.selected < len(items)-1 {
+			c.selected++
+		}
+	case tea.KeyEnter:
+		if c.selected < len(items) {
+			c.showConversation(items[c.selected].UserId, items[c.selected].Username)
+		}
+	case tea.KeyEsc:
+		c.Hide()
+	default:
+		return nil
+	}
+	return func() tea.Msg { return ChatKeyHandledMsg{} }
+}
+
+// updateConversation - клавиши в открытой переписке
+func (c *DirectComponent) updateConversation(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		if msg.Alt {
+			break
+		}
+		if c.input.Empty() {
+			return func() tea.Msg { return ChatKeyHandledMsg{} }
+		}
+		if c.wsClient == nil {
+			c.err = errChatOffline
+			return func() tea.Msg { return ChatKeyHandledMsg{} }
+		}
+		c.send(direct.Send{Type: direct.TypeSend, To: c.peerID, Content: c.input.Submit()})
+		return func() tea.Msg { return ChatKeyHandledMsg{} }
+
+	case tea.KeyPgDown:
+		if c.scrollOffset < len(c.clients.Direct.Messages(c.peerID))-chatVisible {
+			c.scrollOffset++
+		}
+		return func() tea.Msg { return ChatKeyHandledMsg{} }
+
+	case tea.KeyPgUp:
+		if c.scrollOffset > 0 {
+			c.scrollOffset--
+		}
+		return func() tea.Msg { return ChatKeyHandledMsg{} }
+
+	case tea.KeyEsc:
+		c.showList()
+		return func() tea.Msg { return ChatKeyHandledMsg{} }
+	}
+	c.input.Update(msg)
+	return nil
+}
+
+func (c *DirectComponent) View() string {
+	if !c.Visible {
+		return ""
+	}
+
+	var sb strings.Builder
+	title := " Личные сообщения "
+	if c.peerID != 0 {
+		title = fmt.Sprintf(" Личные: %s ", c.peerName)
+	}
+	if c.Focused {
+		sb.WriteString(ui.SelectedStyle.Render(title + "(активен) "))
+	} else {
+		sb.WriteString(ui.ChatHeaderStyle.Render(title))
+	}
+	sb.WriteString("\n\n")
+
+	if c.peerID == 0 {
+		sb.WriteString(c.listView())
+	} else {
+		sb.WriteString(c.conversationView())
+	}
+
+	if c.err != nil {
+		sb.WriteString("\n\n")
+		sb.WriteString(ui.ErrorStyle.Render("Ошибка: " + c.err.Error()))
+	}
+
+	return ui.ChatContainerStyle.Width(c.Width).Render(sb.String())
+}
+
+// listView - переписки с числом непрочитанных и началом последнего сообщения
+func (c *DirectComponent) listView() string {
+	var sb strings.Builder
+	items := c.clients.Direct.Conversations()
+	if len(items) == 0 {
+		sb.WriteString(ui.HelpStyle.Render("Переписок пока нет. Написать игроку можно из списка участников гильдии или рейтинга (Ctrl+N)."))
+		sb.WriteString("\n\n")
+	}
+	for i, item := range items {
+		line := item.Username
+		if item.Unread > 0 {
+			line += fmt.Sprintf(" (%d)", item.Unread)
+		}
+		preview := []rune(strings.ReplaceAll(item.Last.Content, "\n", " "))
+		if len(preview) > directPreviewLength {
+			preview = append(preview[:directPreviewLength], '…')
+		}
+		line += " - " + string(preview)
+
+		switch {
+		case i == c.selected && c.Focused:
+			sb.WriteString(ui.SelectedStyle.Render("> " + line))
+		case item.Unread > 0:
+			sb.WriteString(ui.NewMessageStyle.Render("  " + line))
+		default:
+			sb.WriteString(ui.NormalStyle.Render("  " + line))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	if c.Focused {
+		sb.WriteString(ui.HelpStyle.Render("↑/↓ - выбор, Enter - открыть, Esc - закрыть"))
+	} else {
+		sb.WriteString(ui.HelpStyle.Render("Нажмите Ctrl+G для ввода"))
+	}
+	return sb.String()
+}
+
+// conversationView - сообщения открытой переписки и поле ввода
+func (c *DirectComponent) conversationView() string {
+	var sb strings.Builder
+	messages := c.clients.Direct.Messages(c.peerID)
+	if len(messages) == 0 {
+		sb.WriteString(ui.HelpStyle.Render("Сообщений пока нет"))
+		sb.WriteString("\n")
+	}
+	start := min(c.scrollOffset, max(len(messages)-chatVisible, 0))
+	for _, msg := range messages[start:min(start+chatVisible, len(messages))] {
+		text := fmt.Sprintf("%s %s: %s", formatChatTime(msg.Timestamp), msg.FromName, msg.Content)
+		if msg.FromId == c.userID {
+			sb.WriteString(ui.OwnMessageStyle.Render(text))
+		} else {
+			sb.WriteString(ui.OtherMessageStyle.Render(text))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	if c.Focused {
+		sb.WriteString(editorView(c.input, "Alt+Enter - новая строка, PgUp/PgDn - прокрутка, Esc - к списку"))
+	} else {
+		sb.WriteString(ui.HelpStyle.Render("Нажмите Ctrl+G для ввода"))
+	}
+	return sb.String()
+}
+
+func (c *DirectComponent) scrollToBottom() {
+	c.scrollOffset = max(len(c.clients.Direct.Messages(c.peerID))-chatVisible, 0)
+}
+
+func (c *DirectComponent) waitForMessage() tea.Cmd {
+	client := c.wsClient
+	if client == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		select {
+		case packet := <-client.ReadChan():
+			var unwrapped direct.Packet
+			if err := packets.UnwrapAsDirect(packet, &unwrapped); err != nil {
+				return directErrorMsg{direct: c, err: err}
+			}
+			return directPacketMsg{direct: c, packet: unwrapped}
+		case err := <-client.ErrorChan():
+			return directErrorMsg{direct: c, err: err}
+		case <-time.After(30 * time.Second):
+			return directPingMsg{direct: c}
+		}
+	}
+}
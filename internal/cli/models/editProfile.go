@@ -2,11 +2,13 @@ package models
 
 import (
 	"context"
+	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"lesta-start-battleship/cli/internal/api/auth"
 	"lesta-start-battleship/cli/internal/cli/ui"
 	"lesta-start-battleship/cli/internal/clientdeps"
 	"strings"
+	"time"
 )
 
 type EditProfileModel struct {
@@ -160,6 +162,8 @@ func (m *EditProfileModel) View() string {
 	var sb strings.Builder
 
 	sb.WriteString(ui.TitleStyle.Render("Редактирование профиля"))
+	sb.WriteString("\n")
+	sb.WriteString(ui.HelpStyle.Render(m.sessionInfo()))
 	sb.WriteString("\n\n")
 
 	// Вкладки
@@ -233,3 +237,25 @@ func (m *EditProfileModel) View() string {
 
 	return sb.String()
 }
+
+// sessionInfo - срок действия текущей сессии по данным access token
+func (m *EditProfileModel) sessionInfo() string {
+	claims, ok := m.Clients.AuthClient.Session()
+	if !ok {
+		return "Сессия: срок действия неизвестен"
+	}
+
+	info := "Сессия: бессрочная"
+	if !claims.ExpiresAt.IsZero() {
+		left := time.Until(claims.ExpiresAt).Round(time.Second)
+		if left > 0 {
+			info = fmt.Sprintf("Сессия действует до %s (осталось %s)", claims.ExpiresAt.Local().Format("15:04:05 02.01.2006"), left)
+		} else {
+			info = fmt.Sprintf("Сессия истекла %s", claims.ExpiresAt.Local().Format("15:04:05 02.01.2006"))
+		}
+	}
+	if len(claims.Roles) > 0 {
+		info += ", роли: " + strings.Join(claims.Roles, ", ")
+	}
+	return info
+}
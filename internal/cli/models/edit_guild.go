@@ -7,7 +7,6 @@ import (
 	"lesta-start-battleship/cli/internal/api/guilds"
 	"lesta-start-battleship/cli/internal/cli/ui"
 	"lesta-start-battleship/cli/internal/clientdeps"
-	guildStorage "lesta-start-battleship/cli/storage/guild"
 	"strings"
 )
 
@@ -123,7 +122,8 @@ func (m *EditGuildModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return err
 			}
 		}
-		guildStorage.Self = *member
+		m.Clients.GuildStore.SetSelf(*member)
+		m.Clients.GuildStore.SetGuild(*msg.Guild)
 		return NewGuildModel(m.id, m.username, m.gold, member, msg.Guild, m.Clients), nil
 
 	case error:
@@ -6,7 +6,6 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"lesta-start-battleship/cli/internal/cli/ui"
 	"lesta-start-battleship/cli/internal/clientdeps"
-	guildStore "lesta-start-battleship/cli/storage/guild"
 	"strings"
 )
 
@@ -58,6 +57,8 @@ func (m *ExitGuildModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if err != nil {
 					return err
 				}
+				m.Client.GuildStore.ClearSelf()
+				m.Client.GuildStore.InvalidateMembers(m.guildTag)
 				return GuildExitedMsg{}
 			}
 
@@ -71,7 +72,6 @@ func (m *ExitGuildModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case GuildExitedMsg:
-		guildStore.CleanStorage()
 		return NewGuildModel(m.id, m.username, m.gold, nil, nil, m.Client), nil
 
 	case error:
@@ -21,7 +21,6 @@ type GuildModel struct {
 	inputGuildTag string // Тег гильдии
 	deleteMode    bool   // Режим удаления гильдии
 	isJoining     bool   // Режим вступления в гильдию
-	isDeclareWar  bool   // Режим объявления войны
 	loading       bool
 	errorMsg      string
 	successMsg    string
@@ -45,12 +44,19 @@ func (m *GuildModel) Init() tea.Cmd {
 }
 
 func (m *GuildModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if m.isJoining {
-		return m.handleJoinInput(msg)
+	switch msg := msg.(type) {
+	case GuildStoreChangedMsg:
+		return m.handleStoreChange(msg.Change)
+
+	case GuildDataMsg:
+		m.Member = msg.Member
+		m.Guild = msg.Guild
+		m.selected = 0
+		return m, nil
 	}
 
-	if m.isDeclareWar {
-		return m.handleDeclareWare(msg)
+	if m.isJoining {
+		return m.handleJoinInput(msg)
 	}
 
 	switch msg := msg.(type) {
@@ -93,6 +99,8 @@ func (m *GuildModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						if err != nil {
 							return err
 						}
+						m.Clients.GuildStore.ClearSelf()
+						m.Clients.GuildStore.RemoveGuild(m.Guild.Tag)
 						return GuildDeletedMsg{}
 					}
 				} else {
@@ -121,7 +129,6 @@ func (m *GuildModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case GuildDeletedMsg:
-		guildStorage.CleanStorage()
 		m.successMsg = "Гильдия успешно удалена"
 		return NewGuildModel(m.id, m.username, m.gold, nil, nil, m.Clients),
 			func() tea.Msg {
@@ -129,13 +136,58 @@ func (m *GuildModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 	case DeclareWarMsg:
-		m.successMsg = "Объявление войны успешно отправлено"
+		m.successMsg = fmt.Sprintf("Гильдии [%s] объявлена война", msg.Target.Tag)
 		return m, nil
 	}
 
 	return m, nil
 }
 
+// handleStoreChange - обновление экрана после изменения хранилища гильдий
+func (m *GuildModel) handleStoreChange(change guildStorage.Change) (tea.Model, tea.Cmd) {
+	store := m.Clients.GuildStore
+
+	switch change.Kind {
+	case guildStorage.SelfChanged:
+		self, ok := store.Self()
+		if !ok {
+			m.Member = nil
+			m.Guild = nil
+			m.selected = 0
+			return m, nil
+		}
+		if m.Guild == nil || m.Guild.ID != self.GuildID {
+			// пользователя приняли в другую гильдию
+			return m, m.loadGuild(self)
+		}
+		m.Member = &self
+		if m.selected >= len(m.getMenuItems()) {
+			m.selected = 0
+		}
+
+	case guildStorage.GuildsChanged:
+		if m.Guild == nil || change.GuildTag != m.Guild.Tag {
+			return m, nil
+		}
+		if guild, ok := store.Guild(change.GuildTag); ok {
+			m.Guild = &guild
+		}
+	}
+	return m, nil
+}
+
+// loadGuild - загрузка гильдии, в которой состоит member
+func (m *GuildModel) loadGuild(member guilds.MemberResponse) tea.Cmd {
+	return func() tea.Msg {
+		guild, err := m.Clients.GuildsClient.GetGuildByTag(context.Background(), member.GuildTag)
+		if err != nil || guild == nil {
+			return nil
+		}
+		m.Clients.GuildStore.SetGuild(*guild)
+		return GuildDataMsg{Member: &member, Guild: guild}
+	}
+}
+
 func (m *GuildModel) View() string {
 	var sb strings.Builder
 
@@ -151,7 +203,7 @@ func (m *GuildModel) View() string {
 		sb.WriteString("\nВы не состоите в гильдии\n")
 	} else {
 		sb.WriteString(fmt.Sprintf("\nГильдия: [%s] %s\n", m.Guild.Tag, m.Guild.Title))
-		sb.WriteString(fmt.Sprintf("Ваша роль: %s\n", m.Member.Role.Title))
+		sb.WriteString("Ваша роль: " + roleBadge(m.Member.Role) + "\n")
 	}
 
 	sb.WriteString("\n")
@@ -161,8 +213,6 @@ func (m *GuildModel) View() string {
 
 		if i == m.selected && item == "Вступить в гильдию" && m.isJoining {
 			line = ui.SelectedStyle.Render("> Вступить в гильдию: " + m.inputGuildTag)
-		} else if i == m.selected && item == "Объявить войну" && m.isDeclareWar {
-			line = ui.SelectedStyle.Render("> Объявить войну: " + m.inputGuildTag)
 		} else if i == m.selected {
 			line = ui.SelectedStyle.Render("> " + item)
 		}
@@ -206,10 +256,11 @@ func (m *GuildModel) getMenuItems() []string {
 	case "cabin_boy":
 		return []string{"Список гильдий", "Список участников", "Чат гильдии", "Покинуть гильдию"}
 	case "owner":
-		return []string{"Объявить войну", "Запросы на войну", "Изменить гильдию", "Список участников", "Список гильдий",
-			"Чат гильдии", "Запросы на вступление", "Удалить гильдию"}
+		return []string{"Объявить войну", "Войны гильдии", "Изменить гильдию", "Список участников", "Список гильдий",
+			"Чат гильдии", "Запросы на вступление", "Журнал действий", "Удалить гильдию"}
 	default:
-		return []string{"Список гильдий", "Список участников", "Чат гильдии", "Запросы на вступление", "Покинуть гильдию"}
+		return []string{"Список гильдий", "Список участников", "Чат гильдии", "Запросы на вступление", "Журнал действий",
+			"Покинуть гильдию"}
 	}
 }
 
@@ -226,7 +277,8 @@ func (m *GuildModel) handleMenuSelection() (tea.Model, tea.Cmd) {
 		model := NewGuildListModel(m, m.id, m.username, m.Clients)
 		return model, model.Init()
 	case "Список участников":
-		return NewMembersListModel(m, m.id, m.username, m.Member.Role.Title, m.Guild.Tag, m.Guild.Title, m.Clients), nil
+		model := NewMembersListModel(m, m.id, m.username, *m.Member, m.Guild.Tag, m.Guild.Title, m.Clients)
+		return model, model.Init()
 	case "Чат гильдии":
 		// Инициализация чата гильдии с правильным guildID
 		guildID := 0
@@ -241,15 +293,19 @@ func (m *GuildModel) handleMenuSelection() (tea.Model, tea.Cmd) {
 	case "Покинуть гильдию":
 		return NewExitGuildModel(m, m.id, m.username, m.gold, m.Guild.Tag, m.Guild.Title, m.Clients), nil
 	case "Объявить войну":
-		m.isDeclareWar = true
-		m.inputGuildTag = ""
-		return m, nil
-	case "Запросы на войну":
-		return NewWarRequestsModel(m, m.id, m.username, m.Guild.Tag, m.Guild.Title, m.Guild.ID, m.Clients), nil
+		m.successMsg = ""
+		model := NewWarTargetModel(m, m.id, m.username, *m.Guild, m.Clients)
+		return model, model.Init()
+	case "Войны гильдии":
+		model := NewWarHubModel(m, m.id, m.username, *m.Guild, m.Clients)
+		return model, model.Init()
 	case "Изменить гильдию":
 		return NewEditGuildModel(m, m.id, m.username, m.gold, m.Guild.Tag, m.Guild.Title, m.Guild.Description, m.Clients), nil
 	case "Запросы на вступление":
 		return NewJoinRequestsModel(m, m.id, m.username, m.Guild.Tag, m.Guild.Title, m.Clients), nil
+	case "Журнал действий":
+		model := NewAuditLogModel(m, m.id, *m.Guild, m.Clients)
+		return model, model.Init()
 	case "Создать гильдию":
 		return NewCreateGuildModel(m, m.id, m.username, m.gold, m.Clients), nil
 	case "Вступить в гильдию":
@@ -312,44 +368,3 @@ func (m *GuildModel) handleJoinInput(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 	return m, nil
 }
-
-func (m *GuildModel) handleDeclareWare(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyEnter:
-			m.loading = true
-			return m, func() tea.Msg {
-				targetGuild, ok := guildStorage.GetGuild(m.inputGuildTag)
-				if !ok {
-					m.loading = false
-					m.errorMsg = fmt.Sprintf("Гильдия с тегом [%s] не найдена", m.inputGuildTag)
-					return nil
-				}
-				ctx := context.Background()
-				_, err := m.Clients.GuildsClient.DeclareWar(ctx, m.Guild.ID, targetGuild.ID, m.id)
-				if err != nil {
-					return err
-				}
-				return DeclareWarMsg{}
-			}
-
-		case tea.KeyBackspace:
-			if len(m.inputGuildTag) > 0 {
-				m.inputGuildTag = m.inputGuildTag[:len(m.inputGuildTag)-1]
-			}
-			return m, nil
-
-		case tea.KeyRunes:
-			m.inputGuildTag += string(msg.Runes)
-			return m, nil
-
-		case tea.KeyEsc:
-			m.isDeclareWar = false
-			m.inputGuildTag = ""
-			m.errorMsg = ""
-			return m, nil
-		}
-	}
-	return m, nil
-}
@@ -0,0 +1,223 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/api/scoreboard"
+	"lesta-start-battleship/cli/internal/cli/ui"
+	"lesta-start-battleship/cli/internal/clientdeps"
+)
+
+// guildMembersFetchSize - участников на странице при поиске владельца гильдии
+const guildMembersFetchSize = 50
+
+// guildDetailMsg - загружены подробности о гильдии
+type guildDetailMsg struct {
+	Owner   string
+	Members int
+	Stats   *scoreboard.GuildStat // nil - рейтинги недоступны
+}
+
+// GuildDetailModel - страница гильдии с заявкой на вступление
+type GuildDetailModel struct {
+	parent     tea.Model
+	id         int
+	username   string
+	guild      guilds.GuildResponse
+	detail     *guildDetailMsg
+	loading    bool
+	sending    bool
+	requested  bool // заявка уже отправлена
+	errorMsg   string
+	successMsg string
+	Clients    *clientdeps.Client
+}
+
+func NewGuildDetailModel(parent tea.Model, id int, username string, guild guilds.GuildResponse,
+	clients *clientdeps.Client) *GuildDetailModel {
+	return &GuildDetailModel{
+		parent:   parent,
+		id:       id,
+		username: username,
+		guild:    guild,
+		loading:  true,
+		Clients:  clients,
+	}
+}
+
+func (m *GuildDetailModel) Init() tea.Cmd {
+	return m.loadDetail
+}
+
+func (m *GuildDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.sending {
+			return m, nil
+		}
+
+		switch msg.Type {
+		case tea.KeyEnter:
+			if err := m.checkJoin(); err != nil {
+				m.errorMsg = err.Error()
+				return m, nil
+			}
+			m.sending = true
+			m.errorMsg = ""
+			return m, m.sendJoinRequest
+
+		case tea.KeyEsc:
+			return m.parent, nil
+		}
+
+	case guildDetailMsg:
+		m.loading = false
+		m.detail = &msg
+		return m, nil
+
+	case JoinRequestSentMsg:
+		m.sending = false
+		m.requested = true
+		m.successMsg = fmt.Sprintf("Запрос в гильдию [%s] отправлен", m.guild.Tag)
+		return m, nil
+
+	case error:
+		m.loading = false
+		m.sending = false
+		m.errorMsg = msg.Error()
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// checkJoin - можно ли отправить заявку в гильдию
+func (m *GuildDetailModel) checkJoin() error {
+	if self, ok := m.Clients.GuildStore.Self(); ok {
+		return fmt.Errorf("Вы уже состоите в гильдии [%s]", self.GuildTag)
+	}
+	if m.guild.IsFull {
+		return fmt.Errorf("Гильдия [%s] заполнена", m.guild.Tag)
+	}
+	if m.requested {
+		return fmt.Errorf("Заявка в гильдию [%s] уже отправлена", m.guild.Tag)
+	}
+	return nil
+}
+
+func (m *GuildDetailModel) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(ui.TitleStyle.Render(fmt.Sprintf("[%s] %s", m.guild.Tag, m.guild.Title)))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(ui.NormalStyle.Render(m.guild.Description))
+	sb.WriteString("\n\n")
+
+	status := ui.SuccessStyle.Render("есть места")
+	if m.guild.IsFull {
+		status = ui.ErrorStyle.Render("заполнена")
+	}
+	sb.WriteString(ui.NormalStyle.Render("Набор: ") + status + "\n")
+	war := ui.SuccessStyle.Render("участвует в войнах")
+	if !m.guild.IsActive {
+		war = ui.WarningStyle.Render("не участвует в войнах")
+	}
+	sb.WriteString(ui.NormalStyle.Render("Статус: ") + war + "\n")
+
+	switch {
+	case m.loading:
+		sb.WriteString("\nЗагрузка данных гильдии...\n")
+	case m.detail != nil:
+		sb.WriteString(ui.NormalStyle.Render(fmt.Sprintf("Владелец: %s", m.detail.Owner)) + "\n")
+		sb.WriteString(ui.NormalStyle.Render(fmt.Sprintf("Участников: %d", m.detail.Members)) + "\n")
+		if m.detail.Stats != nil {
+			sb.WriteString(ui.NormalStyle.Render(fmt.Sprintf("Победы в войнах: %d (место в рейтинге: %d)",
+				m.detail.Stats.WarsVictories, m.detail.Stats.WarsVictoriesRatingPos)) + "\n")
+		} else {
+			sb.WriteString(ui.HelpStyle.Render("Победы в войнах: нет данных") + "\n")
+		}
+	}
+
+	if m.errorMsg != "" {
+		sb.WriteString("\n")
+		sb.WriteString(ui.ErrorStyle.Render(m.errorMsg))
+		sb.WriteString("\n")
+	}
+	if m.successMsg != "" {
+		sb.WriteString("\n")
+		sb.WriteString(ui.SuccessStyle.Render(m.successMsg))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(ui.HelpStyle.Render("Enter - отправить заявку на вступление, Esc - назад"))
+
+	return sb.String()
+}
+
+func (m *GuildDetailModel) loadDetail() tea.Msg {
+	ctx := context.Background()
+
+	owner, members, err := m.findOwner(ctx)
+	if err != nil {
+		return err
+	}
+
+	// без рейтингов страница остается полезной
+	stats, err := m.guildStats(ctx)
+	if err != nil {
+		log.Printf("Ошибка получения рейтинга гильдии %s: %v", m.guild.Tag, err)
+	}
+
+	return guildDetailMsg{Owner: owner, Members: members, Stats: stats}
+}
+
+// findOwner - имя владельца и количество участников гильдии
+func (m *GuildDetailModel) findOwner(ctx context.Context) (string, int, error) {
+	owner := "-"
+	for offset := 0; ; offset += guildMembersFetchSize {
+		page, err := m.Clients.GuildsClient.GetGuildMembers(ctx, m.guild.Tag, offset, guildMembersFetchSize)
+		if err != nil {
+			return "", 0, err
+		}
+		for _, member := range page.Items {
+			if member.UserID == m.guild.OwnerID {
+				return member.UserName, page.TotalItems, nil
+			}
+		}
+		if len(page.Items) == 0 || offset+len(page.Items) >= page.TotalItems {
+			return owner, page.TotalItems, nil
+		}
+	}
+}
+
+// guildStats - рейтинг гильдии по победам в войнах
+func (m *GuildDetailModel) guildStats(ctx context.Context) (*scoreboard.GuildStat, error) {
+	id := m.guild.ID
+	stats, err := m.Clients.ScoreboardClient.GetGuildStats(ctx, &id, "", "", false, pageSize, 1)
+	if err != nil {
+		return nil, err
+	}
+	// id_like отбирает гильдии, в ID которых встречается id
+	for _, stat := range stats.Items {
+		if stat.ID == id {
+			return &stat, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *GuildDetailModel) sendJoinRequest() tea.Msg {
+	ctx := context.Background()
+	if err := m.Clients.GuildsClient.SendJoinRequest(ctx, m.guild.Tag, m.id); err != nil {
+		return fmt.Errorf("Ошибка отправки запроса: %w", err)
+	}
+	return JoinRequestSentMsg{}
+}
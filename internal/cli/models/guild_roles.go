@@ -0,0 +1,51 @@
+package models
+
+import (
+	"fmt"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/cli/ui"
+)
+
+// roleTitles - названия известных ролей гильдии
+var roleTitles = map[string]string{
+	"owner":     "Владелец",
+	"officer":   "Офицер",
+	"cabin_boy": "Юнга",
+}
+
+// roleTitle - название роли для экрана
+func roleTitle(role guilds.Role) string {
+	if title, ok := roleTitles[role.Title]; ok {
+		return title
+	}
+	if role.Title != "" {
+		return role.Title
+	}
+	return fmt.Sprintf("роль #%d", role.ID)
+}
+
+// roleBadge - отметка роли: роли, управляющие участниками, выделяются цветом
+func roleBadge(role guilds.Role) string {
+	badge := "[" + roleTitle(role) + "]"
+	switch {
+	case role.Title == "owner":
+		return ui.WarningStyle.Render(badge)
+	case len(role.RolePromote) > 0:
+		return ui.SuccessStyle.Render(badge)
+	default:
+		return ui.HelpStyle.Render(badge)
+	}
+}
+
+// checkManage - может ли self управлять участником target
+func checkManage(self, target guilds.MemberResponse) error {
+	if target.UserID == self.UserID {
+		return fmt.Errorf("Нельзя изменить собственную роль, чтобы выйти из гильдии, используйте «Покинуть гильдию»")
+	}
+	if !self.Role.CanManage(target.Role.ID) {
+		return fmt.Errorf("Роль %s не позволяет управлять участником %s с ролью %s",
+			roleTitle(self.Role), target.UserName, roleTitle(target.Role))
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/clientdeps"
+	guildStorage "lesta-start-battleship/cli/storage/guild"
+)
+
+// ListenGuildStore - ожидание следующего изменения хранилища гильдий.
+// После получения GuildStoreChangedMsg команду нужно запустить снова.
+func ListenGuildStore(changes <-chan guildStorage.Change) tea.Cmd {
+	return func() tea.Msg {
+		change, ok := <-changes
+		if !ok {
+			return nil
+		}
+		return GuildStoreChangedMsg{Change: change}
+	}
+}
+
+// RefreshGuildSelf - повторный запрос членства пользователя в гильдии.
+// Смена гильдии или роли дойдет до экранов через подписку на хранилище.
+func RefreshGuildSelf(clients *clientdeps.Client, userID int) tea.Cmd {
+	return func() tea.Msg {
+		member, err := clients.GuildsClient.GetMemberByUserID(context.Background(), userID)
+		// 404 - пользователь не состоит в гильдии
+		var statusErr *guilds.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			member, err = nil, nil
+		}
+		if err != nil {
+			// сбой сети или сервера: данные устареют по TTL
+			log.Printf("Ошибка обновления данных гильдии: %v", err)
+			return nil
+		}
+		if member == nil {
+			clients.GuildStore.ClearSelf()
+			return nil
+		}
+		clients.GuildStore.SetSelf(*member)
+		return nil
+	}
+}
@@ -7,22 +7,34 @@ import (
 	"lesta-start-battleship/cli/internal/api/guilds"
 	"lesta-start-battleship/cli/internal/cli/ui"
 	"lesta-start-battleship/cli/internal/clientdeps"
-	guildStorage "lesta-start-battleship/cli/storage/guild"
 	"log"
 	"strings"
 )
 
-const guildPerPage = 10
+const (
+	guildPerPage   = 10
+	guildFetchSize = 50 // размер страницы при загрузке всех гильдий для поиска
+)
+
+// guildListMsg - загружены все гильдии
+type guildListMsg struct {
+	Guilds []guilds.GuildResponse
+}
 
 type GuildListModel struct {
 	parent      tea.Model
 	id          int
 	username    string
-	guilds      []guilds.GuildResponse
+	all         []guilds.GuildResponse // все гильдии сервера
+	guilds      []guilds.GuildResponse // гильдии, подходящие под поиск и фильтры
+	query       string                 // поиск по тегу или названию
+	onlyOpen    bool                   // только незаполненные гильдии
+	onlyActive  bool                   // только участвующие в войнах
 	currentPage int
-	totalPages  int
+	selected    int // индекс на текущей странице
 	loading     bool
 	errorMsg    string
+	offline     offlineState
 	Clients     *clientdeps.Client
 }
 
@@ -37,42 +49,100 @@ func NewGuildListModel(parent tea.Model, id int, username string, clients *clien
 }
 
 func (m *GuildListModel) Init() tea.Cmd {
-	return m.loadGuilds
+	return m.loadGuilds()
 }
 
 func (m *GuildListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.Type {
+		case tea.KeyUp:
+			if m.selected > 0 {
+				m.selected--
+			} else if m.currentPage > 1 {
+				m.currentPage--
+				m.selected = guildPerPage - 1
+			}
+			return m, nil
+
+		case tea.KeyDown:
+			if m.selected < len(m.page())-1 {
+				m.selected++
+			} else if m.currentPage < m.totalPages() {
+				m.currentPage++
+				m.selected = 0
+			}
+			return m, nil
+
 		case tea.KeyLeft:
 			if m.currentPage > 1 {
 				m.currentPage--
-				return m, m.loadGuilds
+				m.selected = 0
 			}
 			return m, nil
 
 		case tea.KeyRight:
-			if m.currentPage < m.totalPages {
+			if m.currentPage < m.totalPages() {
 				m.currentPage++
-				return m, m.loadGuilds
+				m.selected = 0
+			}
+			return m, nil
+
+		case tea.KeyTab:
+			m.onlyOpen = !m.onlyOpen
+			m.applyFilters()
+			return m, nil
+
+		case tea.KeyShiftTab:
+			m.onlyActive = !m.onlyActive
+			m.applyFilters()
+			return m, nil
+
+		case tea.KeyRunes:
+			m.query += string(msg.Runes)
+			m.applyFilters()
+			return m, nil
+
+		case tea.KeySpace:
+			m.query += " "
+			m.applyFilters()
+			return m, nil
+
+		case tea.KeyBackspace:
+			if runes := []rune(m.query); len(runes) > 0 {
+				m.query = string(runes[:len(runes)-1])
+				m.applyFilters()
 			}
 			return m, nil
 
+		case tea.KeyEnter:
+			page := m.page()
+			if len(page) == 0 {
+				return m, nil
+			}
+			detail := NewGuildDetailModel(m, m.id, m.username, page[m.selected], m.Clients)
+			return detail, detail.Init()
+
 		case tea.KeyEsc:
+			if m.query != "" {
+				m.query = ""
+				m.applyFilters()
+				return m, nil
+			}
 			return m.parent, nil
 		}
 
-	case *guilds.GuildPagination:
+	case loadedMsg:
+		return m.Update(m.offline.apply(msg))
+
+	case guildListMsg:
 		m.loading = false
-		for _, guild := range msg.Items {
-			guildStorage.SetGuild(guild.Tag, guild)
-			guildStorage.SetGuildID(guild.ID, guild)
-		}
-		m.guilds = msg.Items
-		m.totalPages = msg.TotalPages
-		if len(m.guilds) == 0 {
-			m.errorMsg = "Список гильдий пуст"
+		m.errorMsg = ""
+		for _, guild := range msg.Guilds {
+			m.Clients.GuildStore.SetGuild(guild)
 		}
+		m.all = msg.Guilds
+		m.applyFilters()
 		return m, nil
 
 	case error:
@@ -84,12 +154,60 @@ func (m *GuildListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// applyFilters - отбор гильдий по поиску и фильтрам, переход на первую страницу
+func (m *GuildListModel) applyFilters() {
+	query := strings.ToLower(strings.TrimSpace(m.query))
+
+	m.guilds = m.guilds[:0]
+	for _, guild := range m.all {
+		if m.onlyOpen && guild.IsFull {
+			continue
+		}
+		if m.onlyActive && !guild.IsActive {
+			continue
+		}
+		if !matchGuild(guild, query) {
+			continue
+		}
+		m.guilds = append(m.guilds, guild)
+	}
+	m.currentPage = 1
+	m.selected = 0
+}
+
+// matchGuild - тег или название гильдии содержит query в нижнем регистре
+func matchGuild(guild guilds.GuildResponse, query string) bool {
+	return query == "" ||
+		strings.Contains(strings.ToLower(guild.Tag), query) ||
+		strings.Contains(strings.ToLower(guild.Title), query)
+}
+
+// totalPages - количество страниц отобранных гильдий
+func (m *GuildListModel) totalPages() int {
+	return max((len(m.guilds)+guildPerPage-1)/guildPerPage, 1)
+}
+
+// page - гильдии текущей страницы
+func (m *GuildListModel) page() []guilds.GuildResponse {
+	start := (m.currentPage - 1) * guildPerPage
+	if start >= len(m.guilds) {
+		return nil
+	}
+	return m.guilds[start:min(start+guildPerPage, len(m.guilds))]
+}
+
 func (m *GuildListModel) View() string {
 	var sb strings.Builder
 
 	sb.WriteString(ui.TitleStyle.Render("Список гильдий"))
 	sb.WriteString("\n")
-	sb.WriteString(ui.NormalStyle.Render(fmt.Sprintf("Страница %d/%d", m.currentPage, m.totalPages)))
+	sb.WriteString(ui.NormalStyle.Render(fmt.Sprintf("Страница %d/%d", m.currentPage, m.totalPages())))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(ui.NormalStyle.Render("Поиск: ") + ui.SelectedStyle.Render(m.query+"_"))
+	sb.WriteString("\n")
+	sb.WriteString(ui.NormalStyle.Render(fmt.Sprintf("Фильтры: %s не заполнена  %s участвует в войнах",
+		checkbox(m.onlyOpen), checkbox(m.onlyActive))))
 	sb.WriteString("\n\n")
 
 	if m.loading {
@@ -99,37 +217,64 @@ func (m *GuildListModel) View() string {
 	if m.errorMsg != "" {
 		sb.WriteString(ui.ErrorStyle.Render(m.errorMsg + "\n"))
 	}
+	sb.WriteString(m.offline.View())
 
-	if len(m.guilds) == 0 {
+	switch {
+	case m.loading:
+	case len(m.all) == 0:
 		sb.WriteString(ui.NormalStyle.Render("Список гильдий пуст"))
-	} else {
-		for _, guild := range m.guilds {
-			line := fmt.Sprintf("%s - %s [%s]", guild.Title, guild.Description, guild.Tag)
-
+	case len(m.guilds) == 0:
+		sb.WriteString(ui.NormalStyle.Render("Гильдии не найдены"))
+	default:
+		for i, guild := range m.page() {
+			line := fmt.Sprintf("[%s] %s - %s", guild.Tag, guild.Title, guild.Description)
 			if guild.IsFull {
-				sb.WriteString(ui.ErrorStyle.Render(line + " (Полная)\n"))
+				line += " (Полная)"
 			} else if !guild.IsActive {
-				sb.WriteString(ui.WarningStyle.Render(line + " (Не участвует в войне)\n"))
-			} else {
-				sb.WriteString(ui.NormalStyle.Render(line + "\n"))
+				line += " (Не участвует в войне)"
 			}
+
+			switch {
+			case i == m.selected:
+				sb.WriteString(ui.SelectedStyle.Render("> " + line))
+			case guild.IsFull:
+				sb.WriteString(ui.ErrorStyle.Render("  " + line))
+			case !guild.IsActive:
+				sb.WriteString(ui.WarningStyle.Render("  " + line))
+			default:
+				sb.WriteString(ui.NormalStyle.Render("  " + line))
+			}
+			sb.WriteString("\n")
 		}
 	}
 
 	sb.WriteString("\n")
-	sb.WriteString(ui.HelpStyle.Render("←/→ - переключение страниц, Esc - назад"))
+	sb.WriteString(ui.HelpStyle.Render("Ввод - поиск, Tab - не заполнена, Shift+Tab - участвует в войнах, " +
+		"↑/↓ - выбор, ←/→ - страницы, Enter - подробнее, Esc - назад"))
 
 	return sb.String()
 }
 
-func (m *GuildListModel) loadGuilds() tea.Msg {
+// checkbox - отметка включенного фильтра
+func checkbox(on bool) string {
+	if on {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+// loadGuilds - загрузка всех гильдий, отметка загрузки ставится до запуска команды
+func (m *GuildListModel) loadGuilds() tea.Cmd {
 	m.loading = true
-	ctx := context.Background()
-	offset := (m.currentPage - 1) * guildPerPage
-	guildsList, err := m.Clients.GuildsClient.GetGuilds(ctx, offset, guildPerPage)
-	log.Printf("Количество гильдий: %d", len(guildsList.Items))
+	clients := m.Clients
+	return loadCached(func(ctx context.Context) tea.Msg { return fetchGuilds(ctx, clients) })
+}
+
+func fetchGuilds(ctx context.Context, clients *clientdeps.Client) tea.Msg {
+	all, err := clients.GuildsClient.GetAllGuilds(ctx, guildFetchSize)
 	if err != nil {
 		return err
 	}
-	return guildsList
+	log.Printf("Количество гильдий: %d", len(all))
+	return guildListMsg{Guilds: all}
 }
@@ -138,6 +138,8 @@ func (m *JoinRequestsModel) handleConfirmState(msg tea.Msg) (tea.Model, tea.Cmd)
 				if err != nil {
 					return err
 				}
+				m.Clients.GuildStore.InvalidateMembers(m.guildTag)
+				recordAudit(m.Clients, m.guildTag, m.auditEntry(guilds.AuditJoinAccepted))
 				return RequestProcessedMsg{Message: "Заявка принята"}
 			}
 
@@ -149,6 +151,7 @@ func (m *JoinRequestsModel) handleConfirmState(msg tea.Msg) (tea.Model, tea.Cmd)
 				if err != nil {
 					return err
 				}
+				recordAudit(m.Clients, m.guildTag, m.auditEntry(guilds.AuditJoinDeclined))
 				return RequestProcessedMsg{Message: "Заявка отклонена"}
 			}
 
@@ -161,6 +164,17 @@ func (m *JoinRequestsModel) handleConfirmState(msg tea.Msg) (tea.Model, tea.Cmd)
 	return m, nil
 }
 
+// auditEntry - запись журнала о решении по заявке выбранного пользователя
+func (m *JoinRequestsModel) auditEntry(action guilds.AuditAction) guilds.AuditEntry {
+	entry := guilds.AuditEntry{Action: action, ActorID: m.id, ActorName: m.username, TargetID: m.selectedUser}
+	for _, req := range m.requests {
+		if req.UserID == m.selectedUser {
+			entry.TargetName = req.UserName
+		}
+	}
+	return entry
+}
+
 func (m *JoinRequestsModel) View() string {
 	var sb strings.Builder
 
@@ -7,10 +7,27 @@ import (
 	"lesta-start-battleship/cli/internal/api/inventory"
 	"lesta-start-battleship/cli/internal/cli/ui"
 	"lesta-start-battleship/cli/internal/clientdeps"
-	guildStorage "lesta-start-battleship/cli/storage/guild"
 	"strings"
 )
 
+// mainMenuItems - пункты главного меню
+var mainMenuItems = []string{
+	"⚔️  Бой",
+	"🎒 Инвентарь",
+	"🏪 Магазин",
+	"🏰 Гильдия",
+	"👤 Редактирование профиля",
+	"🏆 Рейтинги",
+	"🔔 Уведомления",
+	"✉️  Личные сообщения",
+}
+
+// Пункты главного меню со счетчиком непрочитанных
+const (
+	notificationsItem = 6
+	directItem        = 7
+)
+
 type MainMenuModel struct {
 	id       int
 	username string
@@ -40,17 +57,17 @@ func (m *MainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyUp:
-			m.selected = (m.selected - 1 + 6) % 6
+			m.selected = (m.selected - 1 + len(mainMenuItems)) % len(mainMenuItems)
 			return m, nil
 
 		case tea.KeyDown:
-			m.selected = (m.selected + 1) % 6
+			m.selected = (m.selected + 1) % len(mainMenuItems)
 			return m, nil
 
 		case tea.KeyEnter:
 			switch m.selected {
 			case 0: // Бой
-				return NewMatchmakingModel(m, m.id, m.username), nil
+				return NewMatchmakingModel(m, m.id, m.username, m.Clients.Endpoints.Matchmaking), nil
 			case 1: // Инвентарь
 				return m, m.loadHandler
 			case 2: // Магазин
@@ -62,6 +79,10 @@ func (m *MainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return NewEditProfileModel(m.id, m.username, m.gold, m.Clients), nil
 			case 5: // Рейтинг
 				return NewScoreboardModel(m, m.id, m.username, m.gold, m.Clients), nil
+			case notificationsItem:
+				return NewNotificationsModel(m, m.Clients), nil
+			case directItem:
+				return m, func() tea.Msg { return OpenDirectMsg{} }
 			}
 			return m, nil
 
@@ -93,16 +114,17 @@ func (m *MainMenuModel) View() string {
 	sb.WriteString(ui.NormalStyle.Render("Пользователь: " + m.username))
 	sb.WriteString("\n\n")
 
-	menuItems := []string{
-		"⚔️  Бой",
-		"🎒 Инвентарь",
-		"🏪 Магазин",
-		"🏰 Гильдия",
-		"👤 Редактирование профиля",
-		"🏆 Рейтинги",
-	}
-
-	for i, item := range menuItems {
+	for i, item := range mainMenuItems {
+		unread := 0
+		switch i {
+		case notificationsItem:
+			unread = m.Clients.Notifications.Unread()
+		case directItem:
+			unread = m.Clients.Direct.Unread()
+		}
+		if unread > 0 {
+			item += fmt.Sprintf(" (%d)", unread)
+		}
 		if i == m.selected {
 			sb.WriteString(ui.SelectedStyle.Render("> " + item))
 		} else {
@@ -141,13 +163,15 @@ func (m *MainMenuModel) guildHandler() tea.Msg {
 	member, err := m.Clients.GuildsClient.GetMemberByUserID(ctx, m.id)
 	if err != nil || member == nil {
 		// Не состоит в гильдии
+		m.Clients.GuildStore.ClearSelf()
 		return GuildNoMemberMsg{}
 	}
-	guildStorage.Self = *member
+	m.Clients.GuildStore.SetSelf(*member)
 	guild, err := m.Clients.GuildsClient.GetGuildByTag(ctx, member.GuildTag)
 	if err != nil || guild == nil {
 		return GuildNoMemberMsg{}
 	}
+	m.Clients.GuildStore.SetGuild(*guild)
 	return GuildDataMsg{
 		Member: member,
 		Guild:  guild,
@@ -0,0 +1,183 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	matchmaking "github.com/lesta-battleship/matchmaking/pkg/packets"
+
+	"lesta-start-battleship/cli/internal/api/websocket"
+	"lesta-start-battleship/cli/internal/api/websocket/packets"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/room"
+	"lesta-start-battleship/cli/internal/cli/editor"
+	"lesta-start-battleship/cli/internal/cli/ui"
+)
+
+// errNoMatch - чат игры недоступен вне комнаты
+var errNoMatch = errors.New("вы не в игре: создайте свою игру или найдите соперника")
+
+// MatchChatComponent - чат комнаты текущей игры.
+// Сообщения идут через websocket матчмейкинга экрана игры и не сохраняются после выхода из комнаты.
+type MatchChatComponent struct {
+	Username     string
+	roomID       string
+	playerID     string
+	wsClient     *websocket.WebsocketClient // соединение экрана игры, компонент его не закрывает
+	messages     []room.Message
+	unread       int
+	input        *editor.Editor
+	Focused      bool
+	scrollOffset int
+	Width        int
+	err          error
+}
+
+// NewMatchChatComponent - чат игры до входа в комнату
+func NewMatchChatComponent(username string) *MatchChatComponent {
+	return &MatchChatComponent{
+		Username: username,
+		input:    editor.New(chatMessageLimit),
+		Width:    55,
+	}
+}
+
+// SetRoom - вход в комнату игры или выход из нее, переписка прежней комнаты очищается
+func (c *MatchChatComponent) SetRoom(msg MatchRoomMsg) {
+	if msg.RoomID != c.roomID {
+		c.messages = nil
+		c.scrollOffset = 0
+		c.unread = 0
+	}
+	c.roomID, c.playerID, c.wsClient = msg.RoomID, msg.PlayerID, msg.Client
+	c.err = nil
+}
+
+// RoomID - комната текущей игры, пустая строка - игрок не в игре
+func (c *MatchChatComponent) RoomID() string {
+	return c.roomID
+}
+
+// Unread - сообщений соперника с последнего MarkRead
+func (c *MatchChatComponent) Unread() int {
+	return c.unread
+}
+
+// MarkRead - сообщения просмотрены
+func (c *MatchChatComponent) MarkRead() {
+	c.unread = 0
+}
+
+func (c *MatchChatComponent) Init() tea.Cmd {
+	return nil
+}
+
+func (c *MatchChatComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.Width = msg.Width / 4
+		return c, nil
+
+	case RoomChatMsg:
+		if c.roomID == "" {
+			return c, nil
+		}
+		c.messages = append(c.messages, msg.Message)
+		c.unread++
+		c.scrollToBottom()
+		return c, nil
+
+	case tea.KeyMsg:
+		if !c.Focused {
+			return c, nil
+		}
+
+		switch msg.Type {
+		case tea.KeyEnter:
+			if msg.Alt {
+				break
+			}
+			c.send()
+			return c, func() tea.Msg { return ChatKeyHandledMsg{} }
+
+		case tea.KeyPgDown:
+			if c.scrollOffset < len(c.messages)-chatVisible {
+				c.scrollOffset++
+			}
+			return c, func() tea.Msg { return ChatKeyHandledMsg{} }
+
+		case tea.KeyPgUp:
+			if c.scrollOffset > 0 {
+				c.scrollOffset--
+			}
+			return c, func() tea.Msg { return ChatKeyHandledMsg{} }
+		}
+		c.input.Update(msg)
+	}
+
+	return c, nil
+}
+
+// send - отправка сообщения сопернику. Сервер пересылает его остальным игрокам комнаты, автору оно добавляется сразу.
+func (c *MatchChatComponent) send() {
+	if c.input.Empty() {
+		return
+	}
+	if c.wsClient == nil || c.roomID == "" {
+		c.err = errNoMatch
+		return
+	}
+	message := room.Message{From: c.Username, Text: c.input.Submit(), Timestamp: time.Now().UTC().Format(time.RFC3339)}
+	c.wsClient.SendPacket(packets.WrapMatchmaking(matchmaking.NewPlayerMessage(c.playerID, room.Encode(message))))
+	c.messages = append(c.messages, message)
+	c.err = nil
+	c.scrollToBottom()
+}
+
+func (c *MatchChatComponent) View() string {
+	var sb strings.Builder
+
+	title := "Чат игры"
+	if c.roomID != "" {
+		title = fmt.Sprintf("Чат игры %s", c.roomID)
+	}
+	header := ui.ChatHeaderStyle.Render(fmt.Sprintf(" %s ", title))
+	if c.Focused {
+		header = ui.SelectedStyle.Render(fmt.Sprintf(" %s (активен) ", title))
+	}
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	if c.roomID == "" {
+		sb.WriteString(ui.HelpStyle.Render(errNoMatch.Error()))
+		sb.WriteString("\n")
+	}
+	for _, msg := range c.messages[c.scrollOffset:min(c.scrollOffset+chatVisible, len(c.messages))] {
+		style := ui.OtherMessageStyle
+		if msg.From == c.Username {
+			style = ui.OwnMessageStyle
+		}
+		sb.WriteString(style.Render(fmt.Sprintf("%s: %s", msg.From, msg.Text)))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	if c.Focused {
+		sb.WriteString(editorView(c.input, "Alt+Enter - новая строка, ↑/↓ - история, PgUp/PgDn - прокрутка"))
+	} else {
+		sb.WriteString(ui.HelpStyle.Render("Нажмите Ctrl+G для ввода"))
+	}
+
+	if c.err != nil {
+		sb.WriteString("\n\n")
+		sb.WriteString(ui.ErrorStyle.Render("Ошибка: " + c.err.Error()))
+	}
+
+	return ui.ChatContainerStyle.Width(c.Width).Render(sb.String())
+}
+
+func (c *MatchChatComponent) scrollToBottom() {
+	c.scrollOffset = max(len(c.messages)-chatVisible, 0)
+}
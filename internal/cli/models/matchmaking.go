@@ -1,31 +1,30 @@
 package models
 
 import (
-	"fmt"
 	"lesta-start-battleship/cli/internal/cli/ui"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-const matchmakingUrl = "ws://37.9.53.32:80/matchmaking/%s"
-
-func formatMatchmakingUrl(matchType string) string {
-	return fmt.Sprintf(matchmakingUrl, matchType)
+func formatMatchmakingUrl(baseUrl, matchType string) string {
+	return baseUrl + matchType
 }
 
 type MatchmakingModel struct {
-	parent   tea.Model
-	id       int
-	username string
-	selected int
+	parent         tea.Model
+	id             int
+	username       string
+	selected       int
+	matchmakingUrl string
 }
 
-func NewMatchmakingModel(parent tea.Model, id int, username string) *MatchmakingModel {
+func NewMatchmakingModel(parent tea.Model, id int, username, matchmakingUrl string) *MatchmakingModel {
 	return &MatchmakingModel{
-		parent:   parent,
-		id:       id,
-		username: username,
+		parent:         parent,
+		id:             id,
+		username:       username,
+		matchmakingUrl: matchmakingUrl,
 	}
 }
 
@@ -50,15 +49,15 @@ func (m *MatchmakingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.KeyEnter:
 			switch m.selected {
 			case 0:
-				model := NewMatchmakingWaitScreenModel(m, m.username, "random")
+				model := NewMatchmakingWaitScreenModel(m, m.username, formatMatchmakingUrl(m.matchmakingUrl, "random"))
 				return model, model.Init()
 			case 1:
-				model := NewMatchmakingWaitScreenModel(m, m.username, "ranked")
+				model := NewMatchmakingWaitScreenModel(m, m.username, formatMatchmakingUrl(m.matchmakingUrl, "ranked"))
 				return model, model.Init()
 			case 2:
 				return m, nil
 			case 3:
-				model := NewMatchmakingCustomMenuModel(m, m.username)
+				model := NewMatchmakingCustomMenuModel(m, m.username, formatMatchmakingUrl(m.matchmakingUrl, "custom"))
 				return model, model.Init()
 			}
 			return m, nil
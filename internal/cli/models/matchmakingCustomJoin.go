@@ -67,7 +67,7 @@ func (m *MatchmakingCustomJoinModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case *matchmaking.PlayerMessage:
 		model := NewMatchmakingCustomRoomModel(m.parent, m.username, m.userId, m.wsClient)
 		model.roomId = msg.Msg
-		return model, model.Init()
+		return model, tea.Batch(model.Init(), model.entered())
 	}
 
 	return m, nil
@@ -25,7 +25,7 @@ type MatchmakingCustomMenuModel struct {
 	wsClient *websocket.WebsocketClient
 }
 
-func NewMatchmakingCustomMenuModel(parent tea.Model, username string) *MatchmakingCustomMenuModel {
+func NewMatchmakingCustomMenuModel(parent tea.Model, username, url string) *MatchmakingCustomMenuModel {
 	id := rand.Text()
 	token := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"sub": id})
 	tokenString, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
@@ -35,7 +35,6 @@ func NewMatchmakingCustomMenuModel(parent tea.Model, username string) *Matchmaki
 	header := http.Header{}
 	header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenString))
 
-	url := formatMatchmakingUrl("custom")
 	client, err := websocket.NewWebsocketClient(url, header, strategies.MatchmakingStrategy{})
 	if err != nil {
 		log.Fatal(err)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"lesta-start-battleship/cli/internal/api/websocket"
 	"lesta-start-battleship/cli/internal/api/websocket/packets"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/room"
 	"lesta-start-battleship/cli/internal/cli/ui"
 	"log"
 	"strings"
@@ -44,13 +45,25 @@ func (m *MatchmakingCustomRoomModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			packet := matchmaking.NewDisconnect(m.userId)
 
 			m.wsClient.SendPacket(packets.WrapMatchmaking(packet))
-			return m.parent, nil
+			return m.parent, tea.Batch(
+				func() tea.Msg { return CustomRoomMsg{} },
+				func() tea.Msg { return MatchRoomMsg{} },
+			)
 
 		case tea.KeyCtrlC:
 			return m, tea.Quit
 		}
 	case *matchmaking.PlayerMessage:
+		if chat, ok := room.Decode(msg.Msg); ok {
+			return m, tea.Batch(m.waitForMessage(), func() tea.Msg { return RoomChatMsg{Message: chat} })
+		}
+		first := m.roomId == "Wait"
 		m.roomId = msg.Msg
+		if first {
+			// первое сообщение сервера - ID комнаты, по нему чат приглашает игроков
+			roomID := msg.Msg
+			return m, tea.Batch(m.waitForMessage(), func() tea.Msg { return CustomRoomMsg{RoomID: roomID} }, m.entered())
+		}
 		return m, m.waitForMessage()
 	}
 
@@ -68,11 +81,19 @@ func (m *MatchmakingCustomRoomModel) View() string {
 	fmt.Fprintf(&sb, "ID: %q", m.roomId)
 
 	sb.WriteString("\n\n")
-	sb.WriteString(ui.NormalStyle.Render("Esc - выход"))
+	sb.WriteString(ui.NormalStyle.Render("Ctrl+G - чат с соперником, Esc - выход"))
 
 	return sb.String()
 }
 
+// entered - игрок в комнате roomId, вкладка «Игра» панели чата подключается к ней
+func (m *MatchmakingCustomRoomModel) entered() tea.Cmd {
+	roomID, playerID, client := m.roomId, m.userId, m.wsClient
+	return func() tea.Msg {
+		return MatchRoomMsg{RoomID: roomID, PlayerID: playerID, Client: client}
+	}
+}
+
 func (c *MatchmakingCustomRoomModel) waitForMessage() tea.Cmd {
 	return func() tea.Msg {
 		select {
@@ -31,7 +31,7 @@ type MatchmakingWaitScreenModel struct {
 	wsClient *websocket.WebsocketClient
 }
 
-func NewMatchmakingWaitScreenModel(parent tea.Model, username, matchType string) *MatchmakingWaitScreenModel {
+func NewMatchmakingWaitScreenModel(parent tea.Model, username, url string) *MatchmakingWaitScreenModel {
 	id := rand.Text()
 	token := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"sub": id})
 	tokenString, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
@@ -41,7 +41,6 @@ func NewMatchmakingWaitScreenModel(parent tea.Model, username, matchType string)
 	header := http.Header{}
 	header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenString))
 
-	url := formatMatchmakingUrl(matchType)
 	client, err := websocket.NewWebsocketClient(url, header, strategies.MatchmakingStrategy{})
 	if err != nil {
 		log.Fatal(err)
@@ -82,7 +81,7 @@ func (m *MatchmakingWaitScreenModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case *matchmaking.PlayerMessage:
 		model := NewMatchmakingCustomRoomModel(m, m.username, m.userId, m.wsClient)
 		model.roomId = msg.Msg
-		return model, model.Init()
+		return model, tea.Batch(model.Init(), model.entered())
 	case tickMsg:
 		m.endTime = time.Time(msg)
 		return m, m.waitForMessage()
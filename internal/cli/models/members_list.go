@@ -13,44 +13,77 @@ import (
 
 const membersPerPage = 10
 
+// memberAction - действие над участником гильдии
+type memberAction struct {
+	roleID int // новая роль, 0 - исключение из гильдии
+	label  string
+}
+
 type MembersListModel struct {
-	parent       tea.Model
-	id           int
-	username     string
-	userRole     string
-	guildTag     string
-	guildName    string
-	members      []guilds.MemberResponse
-	currentPage  int
-	totalPages   int
-	selected     int
-	actionMode   bool // true - выбор действия, false - выбор участников
-	actionType   int  // 0 - изменить роль, 1 - удалить участника
-	loading      bool
-	errorMsg     string
-	confirmState bool // true - подтверждение действия
-	Clients      *clientdeps.Client
+	parent         tea.Model
+	id             int
+	username       string
+	self           guilds.MemberResponse // текущий пользователь, если в хранилище гильдий нет свежих данных
+	guildTag       string
+	guildName      string
+	members        []guilds.MemberResponse
+	roles          map[int]guilds.Role // роли, встреченные в списке участников: id -> роль
+	currentPage    int
+	totalPages     int
+	selected       int
+	actionMode     bool // true - выбор действия, false - выбор участников
+	actions        []memberAction
+	actionSelected int
+	loading        bool
+	errorMsg       string
+	successMsg     string
+	confirmState   bool // true - подтверждение действия
+	Clients        *clientdeps.Client
 }
 
-func NewMembersListModel(parent tea.Model, id int, username, userRole, guildTag, guildName string,
+func NewMembersListModel(parent tea.Model, id int, username string, self guilds.MemberResponse, guildTag, guildName string,
 	clients *clientdeps.Client) *MembersListModel {
 	return &MembersListModel{
 		parent:      parent,
 		id:          id,
 		username:    username,
-		userRole:    userRole,
+		self:        self,
 		guildTag:    guildTag,
 		guildName:   guildName,
+		roles:       map[int]guilds.Role{self.Role.ID: self.Role},
 		currentPage: 1,
 		Clients:     clients,
 	}
 }
 
 func (m *MembersListModel) Init() tea.Cmd {
-	return m.loadMembers
+	return m.loadMembers()
 }
 
 func (m *MembersListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case MemberRoleChangeMsg:
+		m.confirmState = false
+		m.actionMode = false
+		m.successMsg = fmt.Sprintf("Роль участника %s изменена", msg.Username)
+		return m, nil
+
+	case MemberDeleteMsg:
+		m.confirmState = false
+		m.actionMode = false
+		m.successMsg = fmt.Sprintf("Участник %s исключен из гильдии", msg.Username)
+		return m, nil
+
+	case GuildStoreChangedMsg:
+		return m.handleStoreChange(msg)
+
+	case error:
+		m.loading = false
+		m.confirmState = false
+		m.errorMsg = msg.Error()
+		return m, nil
+	}
+
 	if m.confirmState {
 		return m.handleConfirmState(msg)
 	}
@@ -62,6 +95,68 @@ func (m *MembersListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m.handleNormalMode(msg)
 }
 
+// handleStoreChange - обновление экрана после изменения хранилища гильдий
+func (m *MembersListModel) handleStoreChange(msg GuildStoreChangedMsg) (tea.Model, tea.Cmd) {
+	switch msg.Change.Kind {
+	case guildStorage.MembersChanged:
+		// участников изменили в этой или другой сессии - список перечитывается
+		if msg.Change.GuildTag == m.guildTag {
+			return m, m.loadMembers()
+		}
+
+	case guildStorage.SelfChanged:
+		self, ok := m.Clients.GuildStore.Self()
+		if !ok || self.GuildTag != m.guildTag {
+			// пользователь больше не состоит в гильдии
+			return m.parent.Update(msg)
+		}
+		if self.Role.ID != m.self.Role.ID {
+			m.actionMode = false
+			m.confirmState = false
+			m.errorMsg = fmt.Sprintf("Ваша роль изменилась: %s", roleTitle(self.Role))
+		}
+		m.self = self
+		m.roles[self.Role.ID] = self.Role
+	}
+	return m, nil
+}
+
+// currentSelf - текущий пользователь из хранилища гильдий сессии
+func (m *MembersListModel) currentSelf() guilds.MemberResponse {
+	if self, ok := m.Clients.GuildStore.Self(); ok {
+		return self
+	}
+	return m.self
+}
+
+// roleTitleByID - название роли по ID из встреченных ролей
+func (m *MembersListModel) roleTitleByID(id int) string {
+	if role, ok := m.roles[id]; ok {
+		return roleTitle(role)
+	}
+	return roleTitle(guilds.Role{ID: id})
+}
+
+// memberActions - действия, разрешенные текущему пользователю над участником target
+func (m *MembersListModel) memberActions(target guilds.MemberResponse) []memberAction {
+	var actions []memberAction
+	for _, roleID := range m.currentSelf().Role.RolePromote {
+		if roleID == target.Role.ID {
+			continue
+		}
+		actions = append(actions, memberAction{
+			roleID: roleID,
+			label:  "Назначить роль: " + m.roleTitleByID(roleID),
+		})
+	}
+	return append(actions, memberAction{label: "Исключить из гильдии"})
+}
+
+// canManageAny - может ли текущий пользователь управлять хотя бы одним участником
+func (m *MembersListModel) canManageAny() bool {
+	return len(m.currentSelf().Role.RolePromote) > 0
+}
+
 func (m *MembersListModel) View() string {
 	var sb strings.Builder
 
@@ -75,12 +170,6 @@ func (m *MembersListModel) View() string {
 		return sb.String()
 	}
 
-	if m.errorMsg != "" {
-		sb.WriteString(ui.ErrorStyle.Render(m.errorMsg))
-		sb.WriteString("\n")
-		return sb.String()
-	}
-
 	if m.confirmState {
 		return m.renderConfirmView()
 	}
@@ -89,23 +178,36 @@ func (m *MembersListModel) View() string {
 		return m.renderActionView()
 	}
 
+	if m.errorMsg != "" {
+		sb.WriteString(ui.ErrorStyle.Render(m.errorMsg))
+		sb.WriteString("\n\n")
+	}
+	if m.successMsg != "" {
+		sb.WriteString(ui.SuccessStyle.Render(m.successMsg))
+		sb.WriteString("\n\n")
+	}
+
 	if len(m.members) == 0 {
 		sb.WriteString("Нет участников в гильдии.\n")
 	} else {
 		for i, member := range m.members {
-			line := fmt.Sprintf("%s (%s)", member.UserName, member.Role.Title)
+			line := member.UserName
+			if member.UserID == m.id {
+				line += " (вы)"
+			}
 			if i == m.selected {
-				sb.WriteString(ui.SelectedStyle.Render(line))
+				sb.WriteString(ui.SelectedStyle.Render("> " + line))
 			} else {
-				sb.WriteString(ui.NormalStyle.Render(line))
+				sb.WriteString(ui.NormalStyle.Render("  " + line))
 			}
+			sb.WriteString(" " + roleBadge(member.Role))
 			sb.WriteString("\n")
 		}
 	}
 
 	sb.WriteString("\n")
-	helpText := "↑/↓ - выбор, ←/→ - страницы, Esc - назад"
-	if m.userRole == "owner" || m.userRole == "officer" {
+	helpText := "↑/↓ - выбор, ←/→ - страницы, Ctrl+N - написать, Esc - назад"
+	if m.canManageAny() {
 		helpText += ", Enter - действия"
 	}
 	sb.WriteString(ui.HelpStyle.Render(helpText))
@@ -116,37 +218,23 @@ func (m *MembersListModel) View() string {
 func (m *MembersListModel) renderActionView() string {
 	var sb strings.Builder
 
-	sb.WriteString(ui.TitleStyle.Render("Выберите действие"))
+	target := m.members[m.selected]
+	sb.WriteString(ui.TitleStyle.Render(fmt.Sprintf("Действия: %s", target.UserName)))
+	sb.WriteString("\n\n")
+	sb.WriteString(ui.NormalStyle.Render("Текущая роль: ") + roleBadge(target.Role))
 	sb.WriteString("\n\n")
 
-	// Только для owner
-	if m.userRole == "owner" {
-		changeRoleText := "Изменить роль"
-		if m.actionType == 0 {
-			changeRoleText = ui.SelectedStyle.Render("> " + changeRoleText)
+	for i, action := range m.actions {
+		if i == m.actionSelected {
+			sb.WriteString(ui.SelectedStyle.Render("> " + action.label))
 		} else {
-			changeRoleText = ui.NormalStyle.Render("  " + changeRoleText)
+			sb.WriteString(ui.NormalStyle.Render("  " + action.label))
 		}
-		sb.WriteString(changeRoleText)
 		sb.WriteString("\n")
 	}
 
-	// Удаление доступно и owner и officer
-	deleteText := "Удалить из гильдии"
-	if (m.userRole == "owner" && m.actionType == 1) || m.userRole == "officer" {
-		deleteText = ui.SelectedStyle.Render("> " + deleteText)
-	} else {
-		deleteText = ui.NormalStyle.Render("  " + deleteText)
-	}
-	sb.WriteString(deleteText)
-	sb.WriteString("\n")
-
 	sb.WriteString("\n")
-	helpText := "Enter - подтвердить, Esc - назад"
-	if m.userRole == "owner" {
-		helpText = "Tab - переключение, " + helpText
-	}
-	sb.WriteString(ui.HelpStyle.Render(helpText))
+	sb.WriteString(ui.HelpStyle.Render("↑/↓ - выбор, Enter - подтвердить, Esc - назад"))
 
 	return sb.String()
 }
@@ -154,24 +242,16 @@ func (m *MembersListModel) renderActionView() string {
 func (m *MembersListModel) renderConfirmView() string {
 	var sb strings.Builder
 
-	selectedMember := m.members[m.selected]
+	target := m.members[m.selected]
+	action := m.actions[m.actionSelected]
 
-	if m.actionType == 0 {
-		// Подтверждение изменения роли
-		newRole := "офицера"
-		if selectedMember.Role.Title == "cabin_boi" {
-			newRole = "офицера"
-		} else {
-			newRole = "юнги"
-		}
-		sb.WriteString(ui.TitleStyle.Render("Подтверждение"))
-		sb.WriteString("\n\n")
-		sb.WriteString(fmt.Sprintf("Изменить роль %s на %s?\n", selectedMember.UserName, newRole))
+	sb.WriteString(ui.TitleStyle.Render("Подтверждение"))
+	sb.WriteString("\n\n")
+	if action.roleID != 0 {
+		sb.WriteString(fmt.Sprintf("Изменить роль %s: %s -> %s?\n",
+			target.UserName, roleTitle(target.Role), m.roleTitleByID(action.roleID)))
 	} else {
-		// Подтверждение удаления
-		sb.WriteString(ui.TitleStyle.Render("Подтверждение"))
-		sb.WriteString("\n\n")
-		sb.WriteString(fmt.Sprintf("Вы точно хотите удалить %s из гильдии?\n", selectedMember.UserName))
+		sb.WriteString(fmt.Sprintf("Вы точно хотите исключить %s из гильдии?\n", target.UserName))
 	}
 
 	sb.WriteString("\n")
@@ -183,6 +263,9 @@ func (m *MembersListModel) renderConfirmView() string {
 func (m *MembersListModel) handleNormalMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.errorMsg = ""
+		m.successMsg = ""
+
 		switch msg.Type {
 		case tea.KeyUp:
 			if m.selected > 0 {
@@ -190,7 +273,7 @@ func (m *MembersListModel) handleNormalMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else if m.currentPage > 1 {
 				m.currentPage--
 				m.selected = membersPerPage - 1
-				return m, m.loadMembers
+				return m, m.loadMembers()
 			}
 			return m, nil
 
@@ -200,7 +283,7 @@ func (m *MembersListModel) handleNormalMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else if m.currentPage < m.totalPages {
 				m.currentPage++
 				m.selected = 0
-				return m, m.loadMembers
+				return m, m.loadMembers()
 			}
 			return m, nil
 
@@ -208,7 +291,7 @@ func (m *MembersListModel) handleNormalMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.currentPage > 1 {
 				m.currentPage--
 				m.selected = 0
-				return m, m.loadMembers
+				return m, m.loadMembers()
 			}
 			return m, nil
 
@@ -216,16 +299,30 @@ func (m *MembersListModel) handleNormalMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.currentPage < m.totalPages {
 				m.currentPage++
 				m.selected = 0
-				return m, m.loadMembers
+				return m, m.loadMembers()
 			}
 			return m, nil
 
 		case tea.KeyEnter:
-			if len(m.members) > 0 && (m.userRole == "owner" || m.userRole == "officer") {
-				m.actionMode = true
-				m.actionType = 0
+			if len(m.members) == 0 {
+				return m, nil
 			}
-			return m.parent, nil
+			target := m.members[m.selected]
+			if err := checkManage(m.currentSelf(), target); err != nil {
+				m.errorMsg = err.Error()
+				return m, nil
+			}
+			m.actions = m.memberActions(target)
+			m.actionSelected = 0
+			m.actionMode = true
+			return m, nil
+
+		case tea.KeyCtrlN:
+			if len(m.members) == 0 || m.members[m.selected].UserID == m.id {
+				return m, nil
+			}
+			target := m.members[m.selected]
+			return m, func() tea.Msg { return OpenDirectMsg{UserID: target.UserID, Username: target.UserName} }
 
 		case tea.KeyEsc:
 			return m.parent, nil
@@ -238,15 +335,14 @@ func (m *MembersListModel) handleNormalMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if len(m.members) == 0 {
 			m.errorMsg = "Участники не найдены"
 		}
+		if m.selected >= len(m.members) {
+			m.selected = max(len(m.members)-1, 0)
+		}
 		for _, member := range m.members {
-			guildStorage.SetMember(member.UserName, member)
+			m.roles[member.Role.ID] = member.Role
+			m.Clients.GuildStore.SetMember(member)
 		}
 		return m, nil
-
-	case error:
-		m.loading = false
-		m.errorMsg = msg.Error()
-		return m, nil
 	}
 	return m, nil
 }
@@ -255,10 +351,12 @@ func (m *MembersListModel) handleActionMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.Type {
-		case tea.KeyTab:
-			if m.userRole == "owner" {
-				m.actionType = (m.actionType + 1) % 2 // Переключение между действиями
-			}
+		case tea.KeyUp:
+			m.actionSelected = (m.actionSelected - 1 + len(m.actions)) % len(m.actions)
+			return m, nil
+
+		case tea.KeyDown, tea.KeyTab:
+			m.actionSelected = (m.actionSelected + 1) % len(m.actions)
 			return m, nil
 
 		case tea.KeyEnter:
@@ -278,35 +376,55 @@ func (m *MembersListModel) handleConfirmState(msg tea.Msg) (tea.Model, tea.Cmd)
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyEnter:
-			selectedMember := m.members[m.selected]
-			if m.actionType == 0 {
-				var newRole string
-				if selectedMember.Role.Title == "cabin_boy" {
-					newRole = "officer"
-				} else {
-					newRole = "cabin_boy"
-				}
+			target := m.members[m.selected]
+			action := m.actions[m.actionSelected]
+
+			// права могли измениться, пока открыт экран подтверждения
+			self := m.currentSelf()
+			err := checkManage(self, target)
+			if err == nil && action.roleID != 0 && !self.Role.CanManage(action.roleID) {
+				err = fmt.Errorf("Роль %s не позволяет назначать роль %s", roleTitle(self.Role), m.roleTitleByID(action.roleID))
+			}
+			if err != nil {
+				m.confirmState = false
+				m.actionMode = false
+				m.errorMsg = err.Error()
+				return m, nil
+			}
 
+			// команда выполняется вне цикла Update: состояние модели читается до ее запуска
+			clients, guildTag, id := m.Clients, m.guildTag, m.id
+			if action.roleID != 0 {
+				roleID := action.roleID
+				entry := m.auditEntry(guilds.AuditRoleChanged, target)
+				entry.Details = m.roles[roleID].Title
+				if entry.Details == "" {
+					entry.Details = m.roleTitleByID(roleID)
+				}
 				return m, func() tea.Msg {
 					ctx := context.Background()
-					err := m.Clients.GuildsClient.EditMember(ctx, m.guildTag, m.id, selectedMember.UserID,
+					err := clients.GuildsClient.EditMember(ctx, guildTag, id, target.UserID,
 						guilds.EditMemberRequest{
-							RoleID: getRoleID(newRole),
+							RoleID: roleID,
 						})
 					if err != nil {
 						return err
 					}
-					return MemberRoleChangeMsg{Username: selectedMember.UserName}
+					clients.GuildStore.InvalidateMembers(guildTag)
+					recordAudit(clients, guildTag, entry)
+					return MemberRoleChangeMsg{Username: target.UserName}
 				}
-			} else {
-				return m, func() tea.Msg {
-					ctx := context.Background()
-					err := m.Clients.GuildsClient.DeleteMember(ctx, m.guildTag, m.id, selectedMember.UserID)
-					if err != nil {
-						return err
-					}
-					return MemberDeleteMsg{Username: selectedMember.UserName}
+			}
+			entry := m.auditEntry(guilds.AuditMemberKicked, target)
+			return m, func() tea.Msg {
+				ctx := context.Background()
+				err := clients.GuildsClient.DeleteMember(ctx, guildTag, id, target.UserID)
+				if err != nil {
+					return err
 				}
+				clients.GuildStore.InvalidateMembers(guildTag)
+				recordAudit(clients, guildTag, entry)
+				return MemberDeleteMsg{Username: target.UserName}
 			}
 
 		case tea.KeyEsc:
@@ -317,22 +435,27 @@ func (m *MembersListModel) handleConfirmState(msg tea.Msg) (tea.Model, tea.Cmd)
 	return m, nil
 }
 
-func getRoleID(roleTitle string) int {
-	switch roleTitle {
-	case "officer":
-		return 3
-	default: // "cabin_boy"
-		return 2
+// auditEntry - запись журнала о действии текущего пользователя над участником target
+func (m *MembersListModel) auditEntry(action guilds.AuditAction, target guilds.MemberResponse) guilds.AuditEntry {
+	return guilds.AuditEntry{
+		Action:     action,
+		ActorID:    m.id,
+		ActorName:  m.username,
+		TargetID:   target.UserID,
+		TargetName: target.UserName,
 	}
 }
 
-func (m *MembersListModel) loadMembers() tea.Msg {
+// loadMembers - загрузка текущей страницы участников, отметка загрузки ставится до запуска команды
+func (m *MembersListModel) loadMembers() tea.Cmd {
 	m.loading = true
-	ctx := context.Background()
+	clients, guildTag := m.Clients, m.guildTag
 	offset := (m.currentPage - 1) * membersPerPage
-	members, err := m.Clients.GuildsClient.GetGuildMembers(ctx, m.guildTag, offset, membersPerPage)
-	if err != nil {
-		return err
+	return func() tea.Msg {
+		members, err := clients.GuildsClient.GetGuildMembers(context.Background(), guildTag, offset, membersPerPage)
+		if err != nil {
+			return err
+		}
+		return members
 	}
-	return members
 }
@@ -2,6 +2,11 @@ package models
 
 import (
 	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/api/websocket"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/direct"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/room"
+	"lesta-start-battleship/cli/internal/notify"
+	guildStorage "lesta-start-battleship/cli/storage/guild"
 )
 
 type OAuthPollingResultMsg struct {
@@ -19,6 +24,21 @@ type AuthSuccessMsg struct {
 
 type LogoutMsg struct{}
 
+// ProfileSelectedMsg - выбран профиль аккаунта для переключения
+type ProfileSelectedMsg struct {
+	Name string
+}
+
+// ProfileErrorMsg - ошибка переключения профиля
+type ProfileErrorMsg struct {
+	Err error
+}
+
+// SessionRestoreFailedMsg - сохраненную сессию профиля не удалось восстановить
+type SessionRestoreFailedMsg struct {
+	Err error
+}
+
 type OpenChatMsg struct {
 	GuildID int
 }
@@ -65,8 +85,54 @@ type GuildEditMsg struct {
 
 type GuildDeletedMsg struct{}
 
-type DeclareWarMsg struct{}
+type DeclareWarMsg struct {
+	Target guilds.GuildResponse // гильдия, которой объявлена война
+}
 
 type WarRequestProcessedMsg struct {
 	Message string
 }
+
+// GuildStoreChangedMsg - изменились данные хранилища гильдий сессии
+type GuildStoreChangedMsg struct {
+	Change guildStorage.Change
+}
+
+// NotificationsMsg - получены новые уведомления о событиях гильдии
+type NotificationsMsg struct {
+	Items []notify.Notification
+}
+
+// ChatNotifyMsg - упоминание или приглашение из чата гильдии, уже добавленные в центр уведомлений
+type ChatNotifyMsg struct {
+	Items []notify.Notification
+}
+
+// CustomRoomMsg - создана своя игра, пустой RoomID - игрок вышел из комнаты
+type CustomRoomMsg struct {
+	RoomID string
+}
+
+// MatchRoomMsg - игрок в комнате игры: своей или найденной поиском. Пустой RoomID - игрок вышел из комнаты.
+// Через Client и PlayerID вкладка «Игра» панели чата пишет сопернику.
+type MatchRoomMsg struct {
+	RoomID   string
+	PlayerID string
+	Client   *websocket.WebsocketClient
+}
+
+// RoomChatMsg - сообщение соперника в чате комнаты игры
+type RoomChatMsg struct {
+	Message room.Message
+}
+
+// OpenDirectMsg - открыть личные сообщения: переписку с игроком или список переписок, если UserID 0
+type OpenDirectMsg struct {
+	UserID   int
+	Username string
+}
+
+// DirectReceivedMsg - новое личное сообщение вне открытой переписки
+type DirectReceivedMsg struct {
+	Message direct.Message
+}
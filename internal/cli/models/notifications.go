@@ -0,0 +1,132 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+	notifyPackets "lesta-start-battleship/cli/internal/api/websocket/packets/notify"
+	"lesta-start-battleship/cli/internal/cli/ui"
+	"lesta-start-battleship/cli/internal/clientdeps"
+	"lesta-start-battleship/cli/internal/notify"
+)
+
+// notificationsPerPage - уведомлений на экране центра уведомлений
+const notificationsPerPage = 10
+
+// notifyChannelTitles - подписи источника уведомлений
+var notifyChannelTitles = map[notify.Channel]string{
+	notify.ChannelNone:      "не подключены",
+	notify.ChannelWebsocket: "websocket",
+	notify.ChannelPolling:   "опрос сервера",
+}
+
+// NotificationText - текст уведомления для центра уведомлений и всплывающих сообщений
+func NotificationText(n notify.Notification) string {
+	switch n.Kind {
+	case notifyPackets.KindJoinRequest:
+		return fmt.Sprintf("%s подал заявку в гильдию [%s]", n.UserName, n.GuildTag)
+	case notifyPackets.KindWarDeclared:
+		enemy := fmt.Sprintf("#%d", n.EnemyID)
+		if n.EnemyTag != "" {
+			enemy = fmt.Sprintf("[%s]", n.EnemyTag)
+		}
+		return fmt.Sprintf("Гильдия %s объявила войну гильдии [%s]", enemy, n.GuildTag)
+	case notifyPackets.KindRoleChanged:
+		return fmt.Sprintf("Ваша роль в гильдии [%s]: %s", n.GuildTag, roleTitle(guilds.Role{Title: n.RoleTitle}))
+	case notifyPackets.KindKicked:
+		return fmt.Sprintf("Вы исключены из гильдии [%s]", n.GuildTag)
+	case notifyPackets.KindMention:
+		if n.GuildID == 0 {
+			return fmt.Sprintf("%s упомянул вас в лобби", n.UserName)
+		}
+		return fmt.Sprintf("%s упомянул вас в чате гильдии", n.UserName)
+	case notifyPackets.KindChatInvite:
+		return fmt.Sprintf("%s приглашает вас в свою игру, комната %s", n.UserName, n.RoomID)
+	default:
+		return fmt.Sprintf("Событие гильдии [%s]: %s", n.GuildTag, n.Kind)
+	}
+}
+
+// NotificationsModel - центр уведомлений о событиях гильдии
+type NotificationsModel struct {
+	parent  tea.Model
+	items   []notify.Notification
+	offset  int
+	Clients *clientdeps.Client
+}
+
+// NewNotificationsModel - центр уведомлений. Открытие отмечает все уведомления прочитанными.
+func NewNotificationsModel(parent tea.Model, clients *clientdeps.Client) *NotificationsModel {
+	m := &NotificationsModel{
+		parent:  parent,
+		items:   clients.Notifications.List(),
+		Clients: clients,
+	}
+	clients.Notifications.MarkRead()
+	return m
+}
+
+func (m *NotificationsModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *NotificationsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyUp:
+			if m.offset > 0 {
+				m.offset--
+			}
+			return m, nil
+
+		case tea.KeyDown:
+			if m.offset < len(m.items)-notificationsPerPage {
+				m.offset++
+			}
+			return m, nil
+
+		case tea.KeyEsc:
+			return m.parent, nil
+		}
+
+	case NotificationsMsg:
+		// новые уведомления сразу видны и прочитаны
+		m.items = m.Clients.Notifications.List()
+		m.Clients.Notifications.MarkRead()
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m *NotificationsModel) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(ui.TitleStyle.Render("Уведомления"))
+	sb.WriteString("\n")
+	sb.WriteString(ui.HelpStyle.Render("Получение: " + notifyChannelTitles[m.Clients.Notifications.Channel()]))
+	sb.WriteString("\n\n")
+
+	if len(m.items) == 0 {
+		sb.WriteString(ui.NormalStyle.Render("Уведомлений нет"))
+		sb.WriteString("\n")
+	}
+	for _, n := range m.items[m.offset:min(m.offset+notificationsPerPage, len(m.items))] {
+		line := fmt.Sprintf("%s  %s", n.CreatedAt.Local().Format("15:04"), NotificationText(n))
+		if n.Read {
+			sb.WriteString(ui.NormalStyle.Render("  " + line))
+		} else {
+			sb.WriteString(ui.SelectedStyle.Render("• " + line))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(ui.HelpStyle.Render("↑/↓ - прокрутка, Esc - назад"))
+
+	return sb.String()
+}
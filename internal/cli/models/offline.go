@@ -0,0 +1,61 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"lesta-start-battleship/cli/internal/api/cache"
+	"lesta-start-battleship/cli/internal/cli/ui"
+)
+
+// loadedMsg - результат загрузки экрана с отметкой о данных из кэша
+type loadedMsg struct {
+	Msg      tea.Msg
+	Offline  bool
+	StoredAt time.Time
+}
+
+// loadCached - загрузка данных экрана с отслеживанием ответов из кэша.
+// Если сервер недоступен, экран получает сохраненные данные с отметкой офлайн.
+func loadCached(load func(ctx context.Context) tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		ctx, status := cache.Track(context.Background())
+		msg := load(ctx)
+		storedAt, offline := status.Offline()
+		return loadedMsg{Msg: msg, Offline: offline, StoredAt: storedAt}
+	}
+}
+
+// isOffline - запросы контекста получили сохраненные данные вместо ответа сервера
+func isOffline(ctx context.Context) bool {
+	status, ok := cache.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	_, offline := status.Offline()
+	return offline
+}
+
+// offlineState - экран показывает сохраненные данные вместо ответа сервера
+type offlineState struct {
+	offline  bool
+	storedAt time.Time
+}
+
+// apply - запоминание происхождения данных, возвращает результат загрузки
+func (s *offlineState) apply(msg loadedMsg) tea.Msg {
+	s.offline = msg.Offline
+	s.storedAt = msg.StoredAt
+	return msg.Msg
+}
+
+// View - отметка офлайн с временем сохранения данных
+func (s offlineState) View() string {
+	if !s.offline {
+		return ""
+	}
+	return ui.WarningStyle.Render(fmt.Sprintf("офлайн - данные от %s", s.storedAt.Format("02.01.2006 15:04"))) + "\n\n"
+}
@@ -22,6 +22,7 @@ type ScoreboardModel struct {
 	activeTab    int // 0-моя, 1-игроки, 2-гильдия
 	playersTab   int // 0-золото, 1-опыт, 2-рейтинг, 3-сундуки
 	guildsTab    int // 0-игроки, 1-победы
+	selected     int // выбранный игрок на странице вкладки игроков
 	myStats      *scoreboard.UserStat
 	playersStats *scoreboard.UserListResponse
 	guildStats   *scoreboard.GuildListResponse
@@ -29,6 +30,7 @@ type ScoreboardModel struct {
 	currentPage  int
 	totalPages   int
 	tableWidth   int
+	offline      offlineState
 	Clients      *clientdeps.Client
 }
 
@@ -57,15 +59,22 @@ func (m *ScoreboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.tableWidth = msg.Width - 10
 		return m, nil
 
+	case loadedMsg:
+		return m.Update(m.offline.apply(msg))
+
 	case *scoreboard.UserStat:
+		m.err = nil
 		m.myStats = msg
 		return m, nil
 
 	case *scoreboard.UserListResponse:
+		m.err = nil
 		m.playersStats = msg
+		m.selected = min(m.selected, max(len(msg.Items)-1, 0))
 		return m, nil
 
 	case *scoreboard.GuildListResponse:
+		m.err = nil
 		m.guildStats = msg
 		return m, nil
 
@@ -81,6 +90,7 @@ func (m *ScoreboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.activeTab--
 			m.currentPage = 1
+			m.selected = 0
 			return m, m.loadStats
 
 		case tea.KeyRight:
@@ -89,11 +99,16 @@ func (m *ScoreboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.activeTab++
 			m.currentPage = 1
+			m.selected = 0
 			return m, m.loadStats
 
 		case tea.KeyDown:
-			if m.activeTab == 1 && m.playersStats != nil && m.currentPage < m.playersStats.PageAmount {
+			if m.activeTab == 1 && m.playersStats != nil && m.selected < len(m.playersStats.Items)-1 {
+				m.selected++
+				return m, nil
+			} else if m.activeTab == 1 && m.playersStats != nil && m.currentPage < m.playersStats.PageAmount {
 				m.currentPage++
+				m.selected = 0
 				return m, m.loadStats
 			} else if m.activeTab == 2 && m.guildStats != nil && m.currentPage < m.guildStats.PageAmount {
 				m.currentPage++
@@ -102,16 +117,32 @@ func (m *ScoreboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case tea.KeyUp:
+			if m.activeTab == 1 && m.selected > 0 {
+				m.selected--
+				return m, nil
+			}
 			if m.currentPage > 1 {
 				m.currentPage--
+				m.selected = pageSize - 1
 				return m, m.loadStats
 			}
 			return m, nil
 
+		case tea.KeyCtrlN:
+			if m.activeTab != 1 || m.playersStats == nil || m.selected >= len(m.playersStats.Items) {
+				return m, nil
+			}
+			target := m.playersStats.Items[m.selected]
+			if target.ID == m.id {
+				return m, nil
+			}
+			return m, func() tea.Msg { return OpenDirectMsg{UserID: target.ID, Username: target.Name} }
+
 		case tea.KeyTab:
 			if m.activeTab == 1 {
 				m.playersTab = (m.playersTab + 1) % 4
 				m.currentPage = 1
+				m.selected = 0
 				return m, m.loadStats
 			} else if m.activeTab == 2 {
 				m.guildsTab = (m.guildsTab + 1) % 2
@@ -149,6 +180,7 @@ func (m *ScoreboardModel) View() string {
 		sb.WriteString(ui.ErrorStyle.Render("Ошибка: " + m.err.Error()))
 		return sb.String()
 	}
+	sb.WriteString(m.offline.View())
 
 	switch m.activeTab {
 	case 0:
@@ -216,7 +248,9 @@ func (m *ScoreboardModel) View() string {
 	helpText := "←/→ - вкладки"
 	if m.activeTab > 0 {
 		helpText += ", Tab - подвкладки"
-		if m.activeTab == 1 || m.activeTab == 2 {
+		if m.activeTab == 1 {
+			helpText += ", ↑/↓ - выбор игрока, Ctrl+N - написать"
+		} else if m.activeTab == 2 {
 			helpText += ", ↑/↓ - страницы"
 		}
 	}
@@ -278,6 +312,15 @@ func (m *ScoreboardModel) renderPlayersTable() string {
 		}
 	}
 
+	// выбранный игрок отмечается в колонке имени
+	for i, row := range rows {
+		if i == m.selected {
+			row[1] = "> " + row[1]
+		} else {
+			row[1] = "  " + row[1]
+		}
+	}
+
 	table := ui.NewTable(m.tableWidth, widths)
 	table.AddHeader(headers)
 	for _, row := range rows {
@@ -330,8 +373,10 @@ func (m *ScoreboardModel) renderGuildsTable() string {
 }
 
 func (m *ScoreboardModel) loadStats() tea.Msg {
-	ctx := context.Background()
+	return loadCached(m.fetchStats)()
+}
 
+func (m *ScoreboardModel) fetchStats(ctx context.Context) tea.Msg {
 	switch m.activeTab {
 	case 0:
 		// Получение статистики текущего пользователя
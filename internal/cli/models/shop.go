@@ -35,6 +35,7 @@ type ShopModel struct {
 	selected int
 	category int // 0-предметы, 1-акции, 2-сундуки
 	err      error
+	offline  offlineState
 	Clients  *clientdeps.Client
 }
 
@@ -57,7 +58,11 @@ func (m *ShopModel) Init() tea.Cmd {
 
 func (m *ShopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case loadedMsg:
+		return m.Update(m.offline.apply(msg))
+
 	case ShopResponse:
+		m.err = nil
 		m.items = msg
 		return m, nil
 
@@ -157,6 +162,7 @@ func (m *ShopModel) View() string {
 		sb.WriteString(ui.ErrorStyle.Render("Ошибка: " + m.err.Error()))
 		return sb.String()
 	}
+	sb.WriteString(m.offline.View())
 
 	if len(m.items.Items) == 0 {
 		sb.WriteString(ui.NormalStyle.Render("Товары отсутствуют"))
@@ -187,7 +193,10 @@ func (m *ShopModel) View() string {
 }
 
 func (m *ShopModel) loadItems() tea.Msg {
-	ctx := context.Background()
+	return loadCached(m.fetchItems)()
+}
+
+func (m *ShopModel) fetchItems(ctx context.Context) tea.Msg {
 	var items []ShopItem
 	var err error
 
@@ -250,12 +259,15 @@ func (m *ShopModel) loadItems() tea.Msg {
 		}
 	}
 
-	// получение баланса
+	// получение баланса, без сети - баланс сессии
+	balance := m.gold
 	profile, err := m.Clients.AuthClient.GetProfile(ctx)
-	if err != nil {
+	switch {
+	case err == nil:
+		balance = profile.Currency.Gold
+	case !isOffline(ctx):
 		return err
 	}
-	balance := profile.Currency.Gold
 
 	return ShopResponse{
 		Balance: balance,
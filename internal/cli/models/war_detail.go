@@ -0,0 +1,326 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/cli/ui"
+	"lesta-start-battleship/cli/internal/clientdeps"
+)
+
+// warParticipantsShown - участников каждой стороны на странице войны
+const warParticipantsShown = 5
+
+// warParticipantsMsg - загружены участники сторон войны
+type warParticipantsMsg struct {
+	Initiator guilds.MemberPagination
+	Target    guilds.MemberPagination
+}
+
+// warTickMsg - обновление обратного отсчета страницы войны
+type warTickMsg struct {
+	detail *WarDetailModel
+}
+
+// WarDetailModel - страница войны: счет, участники, история и срок
+type WarDetailModel struct {
+	parent       tea.Model
+	id           int
+	guild        guilds.GuildResponse // гильдия пользователя
+	war          guilds.GuildWarItem
+	initiator    guilds.GuildResponse
+	target       guilds.GuildResponse
+	participants *warParticipantsMsg
+	sending      bool
+	errorMsg     string
+	now          func() time.Time
+	Clients      *clientdeps.Client
+}
+
+func NewWarDetailModel(parent tea.Model, id int, guild guilds.GuildResponse, war guilds.GuildWarItem,
+	initiator, target guilds.GuildResponse, clients *clientdeps.Client) *WarDetailModel {
+	return &WarDetailModel{
+		parent:    parent,
+		id:        id,
+		guild:     guild,
+		war:       war,
+		initiator: initiator,
+		target:    target,
+		now:       time.Now,
+		Clients:   clients,
+	}
+}
+
+func (m *WarDetailModel) Init() tea.Cmd {
+	return tea.Batch(m.loadParticipants, m.tick())
+}
+
+func (m *WarDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.sending {
+			return m, nil
+		}
+
+		switch msg.Type {
+		case tea.KeyEnter:
+			if !m.canConfirm() {
+				return m, nil
+			}
+			m.sending = true
+			m.errorMsg = ""
+			return m, m.confirmWar
+
+		case tea.KeyBackspace:
+			if !m.canCancel() {
+				return m, nil
+			}
+			m.sending = true
+			m.errorMsg = ""
+			return m, m.cancelWar
+
+		case tea.KeyEsc:
+			return m.parent, nil
+		}
+
+	case warTickMsg:
+		if msg.detail != m {
+			return m, nil
+		}
+		return m, m.tick()
+
+	case warParticipantsMsg:
+		m.participants = &msg
+		return m, nil
+
+	case WarRequestProcessedMsg:
+		return m.parent.Update(msg)
+
+	case error:
+		m.sending = false
+		m.errorMsg = msg.Error()
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// isOwner - пользователь владеет своей гильдией
+func (m *WarDetailModel) isOwner() bool {
+	return m.guild.OwnerID == m.id
+}
+
+// canConfirm - принять войну может владелец гильдии, которой ее объявили
+func (m *WarDetailModel) canConfirm() bool {
+	return m.isOwner() && m.war.Status == guilds.WarStatusPending && m.war.TargetGuildID == m.guild.ID
+}
+
+// canCancel - отказаться можно от ожидающей или идущей войны
+func (m *WarDetailModel) canCancel() bool {
+	return m.isOwner() && (m.war.Status == guilds.WarStatusPending || m.war.Status == guilds.WarStatusActive)
+}
+
+// tick - обратный отсчет идет только у ожидающих и идущих войн
+func (m *WarDetailModel) tick() tea.Cmd {
+	if m.war.Status != guilds.WarStatusPending && m.war.Status != guilds.WarStatusActive {
+		return nil
+	}
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return warTickMsg{detail: m}
+	})
+}
+
+func (m *WarDetailModel) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(ui.TitleStyle.Render(fmt.Sprintf("Война #%d", m.war.ID)))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(ui.NormalStyle.Render(fmt.Sprintf("%s → %s", sideLabel(m.initiator, m.war.InitiatorGuildID),
+		sideLabel(m.target, m.war.TargetGuildID))))
+	sb.WriteString("\n")
+	sb.WriteString(ui.NormalStyle.Render("Статус: " + warStatusTitles[m.war.Status]))
+	sb.WriteString("\n")
+	sb.WriteString(ui.NormalStyle.Render(fmt.Sprintf("Счет: %d:%d", m.war.InitiatorScore, m.war.TargetScore)))
+	if m.war.Status == guilds.WarStatusFinished {
+		sb.WriteString(" " + m.result())
+	}
+	sb.WriteString("\n\n")
+
+	sb.WriteString(ui.NormalStyle.Render("Участники:"))
+	sb.WriteString("\n")
+	if m.participants == nil {
+		sb.WriteString(ui.HelpStyle.Render("  загрузка..."))
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString(ui.NormalStyle.Render("  " + participantsLine(sideLabel(m.initiator, m.war.InitiatorGuildID), m.participants.Initiator)))
+		sb.WriteString("\n")
+		sb.WriteString(ui.NormalStyle.Render("  " + participantsLine(sideLabel(m.target, m.war.TargetGuildID), m.participants.Target)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(ui.NormalStyle.Render("История:"))
+	sb.WriteString("\n")
+	sb.WriteString(ui.NormalStyle.Render("  Объявлена: " + formatWarTime(m.war.CreatedAt)))
+	sb.WriteString("\n")
+	if event := warStatusEvent(m.war.Status); event != "" {
+		sb.WriteString(ui.NormalStyle.Render(fmt.Sprintf("  %s: %s", event, formatWarTime(m.war.UpdatedAt))))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(m.deadline())
+	sb.WriteString("\n")
+
+	if m.errorMsg != "" {
+		sb.WriteString("\n")
+		sb.WriteString(ui.ErrorStyle.Render(m.errorMsg))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	help := []string{}
+	if m.canConfirm() {
+		help = append(help, "Enter - принять войну", "Backspace - отклонить")
+	} else if m.canCancel() {
+		help = append(help, "Backspace - отменить войну")
+	}
+	help = append(help, "Esc - назад")
+	sb.WriteString(ui.HelpStyle.Render(strings.Join(help, ", ")))
+
+	return sb.String()
+}
+
+// result - итог завершенной войны
+func (m *WarDetailModel) result() string {
+	switch {
+	case m.war.InitiatorScore == m.war.TargetScore:
+		return ui.WarningStyle.Render("ничья")
+	case (m.war.InitiatorScore > m.war.TargetScore) == (m.war.InitiatorGuildID == m.guild.ID):
+		return ui.SuccessStyle.Render("победа")
+	default:
+		return ui.ErrorStyle.Render("поражение")
+	}
+}
+
+// deadline - срок подтверждения или окончания войны с обратным отсчетом
+func (m *WarDetailModel) deadline() string {
+	switch m.war.Status {
+	case guilds.WarStatusPending, guilds.WarStatusActive:
+		title := "Срок подтверждения"
+		if m.war.Status == guilds.WarStatusActive {
+			title = "Окончание"
+		}
+		left := m.war.ExpiresAt.Sub(m.now())
+		if left <= 0 {
+			return ui.WarningStyle.Render(fmt.Sprintf("  %s: %s (срок истек)", title, formatWarTime(m.war.ExpiresAt)))
+		}
+		return ui.NormalStyle.Render(fmt.Sprintf("  %s: %s (осталось %s)", title, formatWarTime(m.war.ExpiresAt), formatCountdown(left)))
+	default:
+		return ui.NormalStyle.Render("  Срок: " + formatWarTime(m.war.ExpiresAt))
+	}
+}
+
+// warStatusEvent - событие смены статуса войны для истории
+func warStatusEvent(status guilds.WarStatus) string {
+	switch status {
+	case guilds.WarStatusActive:
+		return "Началась"
+	case guilds.WarStatusFinished:
+		return "Завершена"
+	case guilds.WarStatusDeclined:
+		return "Отклонена"
+	case guilds.WarStatusCanceled:
+		return "Отменена"
+	case guilds.WarStatusExpired:
+		return "Истекла"
+	default:
+		return ""
+	}
+}
+
+// participantsLine - сторона войны и имена первых участников
+func participantsLine(side string, members guilds.MemberPagination) string {
+	names := make([]string, 0, warParticipantsShown)
+	for _, member := range members.Items {
+		if len(names) == warParticipantsShown {
+			break
+		}
+		names = append(names, member.UserName)
+	}
+	line := fmt.Sprintf("%s (%d): %s", side, members.TotalItems, strings.Join(names, ", "))
+	if rest := members.TotalItems - len(names); rest > 0 {
+		line += fmt.Sprintf(" и еще %d", rest)
+	}
+	return line
+}
+
+// formatWarTime - время события войны
+func formatWarTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("02.01.2006 15:04")
+}
+
+// formatCountdown - оставшееся время: дни и часы:минуты:секунды
+func formatCountdown(d time.Duration) string {
+	d = d.Round(time.Second)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	clock := fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+	if days > 0 {
+		return fmt.Sprintf("%dд %s", days, clock)
+	}
+	return clock
+}
+
+func (m *WarDetailModel) loadParticipants() tea.Msg {
+	ctx := context.Background()
+	var msg warParticipantsMsg
+	for _, side := range []struct {
+		guild guilds.GuildResponse
+		dst   *guilds.MemberPagination
+	}{{m.initiator, &msg.Initiator}, {m.target, &msg.Target}} {
+		if side.guild.Tag == "" {
+			continue
+		}
+		members, err := m.Clients.GuildsClient.GetGuildMembers(ctx, side.guild.Tag, 0, warParticipantsShown)
+		if err != nil {
+			return fmt.Errorf("Ошибка загрузки участников [%s]: %w", side.guild.Tag, err)
+		}
+		*side.dst = *members
+	}
+	return msg
+}
+
+func (m *WarDetailModel) confirmWar() tea.Msg {
+	ctx := context.Background()
+	if _, err := m.Clients.GuildsClient.ConfirmWar(ctx, m.war.ID, m.id); err != nil {
+		return fmt.Errorf("Ошибка подтверждения войны: %w", err)
+	}
+	return WarRequestProcessedMsg{
+		Message: fmt.Sprintf("Война с %s подтверждена", sideLabel(m.initiator, m.war.InitiatorGuildID)),
+	}
+}
+
+func (m *WarDetailModel) cancelWar() tea.Msg {
+	ctx := context.Background()
+	if _, err := m.Clients.GuildsClient.CancelWar(ctx, m.war.ID, m.id); err != nil {
+		return fmt.Errorf("Ошибка отмены войны: %w", err)
+	}
+
+	enemy := sideLabel(m.target, m.war.TargetGuildID)
+	action := "отменена"
+	if m.war.TargetGuildID == m.guild.ID {
+		enemy = sideLabel(m.initiator, m.war.InitiatorGuildID)
+		if m.war.Status == guilds.WarStatusPending {
+			action = "отклонена"
+		}
+	}
+	return WarRequestProcessedMsg{Message: fmt.Sprintf("Война с %s %s", enemy, action)}
+}
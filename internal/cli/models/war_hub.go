@@ -0,0 +1,280 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/cli/ui"
+	"lesta-start-battleship/cli/internal/clientdeps"
+)
+
+const warsPerPage = 5
+
+// warStatuses - вкладки списка войн
+var warStatuses = []guilds.WarStatus{
+	guilds.WarStatusPending,
+	guilds.WarStatusActive,
+	guilds.WarStatusFinished,
+	guilds.WarStatusDeclined,
+	guilds.WarStatusCanceled,
+	guilds.WarStatusExpired,
+}
+
+// warStatusTitles - названия статусов войны
+var warStatusTitles = map[guilds.WarStatus]string{
+	guilds.WarStatusPending:  "Ожидают",
+	guilds.WarStatusActive:   "Идут",
+	guilds.WarStatusFinished: "Завершены",
+	guilds.WarStatusDeclined: "Отклонены",
+	guilds.WarStatusCanceled: "Отменены",
+	guilds.WarStatusExpired:  "Истекли",
+}
+
+// warListMsg - загружена страница войн со сторонами
+type warListMsg struct {
+	Status     guilds.WarStatus
+	Page       int
+	Wars       []guilds.GuildWarItem
+	TotalPages int
+	Guilds     map[int]guilds.GuildResponse // стороны войн по ID
+}
+
+// WarHubModel - войны гильдии по статусам
+type WarHubModel struct {
+	parent      tea.Model
+	id          int
+	username    string
+	guild       guilds.GuildResponse
+	tab         int // индекс в warStatuses
+	wars        []guilds.GuildWarItem
+	sides       map[int]guilds.GuildResponse
+	currentPage int
+	totalPages  int
+	selected    int
+	loading     bool
+	errorMsg    string
+	successMsg  string
+	Clients     *clientdeps.Client
+}
+
+func NewWarHubModel(parent tea.Model, id int, username string, guild guilds.GuildResponse,
+	clients *clientdeps.Client) *WarHubModel {
+	return &WarHubModel{
+		parent:      parent,
+		id:          id,
+		username:    username,
+		guild:       guild,
+		sides:       make(map[int]guilds.GuildResponse),
+		currentPage: 1,
+		totalPages:  1,
+		loading:     true,
+		Clients:     clients,
+	}
+}
+
+func (m *WarHubModel) Init() tea.Cmd {
+	return m.reload()
+}
+
+func (m *WarHubModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyUp:
+			if m.selected > 0 {
+				m.selected--
+			}
+			return m, nil
+
+		case tea.KeyDown:
+			if m.selected < len(m.wars)-1 {
+				m.selected++
+			}
+			return m, nil
+
+		case tea.KeyLeft:
+			if m.currentPage > 1 {
+				m.currentPage--
+				return m, m.reload()
+			}
+			return m, nil
+
+		case tea.KeyRight:
+			if m.currentPage < m.totalPages {
+				m.currentPage++
+				return m, m.reload()
+			}
+			return m, nil
+
+		case tea.KeyTab:
+			m.tab = (m.tab + 1) % len(warStatuses)
+			m.currentPage = 1
+			return m, m.reload()
+
+		case tea.KeyShiftTab:
+			m.tab = (m.tab + len(warStatuses) - 1) % len(warStatuses)
+			m.currentPage = 1
+			return m, m.reload()
+
+		case tea.KeyEnter:
+			if len(m.wars) == 0 {
+				return m, nil
+			}
+			war := m.wars[m.selected]
+			detail := NewWarDetailModel(m, m.id, m.guild, war, m.sides[war.InitiatorGuildID], m.sides[war.TargetGuildID], m.Clients)
+			return detail, detail.Init()
+
+		case tea.KeyEsc:
+			return m.parent, nil
+		}
+
+	case warListMsg:
+		// ответ для уже закрытой вкладки или страницы
+		if msg.Status != warStatuses[m.tab] || msg.Page != m.currentPage {
+			return m, nil
+		}
+		m.loading = false
+		m.errorMsg = ""
+		m.wars = msg.Wars
+		m.totalPages = max(msg.TotalPages, 1)
+		m.selected = min(m.selected, max(len(m.wars)-1, 0))
+		for id, guild := range msg.Guilds {
+			m.sides[id] = guild
+		}
+		return m, nil
+
+	case WarRequestProcessedMsg:
+		m.successMsg = msg.Message
+		return m, m.reload()
+
+	case error:
+		m.loading = false
+		m.errorMsg = msg.Error()
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// reload - загрузка текущей вкладки и страницы
+func (m *WarHubModel) reload() tea.Cmd {
+	m.loading = true
+	m.selected = 0
+	status, page := warStatuses[m.tab], m.currentPage
+	return func() tea.Msg { return m.loadWars(status, page) }
+}
+
+func (m *WarHubModel) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(ui.TitleStyle.Render(fmt.Sprintf("Войны гильдии %s [%s]", m.guild.Title, m.guild.Tag)))
+	sb.WriteString("\n\n")
+
+	tabs := make([]string, len(warStatuses))
+	for i, status := range warStatuses {
+		if i == m.tab {
+			tabs[i] = ui.SelectedStyle.Render("[" + warStatusTitles[status] + "]")
+		} else {
+			tabs[i] = ui.NormalStyle.Render(" " + warStatusTitles[status] + " ")
+		}
+	}
+	sb.WriteString(strings.Join(tabs, " "))
+	sb.WriteString("\n")
+	sb.WriteString(ui.NormalStyle.Render(fmt.Sprintf("Страница %d/%d", m.currentPage, m.totalPages)))
+	sb.WriteString("\n\n")
+
+	if m.errorMsg != "" {
+		sb.WriteString(ui.ErrorStyle.Render(m.errorMsg))
+		sb.WriteString("\n\n")
+	}
+	if m.successMsg != "" {
+		sb.WriteString(ui.SuccessStyle.Render(m.successMsg))
+		sb.WriteString("\n\n")
+	}
+
+	switch {
+	case m.loading:
+		sb.WriteString(ui.NormalStyle.Render("Загрузка списка войн..."))
+		sb.WriteString("\n")
+	case len(m.wars) == 0:
+		sb.WriteString(ui.NormalStyle.Render("Войн с этим статусом нет"))
+		sb.WriteString("\n")
+	default:
+		for i, war := range m.wars {
+			line := fmt.Sprintf("%s → %s", sideLabel(m.sides[war.InitiatorGuildID], war.InitiatorGuildID),
+				sideLabel(m.sides[war.TargetGuildID], war.TargetGuildID))
+			if war.Status != guilds.WarStatusPending {
+				line += fmt.Sprintf("  %d:%d", war.InitiatorScore, war.TargetScore)
+			}
+			if i == m.selected {
+				sb.WriteString(ui.SelectedStyle.Render("> " + line))
+			} else {
+				sb.WriteString(ui.NormalStyle.Render("  " + line))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(ui.HelpStyle.Render("Tab/Shift+Tab - статус, ↑/↓ - выбор, ←/→ - страницы, Enter - подробнее, Esc - назад"))
+
+	return sb.String()
+}
+
+// sideLabel - тег и название стороны войны, ID если гильдия не найдена
+func sideLabel(guild guilds.GuildResponse, id int) string {
+	if guild.ID != id || guild.Tag == "" {
+		return fmt.Sprintf("гильдия #%d", id)
+	}
+	return fmt.Sprintf("[%s] %s", guild.Tag, guild.Title)
+}
+
+// loadWars - страница page войн со статусом status
+func (m *WarHubModel) loadWars(status guilds.WarStatus, page int) tea.Msg {
+	ctx := context.Background()
+	wars, err := m.Clients.GuildsClient.GetGuildWarList(ctx, m.id, m.guild.ID, nil, nil, &status, page, warsPerPage)
+	if err != nil {
+		return fmt.Errorf("Ошибка загрузки войн: %w", err)
+	}
+
+	ids := make([]int, 0, 2*len(wars.Results))
+	for _, war := range wars.Results {
+		ids = append(ids, war.InitiatorGuildID, war.TargetGuildID)
+	}
+	sides, err := resolveGuilds(ctx, m.Clients, ids)
+	if err != nil {
+		return fmt.Errorf("Ошибка загрузки гильдий: %w", err)
+	}
+
+	return warListMsg{Status: status, Page: page, Wars: wars.Results, TotalPages: wars.TotalPages, Guilds: sides}
+}
+
+// resolveGuilds - гильдии по ID: из хранилища, недостающие - из списка всех гильдий сервера
+func resolveGuilds(ctx context.Context, clients *clientdeps.Client, ids []int) (map[int]guilds.GuildResponse, error) {
+	found := make(map[int]guilds.GuildResponse, len(ids))
+	missing := false
+	for _, id := range ids {
+		if guild, ok := clients.GuildStore.GuildByID(id); ok {
+			found[id] = guild
+		} else {
+			missing = true
+		}
+	}
+	if !missing {
+		return found, nil
+	}
+
+	all, err := clients.GuildsClient.GetAllGuilds(ctx, guildFetchSize)
+	if err != nil {
+		return nil, err
+	}
+	for _, guild := range all {
+		clients.GuildStore.SetGuild(guild)
+		found[guild.ID] = guild
+	}
+	return found, nil
+}
@@ -0,0 +1,233 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/cli/ui"
+	"lesta-start-battleship/cli/internal/clientdeps"
+)
+
+// WarTargetModel - выбор гильдии для объявления войны с поиском
+type WarTargetModel struct {
+	parent     tea.Model
+	id         int
+	username   string
+	guild      guilds.GuildResponse // гильдия, которая объявляет войну
+	all        []guilds.GuildResponse
+	matches    []guilds.GuildResponse
+	query      string
+	selected   int
+	confirming bool // выбранная гильдия ждет подтверждения
+	loading    bool
+	sending    bool
+	errorMsg   string
+	offline    offlineState
+	Clients    *clientdeps.Client
+}
+
+func NewWarTargetModel(parent tea.Model, id int, username string, guild guilds.GuildResponse,
+	clients *clientdeps.Client) *WarTargetModel {
+	return &WarTargetModel{
+		parent:   parent,
+		id:       id,
+		username: username,
+		guild:    guild,
+		loading:  true,
+		Clients:  clients,
+	}
+}
+
+func (m *WarTargetModel) Init() tea.Cmd {
+	return loadCached(m.fetchGuilds)
+}
+
+func (m *WarTargetModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.sending {
+			return m, nil
+		}
+		if m.confirming {
+			return m.handleConfirm(msg)
+		}
+
+		switch msg.Type {
+		case tea.KeyUp:
+			if m.selected > 0 {
+				m.selected--
+			}
+			return m, nil
+
+		case tea.KeyDown:
+			if m.selected < len(m.matches)-1 {
+				m.selected++
+			}
+			return m, nil
+
+		case tea.KeyRunes:
+			m.query += string(msg.Runes)
+			m.applyQuery()
+			return m, nil
+
+		case tea.KeySpace:
+			m.query += " "
+			m.applyQuery()
+			return m, nil
+
+		case tea.KeyBackspace:
+			if runes := []rune(m.query); len(runes) > 0 {
+				m.query = string(runes[:len(runes)-1])
+				m.applyQuery()
+			}
+			return m, nil
+
+		case tea.KeyEnter:
+			if len(m.matches) > 0 {
+				m.confirming = true
+				m.errorMsg = ""
+			}
+			return m, nil
+
+		case tea.KeyEsc:
+			if m.query != "" {
+				m.query = ""
+				m.applyQuery()
+				return m, nil
+			}
+			return m.parent, nil
+		}
+
+	case loadedMsg:
+		return m.Update(m.offline.apply(msg))
+
+	case guildListMsg:
+		m.loading = false
+		m.errorMsg = ""
+		m.all = m.all[:0]
+		for _, guild := range msg.Guilds {
+			m.Clients.GuildStore.SetGuild(guild)
+			if guild.ID != m.guild.ID {
+				m.all = append(m.all, guild)
+			}
+		}
+		m.applyQuery()
+		return m, nil
+
+	case DeclareWarMsg:
+		return m.parent.Update(msg)
+
+	case error:
+		m.loading = false
+		m.sending = false
+		m.confirming = false
+		m.errorMsg = msg.Error()
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m *WarTargetModel) handleConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.sending = true
+		return m, m.declareWar(m.matches[m.selected])
+	case tea.KeyEsc:
+		m.confirming = false
+	}
+	return m, nil
+}
+
+// applyQuery - отбор гильдий по поиску
+func (m *WarTargetModel) applyQuery() {
+	query := strings.ToLower(strings.TrimSpace(m.query))
+	m.matches = m.matches[:0]
+	for _, guild := range m.all {
+		if matchGuild(guild, query) {
+			m.matches = append(m.matches, guild)
+		}
+	}
+	m.selected = 0
+}
+
+func (m *WarTargetModel) View() string {
+	var sb strings.Builder
+
+	sb.WriteString(ui.TitleStyle.Render("Объявить войну"))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(ui.NormalStyle.Render("Поиск: ") + ui.SelectedStyle.Render(m.query+"_"))
+	sb.WriteString("\n\n")
+
+	if m.errorMsg != "" {
+		sb.WriteString(ui.ErrorStyle.Render(m.errorMsg))
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString(m.offline.View())
+
+	switch {
+	case m.loading:
+		sb.WriteString(ui.NormalStyle.Render("Загрузка списка гильдий..."))
+		sb.WriteString("\n")
+	case len(m.matches) == 0:
+		sb.WriteString(ui.NormalStyle.Render("Гильдии не найдены"))
+		sb.WriteString("\n")
+	default:
+		// окно списка со страницей выбранной гильдии
+		start := m.selected / guildPerPage * guildPerPage
+		for i, guild := range m.matches[start:min(start+guildPerPage, len(m.matches))] {
+			line := fmt.Sprintf("[%s] %s", guild.Tag, guild.Title)
+			if !guild.IsActive {
+				line += " (Не участвует в войне)"
+			}
+			if start+i == m.selected {
+				sb.WriteString(ui.SelectedStyle.Render("> " + line))
+			} else {
+				sb.WriteString(ui.NormalStyle.Render("  " + line))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("\n")
+	if m.confirming {
+		target := m.matches[m.selected]
+		sb.WriteString(ui.WarningStyle.Render(fmt.Sprintf("Объявить войну гильдии [%s] %s? Enter - подтвердить, Esc - отмена",
+			target.Tag, target.Title)))
+		return sb.String()
+	}
+	sb.WriteString(ui.HelpStyle.Render("Ввод - поиск, ↑/↓ - выбор, Enter - объявить войну, Esc - назад"))
+
+	return sb.String()
+}
+
+func (m *WarTargetModel) fetchGuilds(ctx context.Context) tea.Msg {
+	all, err := m.Clients.GuildsClient.GetAllGuilds(ctx, guildFetchSize)
+	if err != nil {
+		return err
+	}
+	return guildListMsg{Guilds: all}
+}
+
+func (m *WarTargetModel) declareWar(target guilds.GuildResponse) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		if _, err := m.Clients.GuildsClient.DeclareWar(ctx, m.guild.ID, target.ID, m.id); err != nil {
+			return fmt.Errorf("Ошибка объявления войны: %w", err)
+		}
+		recordAudit(m.Clients, m.guild.Tag, guilds.AuditEntry{
+			Action:     guilds.AuditWarDeclared,
+			ActorID:    m.id,
+			ActorName:  m.username,
+			TargetID:   target.ID,
+			TargetName: target.Tag,
+			Details:    m.guild.Tag,
+		})
+		return DeclareWarMsg{Target: target}
+	}
+}
@@ -0,0 +1,146 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"lesta-start-battleship/cli/internal/cli/ui"
+)
+
+// Format - формат вывода результата команды
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+	FormatCSV   Format = "csv"
+)
+
+// Formats - поддерживаемые форматы
+var Formats = []Format{FormatTable, FormatJSON, FormatYAML, FormatCSV}
+
+// ParseFormat - разбор имени формата
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(name) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("неизвестный формат вывода %q (доступны: table, json, yaml, csv)", name)
+}
+
+// View - представление ответа API для вывода.
+// JSON и YAML выводят Value целиком с именами полей из json-тегов,
+// CSV и таблица - строки Rows с колонками Columns.
+type View struct {
+	Value   any
+	Columns []string
+	Rows    [][]string
+	Text    string // текст для табличного формата вместо таблицы, например результат действия
+}
+
+// Write - вывод представления в формате format
+func Write(w io.Writer, format Format, v View) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, v.Value)
+	case FormatYAML:
+		return writeYAML(w, v.Value)
+	case FormatCSV:
+		return writeCSV(w, v)
+	case FormatTable, "":
+		return writeTable(w, v)
+	default:
+		return fmt.Errorf("неизвестный формат вывода %q", format)
+	}
+}
+
+func writeJSON(w io.Writer, value any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(value); err != nil {
+		return fmt.Errorf("ошибка кодирования JSON: %w", err)
+	}
+	return nil
+}
+
+// writeYAML - YAML строится из JSON, чтобы имена и порядок полей совпадали с JSON
+func writeYAML(w io.Writer, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("ошибка кодирования YAML: %w", err)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("ошибка кодирования YAML: %w", err)
+	}
+	clearStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return fmt.Errorf("ошибка кодирования YAML: %w", err)
+	}
+	return enc.Close()
+}
+
+// clearStyle - сброс flow-стиля JSON, чтобы вывод был в блочном стиле YAML.
+// Строки, похожие на числа или bool, кодировщик сам заключит в кавычки по тегу узла.
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
+
+func writeCSV(w io.Writer, v View) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(v.Columns); err != nil {
+		return fmt.Errorf("ошибка записи CSV: %w", err)
+	}
+	if err := cw.WriteAll(v.Rows); err != nil {
+		return fmt.Errorf("ошибка записи CSV: %w", err)
+	}
+	return nil
+}
+
+func writeTable(w io.Writer, v View) error {
+	if v.Text != "" {
+		_, err := fmt.Fprintln(w, v.Text)
+		return err
+	}
+
+	widths := make([]int, len(v.Columns))
+	for i, c := range v.Columns {
+		widths[i] = utf8.RuneCountInString(c)
+	}
+	for _, row := range v.Rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	total := len(widths) - 1
+	for _, width := range widths {
+		total += width
+	}
+
+	table := ui.NewTable(total, widths)
+	table.AddHeader(v.Columns)
+	for _, row := range v.Rows {
+		table.AddRow(row)
+	}
+
+	_, err := io.WriteString(w, table.Render())
+	return err
+}
@@ -0,0 +1,144 @@
+package output_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"lesta-start-battleship/cli/internal/api/auth"
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/api/inventory"
+	"lesta-start-battleship/cli/internal/api/scoreboard"
+	"lesta-start-battleship/cli/internal/api/shop"
+	"lesta-start-battleship/cli/internal/cli/clitest"
+	"lesta-start-battleship/cli/internal/cli/output"
+)
+
+var (
+	created = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	expires = time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+)
+
+func ptr(n int) *int {
+	return &n
+}
+
+// views - представления с фиксированными данными, по одному на функцию views.go
+func views() map[string]output.View {
+	profile := &auth.ProfileResponse{ID: 1, Username: "admiral", Email: "admiral@example.com"}
+	profile.Currency.Gold = 1500
+
+	officer := guilds.Role{ID: 2, Title: "officer", RolePromote: []int{3}}
+	wolf := guilds.GuildResponse{
+		ID: 1, Title: "Морские волки", Description: "Гроза морей, \"без пощады\"", Tag: "WOLF", OwnerID: 1, IsActive: true,
+	}
+
+	return map[string]output.View{
+		"message": output.Message("Заявка отправлена"),
+		"session": output.Session("default", profile, &expires),
+		"guilds": output.Guilds(&guilds.GuildPagination{
+			Items: []guilds.GuildResponse{
+				wolf,
+				{ID: 2, Title: "Акулы", Tag: "SHRK", OwnerID: 4, IsActive: true, IsFull: true},
+			},
+			TotalItems: 2, TotalPages: 1,
+		}),
+		"guild": output.Guild(&wolf),
+		"members": output.Members(&guilds.MemberPagination{
+			Items: []guilds.MemberResponse{
+				{UserID: 2, UserName: "bosun", GuildID: 1, GuildTag: "WOLF", Role: officer},
+			},
+			TotalItems: 1, TotalPages: 1,
+		}),
+		"join_requests": output.JoinRequests(&guilds.RequestPagination{
+			Items:      []guilds.RequestResponse{{UserID: 6, UserName: "newbie", CreatedAt: "2025-06-01T12:00:00Z"}},
+			TotalItems: 1, TotalPages: 1,
+		}),
+		"wars": output.Wars(&guilds.GuildWarListResponse{
+			Page: 1, PageSize: 10, Total: 1, TotalPages: 1,
+			Results: []guilds.GuildWarItem{{
+				ID: 7, InitiatorGuildID: 1, TargetGuildID: 2, Status: guilds.WarStatusActive,
+				InitiatorScore: 3, TargetScore: 1, CreatedAt: created, UpdatedAt: created, ExpiresAt: expires,
+			}},
+		}),
+		"audit_log": output.AuditLog(&guilds.AuditPagination{
+			Items: []guilds.AuditEntry{{
+				ID: 5, Action: guilds.AuditRoleChanged, ActorID: 1, ActorName: "admiral",
+				TargetID: 2, TargetName: "bosun", Details: "officer", CreatedAt: created,
+			}},
+			TotalItems: 1, TotalPages: 1,
+		}),
+		"user_stats": output.UserStats(&scoreboard.UserListResponse{
+			Page: 1, PageAmount: 1,
+			Items: []scoreboard.UserStat{{
+				ID: 1, Name: "admiral", Gold: 1500, GoldRatingPos: 1, Experience: 900, ExpRatingPos: 2,
+				Rating: 1200, RatingRatingPos: 1, ChestsOpened: 4, ChestsOpenedRatingPos: 3,
+			}},
+		}),
+		"guild_stats": output.GuildStats(&scoreboard.GuildListResponse{
+			Page: 1, PageAmount: 1,
+			Items: []scoreboard.GuildStat{{
+				ID: 1, Name: "WOLF", GuildMembers: 3, GuildMembersRatingPos: 1, WarsVictories: 2, WarsVictoriesRatingPos: 1,
+			}},
+		}),
+		"products": output.Products([]shop.Product{
+			{ID: 1, Name: "Бомба", Description: "Поражает область 3x3", Currency: "gold", Cost: 100, DailyLimit: ptr(5)},
+			{ID: 2, Name: "Радар", Currency: "guild_rage", Cost: 50, PromotionID: ptr(1)},
+		}),
+		"chests": output.Chests([]shop.Chest{
+			{ID: 1, Name: "Малый сундук", Gold: 50, ItemProbability: 10, Experience: 20, Currency: "gold", Cost: 30},
+		}),
+		"promotions": output.Promotions([]shop.Promotion{
+			{ID: 1, Name: "Летняя распродажа", Description: "Скидки на радары", StartDate: created, EndDate: expires, IsActive: true},
+		}),
+		"inventory": output.Inventory(&inventory.UserInventoryResponse{
+			UserID: 1,
+			Items:  []inventory.InventoryItem{{ItemID: 1, Name: "Бомба", Description: "Поражает область 3x3", Amount: 2}},
+		}),
+	}
+}
+
+// TestViews - вывод каждого представления в форматах table, json и csv
+func TestViews(t *testing.T) {
+	formats := []output.Format{output.FormatTable, output.FormatJSON, output.FormatCSV}
+	for name, view := range views() {
+		t.Run(name, func(t *testing.T) {
+			var out strings.Builder
+			for _, format := range formats {
+				var buf strings.Builder
+				if err := output.Write(&buf, format, view); err != nil {
+					t.Fatalf("формат %s: %v", format, err)
+				}
+				out.WriteString("== " + string(format) + " ==\n")
+				out.WriteString(clitest.Clean(buf.String()))
+				if !strings.HasSuffix(buf.String(), "\n") {
+					out.WriteString("\n")
+				}
+			}
+			clitest.AssertGolden(t, "views/"+name, out.String())
+		})
+	}
+}
+
+// TestCSVColumns - число ячеек каждой строки CSV совпадает с числом колонок
+func TestCSVColumns(t *testing.T) {
+	for name, view := range views() {
+		for i, row := range view.Rows {
+			if len(row) != len(view.Columns) {
+				t.Errorf("%s: строка %d содержит %d ячеек, колонок %d", name, i, len(row), len(view.Columns))
+			}
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"table", "json", "yaml", "csv", "JSON"} {
+		f, err := output.ParseFormat(name)
+		if err != nil || string(f) != strings.ToLower(name) {
+			t.Errorf("ParseFormat(%q) = %q, %v", name, f, err)
+		}
+	}
+	if _, err := output.ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(\"xml\") должен вернуть ошибку")
+	}
+}
@@ -0,0 +1,209 @@
+package output
+
+import (
+	"strconv"
+	"time"
+
+	"lesta-start-battleship/cli/internal/api/auth"
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/api/inventory"
+	"lesta-start-battleship/cli/internal/api/scoreboard"
+	"lesta-start-battleship/cli/internal/api/shop"
+)
+
+// Колонки CSV и таблиц названы по полям JSON элементов списка,
+// вложенные поля выносятся в отдельные колонки (role_id, role_title, gold).
+// Поля пагинации (total_items, page и т.д.) есть только в JSON и YAML.
+
+// Message - результат действия без данных: {"ok": true, "message": "..."}
+func Message(text string) View {
+	return View{
+		Value: struct {
+			OK      bool   `json:"ok"`
+			Message string `json:"message"`
+		}{true, text},
+		Columns: []string{"ok", "message"},
+		Rows:    [][]string{{"true", text}},
+		Text:    text,
+	}
+}
+
+// Session - текущая сессия: profile, id, username, gold, expires_at
+func Session(profile string, p *auth.ProfileResponse, expiresAt *time.Time) View {
+	value := struct {
+		Profile   string     `json:"profile"`
+		ID        int        `json:"id"`
+		Username  string     `json:"username"`
+		Gold      int        `json:"gold"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}{profile, p.ID, p.Username, p.Currency.Gold, expiresAt}
+
+	expires := ""
+	if expiresAt != nil {
+		expires = timeString(*expiresAt)
+	}
+	return View{
+		Value:   value,
+		Columns: []string{"profile", "id", "username", "gold", "expires_at"},
+		Rows:    [][]string{{profile, itoa(p.ID), p.Username, itoa(p.Currency.Gold), expires}},
+	}
+}
+
+// Guilds - список гильдий: id, tag, title, owner_id, is_active, is_full
+func Guilds(p *guilds.GuildPagination) View {
+	v := View{Value: p, Columns: []string{"id", "tag", "title", "owner_id", "is_active", "is_full"}}
+	for _, g := range p.Items {
+		v.Rows = append(v.Rows, guildRow(g))
+	}
+	return v
+}
+
+// Guild - гильдия: колонки как у Guilds и description
+func Guild(g *guilds.GuildResponse) View {
+	return View{
+		Value:   g,
+		Columns: []string{"id", "tag", "title", "owner_id", "is_active", "is_full", "description"},
+		Rows:    [][]string{append(guildRow(*g), g.Description)},
+	}
+}
+
+func guildRow(g guilds.GuildResponse) []string {
+	return []string{itoa(g.ID), g.Tag, g.Title, itoa(g.OwnerID), btoa(g.IsActive), btoa(g.IsFull)}
+}
+
+// Members - участники гильдии: user_id, user_name, guild_id, guild_tag, role_id, role_title
+func Members(p *guilds.MemberPagination) View {
+	v := View{Value: p, Columns: []string{"user_id", "user_name", "guild_id", "guild_tag", "role_id", "role_title"}}
+	for _, m := range p.Items {
+		v.Rows = append(v.Rows, []string{itoa(m.UserID), m.UserName, itoa(m.GuildID), m.GuildTag, itoa(m.Role.ID), m.Role.Title})
+	}
+	return v
+}
+
+// JoinRequests - заявки на вступление: user_id, user_name, created_at
+func JoinRequests(p *guilds.RequestPagination) View {
+	v := View{Value: p, Columns: []string{"user_id", "user_name", "created_at"}}
+	for _, r := range p.Items {
+		v.Rows = append(v.Rows, []string{itoa(r.UserID), r.UserName, r.CreatedAt})
+	}
+	return v
+}
+
+// Wars - войны гильдии: id, стороны, status, счет, created_at и expires_at
+func Wars(p *guilds.GuildWarListResponse) View {
+	v := View{Value: p, Columns: []string{
+		"id", "initiator_guild_id", "target_guild_id", "status", "initiator_score", "target_score", "created_at", "expires_at",
+	}}
+	for _, w := range p.Results {
+		v.Rows = append(v.Rows, []string{
+			itoa(w.ID), itoa(w.InitiatorGuildID), itoa(w.TargetGuildID), string(w.Status),
+			itoa(w.InitiatorScore), itoa(w.TargetScore), timeString(w.CreatedAt), timeString(w.ExpiresAt),
+		})
+	}
+	return v
+}
+
+// AuditLog - журнал действий гильдии: id, created_at, action, actor_id, actor_name, target_id, target_name, details
+func AuditLog(p *guilds.AuditPagination) View {
+	v := View{Value: p, Columns: []string{
+		"id", "created_at", "action", "actor_id", "actor_name", "target_id", "target_name", "details",
+	}}
+	for _, e := range p.Items {
+		v.Rows = append(v.Rows, []string{
+			itoa(e.ID), timeString(e.CreatedAt), string(e.Action), itoa(e.ActorID), e.ActorName,
+			itoa(e.TargetID), e.TargetName, e.Details,
+		})
+	}
+	return v
+}
+
+// UserStats - рейтинг игроков: id, name, rating, gold, experience, chest_opened и позиции *_pos
+func UserStats(r *scoreboard.UserListResponse) View {
+	v := View{Value: r, Columns: []string{
+		"id", "name", "rating", "rating_rating_pos", "gold", "gold_rating_pos",
+		"experience", "exp_rating_pos", "chest_opened", "chest_opened_pos",
+	}}
+	for _, s := range r.Items {
+		v.Rows = append(v.Rows, []string{
+			itoa(s.ID), s.Name, itoa(s.Rating), itoa(s.RatingRatingPos), itoa(s.Gold), itoa(s.GoldRatingPos),
+			itoa(s.Experience), itoa(s.ExpRatingPos), itoa(s.ChestsOpened), itoa(s.ChestsOpenedRatingPos),
+		})
+	}
+	return v
+}
+
+// GuildStats - рейтинг гильдий: id, name, guild_members, wars_victories и позиции *_rating_pos
+func GuildStats(r *scoreboard.GuildListResponse) View {
+	v := View{Value: r, Columns: []string{
+		"id", "name", "guild_members", "guild_members_rating_pos", "wars_victories", "wars_victories_rating_pos",
+	}}
+	for _, s := range r.Items {
+		v.Rows = append(v.Rows, []string{
+			itoa(s.ID), s.Name, itoa(s.GuildMembers), itoa(s.GuildMembersRatingPos), itoa(s.WarsVictories), itoa(s.WarsVictoriesRatingPos),
+		})
+	}
+	return v
+}
+
+// Products - товары магазина: id, name, cost, currency_type, daily_purchase_limit, promotion, description
+func Products(products []shop.Product) View {
+	v := View{Value: products, Columns: []string{"id", "name", "cost", "currency_type", "daily_purchase_limit", "promotion", "description"}}
+	for _, p := range products {
+		v.Rows = append(v.Rows, []string{itoa(p.ID), p.Name, itoa(p.Cost), p.Currency, optional(p.DailyLimit), optional(p.PromotionID), p.Description})
+	}
+	return v
+}
+
+// Chests - сундуки: id, name, cost, currency_type, gold, experience, item_probability, daily_purchase_limit, promotion
+func Chests(chests []shop.Chest) View {
+	v := View{Value: chests, Columns: []string{
+		"id", "name", "cost", "currency_type", "gold", "experience", "item_probability", "daily_purchase_limit", "promotion",
+	}}
+	for _, c := range chests {
+		v.Rows = append(v.Rows, []string{
+			itoa(c.ID), c.Name, itoa(c.Cost), c.Currency, itoa(c.Gold), itoa(c.Experience), itoa(c.ItemProbability), optional(c.DailyLimit), optional(c.PromotionID),
+		})
+	}
+	return v
+}
+
+// Promotions - акции: id, name, start_date, end_date, is_active, description
+func Promotions(promotions []shop.Promotion) View {
+	v := View{Value: promotions, Columns: []string{"id", "name", "start_date", "end_date", "is_active", "description"}}
+	for _, p := range promotions {
+		v.Rows = append(v.Rows, []string{itoa(p.ID), p.Name, timeString(p.StartDate), timeString(p.EndDate), btoa(p.IsActive), p.Description})
+	}
+	return v
+}
+
+// Inventory - инвентарь: item_id, name, amount, description
+func Inventory(r *inventory.UserInventoryResponse) View {
+	v := View{Value: r, Columns: []string{"item_id", "name", "amount", "description"}}
+	for _, item := range r.Items {
+		v.Rows = append(v.Rows, []string{itoa(item.ItemID), item.Name, itoa(item.Amount), item.Description})
+	}
+	return v
+}
+
+func itoa(n int) string {
+	return strconv.Itoa(n)
+}
+
+func btoa(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+// optional - пустая строка для незаданного значения
+func optional(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+func timeString(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -23,12 +23,18 @@ var (
 			Background(lipgloss.Color("#1E88E5")).Bold(true).Padding(0, 1)
 
 	ChatInputStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))
+	CursorStyle        = lipgloss.NewStyle().Reverse(true)
 	SystemMessageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Italic(true)
 	OwnMessageStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00")).Bold(true)
 	OtherMessageStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#CCCCCC"))
 	NewMessageStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500")).Bold(true)
+	WhisperStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#CE93D8")).Italic(true)
+	MentionStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#000000")).Background(lipgloss.Color("#FFD600")).Bold(true)
 
 	HelpStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Italic(true)
 
+	ToastStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#FF9800")).Foreground(lipgloss.Color("#FFFFFF")).Padding(0, 1)
+
 	WindowWidth = 80
 )
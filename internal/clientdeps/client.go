@@ -6,6 +6,12 @@ import (
 	"lesta-start-battleship/cli/internal/api/inventory"
 	"lesta-start-battleship/cli/internal/api/scoreboard"
 	"lesta-start-battleship/cli/internal/api/shop"
+	"lesta-start-battleship/cli/internal/config"
+	"lesta-start-battleship/cli/internal/notify"
+	"lesta-start-battleship/cli/storage/audit"
+	"lesta-start-battleship/cli/storage/chat"
+	"lesta-start-battleship/cli/storage/guild"
+	"lesta-start-battleship/cli/storage/profile"
 )
 
 type Client struct {
@@ -14,4 +20,17 @@ type Client struct {
 	InventoryClient  *inventory.Client
 	ScoreboardClient *scoreboard.Client
 	ShopClient       *shop.Client
+	Endpoints        config.Endpoints
+
+	GuildStore    *guild.Store   // данные гильдий текущей сессии
+	Notifications *notify.Center // уведомления о событиях гильдии текущей сессии
+	Audit         *audit.Log     // локальный журнал действий управления гильдией
+	Chat          *chat.Log      // локальная история чатов гильдий
+	Direct        *chat.Direct   // личные переписки текущей сессии
+
+	Profile  string         // имя активного профиля
+	Profiles *profile.Store // хранилище профилей
+
+	// OpenProfile - создание клиентов для другого профиля без перезапуска приложения
+	OpenProfile func(name string) (*Client, error)
 }
@@ -0,0 +1,291 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Имена встроенных окружений
+const (
+	EnvProd    = "prod"
+	EnvStaging = "staging"
+	EnvLocal   = "local"
+
+	DefaultEnv = EnvProd
+)
+
+// Переменные окружения, которые учитываются при загрузке
+const (
+	EnvVarConfig      = "BATTLESHIP_CONFIG"
+	EnvVarEnvironment = "BATTLESHIP_ENV"
+	EnvVarProfile     = "BATTLESHIP_PROFILE"
+)
+
+// Endpoints - адреса всех сервисов, с которыми работает клиент
+type Endpoints struct {
+	Auth        string `yaml:"auth"`
+	Guilds      string `yaml:"guilds"`
+	Inventory   string `yaml:"inventory"`
+	Scoreboard  string `yaml:"scoreboard"`
+	Shop        string `yaml:"shop"`
+	GuildChat   string `yaml:"guild_chat"`  // базовый адрес websocket чата гильдий
+	Direct      string `yaml:"direct"`      // базовый адрес websocket личных сообщений, пустой - личные сообщения недоступны
+	Matchmaking string `yaml:"matchmaking"` // базовый адрес websocket матчмейкинга
+	ChatServer  string `yaml:"chat_server"` // адрес локального чат-сервера (cmd/chat_server)
+
+	// Notifications - базовый адрес websocket уведомлений гильдий.
+	// Пустой адрес - уведомления получаются опросом сервиса гильдий.
+	Notifications string `yaml:"notifications"`
+}
+
+// Config - итоговая конфигурация клиента после применения всех слоев
+type Config struct {
+	Environment string    // имя выбранного окружения
+	Profile     string    // имя профиля, если задан флагом или переменной окружения
+	Path        string    // путь к файлу конфигурации, если он был прочитан
+	Endpoints   Endpoints // адреса сервисов выбранного окружения
+	Args        []string  // аргументы, оставшиеся после разбора флагов
+
+	environments map[string]Endpoints
+	overrides    Endpoints // адреса из переменных окружения и флагов
+	explicitEnv  bool      // окружение задано флагом или переменной окружения
+}
+
+// fileConfig - формат файла конфигурации
+type fileConfig struct {
+	Environment  string               `yaml:"environment"`
+	Environments map[string]Endpoints `yaml:"environments"`
+}
+
+// defaults - встроенные окружения
+var defaults = map[string]Endpoints{
+	EnvProd: {
+		Auth:        "https://battleship-lesta-start.ru/",
+		Guilds:      "https://battleship-lesta-start.ru/guild/",
+		Inventory:   "https://battleship-lesta-start.ru/inventory/",
+		Scoreboard:  "https://battleship-lesta-start.ru/scoreboard/",
+		Shop:        "https://battleship-lesta-start.ru/shop/",
+		GuildChat:   "ws://37.9.53.187:8000/api/v1/chat/",
+		Matchmaking: "ws://37.9.53.32:80/matchmaking/",
+		ChatServer:  "ws://localhost:8080/",
+	},
+	// staging не имеет встроенных адресов и описывается в файле конфигурации
+	EnvStaging: {},
+	EnvLocal: {
+		Auth:        "http://localhost:8090/",
+		Guilds:      "http://localhost:8090/guild/",
+		Inventory:   "http://localhost:8090/inventory/",
+		Scoreboard:  "http://localhost:8090/scoreboard/",
+		Shop:        "http://localhost:8090/shop/",
+		GuildChat:   "ws://localhost:8090/api/v1/chat/",
+		Direct:      "ws://localhost:8090/api/v1/direct/",
+		Matchmaking: "ws://localhost:8090/matchmaking/",
+		ChatServer:  "ws://localhost:8080/",
+
+		Notifications: "ws://localhost:8090/api/v1/notifications/",
+	},
+}
+
+// endpointVars - переменные окружения и флаги для переопределения отдельных адресов
+var endpointVars = []struct {
+	flag   string
+	envVar string
+	usage  string
+	field  func(*Endpoints) *string
+}{
+	{"auth-url", "BATTLESHIP_AUTH_URL", "адрес сервиса авторизации", func(e *Endpoints) *string { return &e.Auth }},
+	{"guilds-url", "BATTLESHIP_GUILDS_URL", "адрес сервиса гильдий", func(e *Endpoints) *string { return &e.Guilds }},
+	{"inventory-url", "BATTLESHIP_INVENTORY_URL", "адрес сервиса инвентаря", func(e *Endpoints) *string { return &e.Inventory }},
+	{"scoreboard-url", "BATTLESHIP_SCOREBOARD_URL", "адрес сервиса рейтингов", func(e *Endpoints) *string { return &e.Scoreboard }},
+	{"shop-url", "BATTLESHIP_SHOP_URL", "адрес сервиса магазина", func(e *Endpoints) *string { return &e.Shop }},
+	{"guild-chat-url", "BATTLESHIP_GUILD_CHAT_URL", "базовый адрес чата гильдий", func(e *Endpoints) *string { return &e.GuildChat }},
+	{"direct-url", "BATTLESHIP_DIRECT_URL", "базовый адрес личных сообщений", func(e *Endpoints) *string { return &e.Direct }},
+	{"matchmaking-url", "BATTLESHIP_MATCHMAKING_URL", "базовый адрес матчмейкинга", func(e *Endpoints) *string { return &e.Matchmaking }},
+	{"chat-server-url", "BATTLESHIP_CHAT_SERVER_URL", "адрес локального чат-сервера", func(e *Endpoints) *string { return &e.ChatServer }},
+	{"notifications-url", "BATTLESHIP_NOTIFICATIONS_URL", "базовый адрес уведомлений гильдий", func(e *Endpoints) *string { return &e.Notifications }},
+}
+
+// optionalEndpoints - флаги адресов, без которых клиент работает
+var optionalEndpoints = map[string]bool{
+	"notifications-url": true,
+	"direct-url":        true,
+}
+
+// Load - загрузка конфигурации.
+// Слои применяются по порядку: встроенные значения, файл, переменные окружения, флаги.
+//
+// Аргументы после флагов сохраняются в Config.Args.
+func Load(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("cli", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	configFlag := fs.String("config", "", "путь к файлу конфигурации (YAML)")
+	envFlag := fs.String("env", "", "окружение: prod, staging, local или описанное в файле")
+	profileFlag := fs.String("profile", "", "имя профиля аккаунта")
+	endpointFlags := make([]*string, len(endpointVars))
+	for i, v := range endpointVars {
+		endpointFlags[i] = fs.String(v.flag, "", v.usage)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("ошибка разбора флагов: %w", err)
+	}
+
+	// файл конфигурации
+	path := firstNonEmpty(*configFlag, os.Getenv(EnvVarConfig))
+	explicitPath := path != ""
+	if path == "" {
+		path = DefaultPath()
+	}
+
+	file, err := readFile(path)
+	if err != nil {
+		if explicitPath || !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		path = ""
+	}
+
+	environments := make(map[string]Endpoints, len(defaults))
+	for name, endpoints := range defaults {
+		environments[name] = endpoints
+	}
+	for name, endpoints := range file.Environments {
+		environments[name] = merge(environments[name], endpoints)
+	}
+
+	// переменные окружения и флаги для отдельных адресов
+	var overrides Endpoints
+	for i, v := range endpointVars {
+		if value := os.Getenv(v.envVar); value != "" {
+			*v.field(&overrides) = value
+		}
+		if value := *endpointFlags[i]; value != "" {
+			*v.field(&overrides) = value
+		}
+	}
+
+	explicitEnv := firstNonEmpty(*envFlag, os.Getenv(EnvVarEnvironment))
+	cfg := &Config{
+		Profile:      firstNonEmpty(*profileFlag, os.Getenv(EnvVarProfile)),
+		Path:         path,
+		Args:         fs.Args(),
+		environments: environments,
+		overrides:    overrides,
+		explicitEnv:  explicitEnv != "",
+	}
+
+	// выбор окружения: флаг, переменная окружения, файл, значение по умолчанию
+	envName := firstNonEmpty(explicitEnv, file.Environment, DefaultEnv)
+	endpoints, err := cfg.ForEnvironment(envName)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Environment = envName
+	cfg.Endpoints = endpoints
+
+	return cfg, nil
+}
+
+// ForEnvironment - адреса сервисов окружения name с учетом переопределений из переменных окружения и флагов
+func (c *Config) ForEnvironment(name string) (Endpoints, error) {
+	endpoints, ok := c.environments[name]
+	if !ok {
+		return Endpoints{}, fmt.Errorf("неизвестное окружение %q (доступны: %s)", name, strings.Join(names(c.environments), ", "))
+	}
+
+	endpoints = merge(endpoints, c.overrides)
+	if err := endpoints.validate(); err != nil {
+		return Endpoints{}, fmt.Errorf("окружение %q: %w", name, err)
+	}
+	return endpoints, nil
+}
+
+// EnvironmentExplicit - задано ли окружение флагом или переменной окружения.
+// Такое окружение имеет приоритет над окружением профиля.
+func (c *Config) EnvironmentExplicit() bool {
+	return c.explicitEnv
+}
+
+// Usage - описание флагов конфигурации для справки
+func Usage(w io.Writer) {
+	fmt.Fprintln(w, "  -config string\tпуть к файлу конфигурации (YAML), также "+EnvVarConfig)
+	fmt.Fprintln(w, "  -env string\t\tокружение: prod, staging, local, также "+EnvVarEnvironment)
+	fmt.Fprintln(w, "  -profile string\tимя профиля аккаунта, также "+EnvVarProfile)
+	for _, v := range endpointVars {
+		fmt.Fprintf(w, "  -%s string\t%s, также %s\n", v.flag, v.usage, v.envVar)
+	}
+}
+
+// DefaultPath - путь к файлу конфигурации по умолчанию
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "config.yaml"
+	}
+	return filepath.Join(dir, "lesta-battleship", "config.yaml")
+}
+
+func readFile(path string) (fileConfig, error) {
+	var file fileConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file, fmt.Errorf("ошибка чтения файла конфигурации: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return file, fmt.Errorf("ошибка разбора файла конфигурации %s: %w", path, err)
+	}
+
+	return file, nil
+}
+
+// merge - переопределение непустых адресов окружения
+func merge(base, override Endpoints) Endpoints {
+	for _, v := range endpointVars {
+		if value := *v.field(&override); value != "" {
+			*v.field(&base) = value
+		}
+	}
+	return base
+}
+
+func (e Endpoints) validate() error {
+	var missing []string
+	for _, v := range endpointVars {
+		if *v.field(&e) == "" && !optionalEndpoints[v.flag] {
+			missing = append(missing, v.flag)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("не заданы адреса: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func names(environments map[string]Endpoints) []string {
+	result := make([]string, 0, len(environments))
+	for name := range environments {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
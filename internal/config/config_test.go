@@ -0,0 +1,192 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// isolate - окружение теста без переменных BATTLESHIP_* и без файла конфигурации пользователя
+func isolate(t *testing.T) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	for _, name := range []string{EnvVarConfig, EnvVarEnvironment, EnvVarProfile} {
+		t.Setenv(name, "")
+	}
+	for _, v := range endpointVars {
+		t.Setenv(v.envVar, "")
+	}
+}
+
+// writeConfig - файл конфигурации с содержимым data
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const testFile = `
+environment: local
+environments:
+  local:
+    auth: http://file/
+    shop: http://file-shop/
+  staging:
+    auth: http://staging/
+    guilds: http://staging/guild/
+    inventory: http://staging/inventory/
+    scoreboard: http://staging/scoreboard/
+    shop: http://staging/shop/
+    guild_chat: ws://staging/chat/
+    matchmaking: ws://staging/matchmaking/
+    chat_server: ws://staging:8080/
+`
+
+func TestLoadPrecedence(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      bool
+		env       map[string]string
+		args      []string
+		wantEnv   string
+		wantAuth  string
+		wantShop  string
+		wantGuild string
+		explicit  bool
+	}{
+		{
+			name:      "встроенные значения",
+			wantEnv:   DefaultEnv,
+			wantAuth:  defaults[EnvProd].Auth,
+			wantShop:  defaults[EnvProd].Shop,
+			wantGuild: defaults[EnvProd].Guilds,
+		},
+		{
+			name:      "файл выбирает окружение и дополняет встроенное",
+			file:      true,
+			wantEnv:   EnvLocal,
+			wantAuth:  "http://file/",
+			wantShop:  "http://file-shop/",
+			wantGuild: defaults[EnvLocal].Guilds,
+		},
+		{
+			name:      "переменные окружения важнее файла",
+			file:      true,
+			env:       map[string]string{EnvVarEnvironment: EnvStaging, "BATTLESHIP_SHOP_URL": "http://env-shop/"},
+			wantEnv:   EnvStaging,
+			wantAuth:  "http://staging/",
+			wantShop:  "http://env-shop/",
+			wantGuild: "http://staging/guild/",
+			explicit:  true,
+		},
+		{
+			name:      "флаги важнее переменных окружения",
+			file:      true,
+			env:       map[string]string{EnvVarEnvironment: EnvStaging, "BATTLESHIP_SHOP_URL": "http://env-shop/"},
+			args:      []string{"--env", EnvLocal, "--shop-url", "http://flag-shop/"},
+			wantEnv:   EnvLocal,
+			wantAuth:  "http://file/",
+			wantShop:  "http://flag-shop/",
+			wantGuild: defaults[EnvLocal].Guilds,
+			explicit:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t)
+			if tt.file {
+				t.Setenv(EnvVarConfig, writeConfig(t, testFile))
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := Load(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Environment != tt.wantEnv || cfg.EnvironmentExplicit() != tt.explicit {
+				t.Errorf("окружение %q (явное %v), ожидалось %q (%v)", cfg.Environment, cfg.EnvironmentExplicit(), tt.wantEnv, tt.explicit)
+			}
+			got := cfg.Endpoints
+			if got.Auth != tt.wantAuth || got.Shop != tt.wantShop || got.Guilds != tt.wantGuild {
+				t.Errorf("адреса auth %q, shop %q, guilds %q, ожидалось %q, %q, %q",
+					got.Auth, got.Shop, got.Guilds, tt.wantAuth, tt.wantShop, tt.wantGuild)
+			}
+		})
+	}
+}
+
+func TestForEnvironment(t *testing.T) {
+	isolate(t)
+	t.Setenv("BATTLESHIP_AUTH_URL", "http://override/")
+	cfg, err := Load([]string{"--profile", "officer", "stats", "--json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Profile != "officer" || strings.Join(cfg.Args, " ") != "stats --json" {
+		t.Errorf("профиль %q, аргументы %q", cfg.Profile, cfg.Args)
+	}
+
+	// переопределения из переменных окружения действуют в любом окружении
+	local, err := cfg.ForEnvironment(EnvLocal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if local.Auth != "http://override/" || local.Shop != defaults[EnvLocal].Shop {
+		t.Errorf("адреса local: %+v", local)
+	}
+
+	if _, err := cfg.ForEnvironment("qa"); err == nil || !strings.Contains(err.Error(), "неизвестное окружение") {
+		t.Errorf("неизвестное окружение: %v", err)
+	}
+	// staging без файла не содержит обязательных адресов
+	if _, err := cfg.ForEnvironment(EnvStaging); err == nil || !strings.Contains(err.Error(), "не заданы адреса") {
+		t.Errorf("staging без адресов: %v", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := map[string]func(t *testing.T) []string{
+		"неизвестное окружение флагом": func(*testing.T) []string { return []string{"--env", "qa"} },
+		"неизвестное окружение в переменной": func(t *testing.T) []string {
+			t.Setenv(EnvVarEnvironment, "qa")
+			return nil
+		},
+		"неизвестное окружение в файле": func(t *testing.T) []string {
+			t.Setenv(EnvVarConfig, writeConfig(t, "environment: qa\n"))
+			return nil
+		},
+		"отсутствующий явный файл": func(t *testing.T) []string {
+			return []string{"--config", filepath.Join(t.TempDir(), "missing.yaml")}
+		},
+		"некорректный файл": func(t *testing.T) []string {
+			return []string{"--config", writeConfig(t, "environments: [")}
+		},
+		"неизвестный флаг": func(*testing.T) []string { return []string{"--no-such-flag"} },
+	}
+	for name, setup := range tests {
+		t.Run(name, func(t *testing.T) {
+			isolate(t)
+			if _, err := Load(setup(t)); err == nil {
+				t.Error("ошибки нет")
+			}
+		})
+	}
+}
+
+func TestLoadDefaultFileOptional(t *testing.T) {
+	isolate(t)
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("без файла по умолчанию: %v", err)
+	}
+	if cfg.Path != "" {
+		t.Errorf("путь прочитанного файла: %q", cfg.Path)
+	}
+}
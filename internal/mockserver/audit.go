@@ -0,0 +1,71 @@
+package mockserver
+
+import (
+	"net/http"
+	"time"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+)
+
+// AuditRecord - запись журнала действий гильдии guild_id
+type AuditRecord struct {
+	GuildID int `json:"guild_id"`
+	guilds.AuditEntry
+}
+
+// addAudit - запись в журнал гильдии guildID от имени actorID, вызывается под блокировкой
+func (s *Store) addAudit(guildID, actorID int, entry guilds.AuditEntry) {
+	s.nextAuditID++
+	entry.ID = s.nextAuditID
+	entry.ActorID = actorID
+	if u := s.users[actorID]; u != nil {
+		entry.ActorName = u.Username
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	s.audit = append(s.audit, AuditRecord{GuildID: guildID, AuditEntry: entry})
+}
+
+// userAudit - запись о действии actorID над пользователем targetID, вызывается под блокировкой
+func (s *Store) userAudit(guildID, actorID, targetID int, action guilds.AuditAction, details string) {
+	entry := guilds.AuditEntry{Action: action, TargetID: targetID, Details: details}
+	if u := s.users[targetID]; u != nil {
+		entry.TargetName = u.Username
+	}
+	s.addAudit(guildID, actorID, entry)
+}
+
+// handleGetAuditLog - журнал гильдии, новые записи первыми. Доступен участникам, которые управляют гильдией.
+func (s *Server) handleGetAuditLog(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.actorParam(w, r, "user_id")
+	if !ok {
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := queryInt(r, "limit", 10)
+	if err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	guild, _ := s.managedGuild(w, r, userID)
+	if guild == nil {
+		return
+	}
+
+	var all []guilds.AuditEntry
+	for i := len(s.store.audit) - 1; i >= 0; i-- {
+		if rec := s.store.audit[i]; rec.GuildID == guild.ID {
+			all = append(all, rec.AuditEntry)
+		}
+	}
+	items := append([]guilds.AuditEntry{}, page(all, offset, limit)...)
+	guildOK(w, &guilds.AuditPagination{Items: items, TotalItems: len(all), TotalPages: pages(len(all), limit)})
+}
@@ -0,0 +1,280 @@
+package mockserver
+
+import (
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"sync"
+
+	"lesta-start-battleship/cli/internal/api/auth"
+)
+
+// registerAuth - маршруты сервиса авторизации (пути из auth/models.go)
+func (s *Server) registerAuth() {
+	s.mux.HandleFunc("POST "+route(auth.RegistrationPath), s.handleRegister)
+	s.mux.HandleFunc("POST "+route(auth.LoginPath), s.handleLogin)
+	s.mux.HandleFunc("POST "+route(auth.RefreshTokenPath), s.handleRefresh)
+	s.mux.HandleFunc("POST "+route(auth.LogoutPath), s.handleLogout)
+	s.mux.HandleFunc(pathRoute("GET", "", auth.GetProfilePath, "id"), s.handleGetUser)
+	s.mux.HandleFunc(pathRoute("PATCH", "", auth.UpdateUserPath, "id"), s.handleUpdateUser)
+	s.mux.HandleFunc(pathRoute("DELETE", "", auth.DeleteUserPath, "id"), s.handleDeleteUser)
+
+	s.mux.HandleFunc("POST "+route(auth.GoogleInitPath), s.handleDeviceInit("google"))
+	s.mux.HandleFunc("POST "+route(auth.GoogleCheckPath), s.handleDeviceCheck)
+	s.mux.HandleFunc("POST "+route(auth.YandexInitPath), s.handleDeviceInit("yandex"))
+	s.mux.HandleFunc("POST "+route(auth.YandexCheckPath), s.handleDeviceCheck)
+}
+
+func authError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, auth.ErrorResponse{Error: err.Error()})
+}
+
+// profileResponse - профиль в формате API, вызывается под блокировкой
+func profileResponse(u *User) auth.ProfileResponse {
+	p := auth.ProfileResponse{ID: u.ID, Username: u.Username, Email: u.Email}
+	p.Currency.Gold = u.Gold
+	p.Currency.GuildRage = u.GuildRage
+	return p
+}
+
+func (s *Server) writeTokens(w http.ResponseWriter, userID int) {
+	access, refresh, err := s.issueTokens(userID)
+	if err != nil {
+		authError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, auth.TokenResponse{AccessToken: access, RefreshToken: refresh})
+}
+
+func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
+	var req auth.UserRegRequest
+	if err := readJSON(r, &req); err != nil {
+		authError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Username == "" || req.Password == "" {
+		authError(w, http.StatusBadRequest, errors.New("не заданы имя пользователя или пароль"))
+		return
+	}
+
+	s.store.mu.Lock()
+	if s.store.userByName(req.Username) != nil {
+		s.store.mu.Unlock()
+		authError(w, http.StatusConflict, errors.New("пользователь уже существует"))
+		return
+	}
+	s.store.nextUserID++
+	user := &User{
+		ID:       s.store.nextUserID,
+		Username: req.Username,
+		Email:    req.Email,
+		Password: req.Password,
+		Gold:     1000,
+	}
+	s.store.users[user.ID] = user
+	s.store.mu.Unlock()
+
+	s.writeTokens(w, user.ID)
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var req auth.LoginRequest
+	if err := readJSON(r, &req); err != nil {
+		authError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.store.mu.Lock()
+	user := s.store.userByName(req.Username)
+	s.store.mu.Unlock()
+	if user == nil || user.Password != req.Password {
+		authError(w, http.StatusUnauthorized, errors.New("неверное имя пользователя или пароль"))
+		return
+	}
+
+	s.writeTokens(w, user.ID)
+}
+
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.tokenUser(r, true)
+	if err != nil {
+		authError(w, http.StatusUnauthorized, err)
+		return
+	}
+	s.writeTokens(w, userID)
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if _, err := s.tokenUser(r, false); err != nil {
+		authError(w, http.StatusUnauthorized, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, auth.SuccessResponse{Message: "выход выполнен"})
+}
+
+func (s *Server) handleGetUser(w http.ResponseWriter, r *http.Request) {
+	if _, err := s.tokenUser(r, false); err != nil {
+		authError(w, http.StatusUnauthorized, err)
+		return
+	}
+	id, err := pathInt(r, "id")
+	if err != nil {
+		authError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	user, ok := s.store.users[id]
+	if !ok {
+		authError(w, http.StatusNotFound, errors.New("пользователь не найден"))
+		return
+	}
+	writeJSON(w, http.StatusOK, profileResponse(user))
+}
+
+func (s *Server) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := s.selfRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req auth.UpdateUserRequest
+	if err := readJSON(r, &req); err != nil {
+		authError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	user := s.store.users[userID]
+	if req.Username != "" && req.Username != user.Username {
+		if s.store.userByName(req.Username) != nil {
+			authError(w, http.StatusConflict, errors.New("имя пользователя занято"))
+			return
+		}
+		user.Username = req.Username
+	}
+	if req.Password != "" {
+		user.Password = req.Password
+	}
+	writeJSON(w, http.StatusOK, profileResponse(s.store.users[id]))
+}
+
+func (s *Server) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := s.selfRequest(w, r)
+	if !ok {
+		return
+	}
+
+	s.store.mu.Lock()
+	s.store.removeUser(userID)
+	s.store.mu.Unlock()
+	writeJSON(w, http.StatusOK, auth.SuccessResponse{Message: "пользователь удален"})
+}
+
+// selfRequest - проверка, что пользователь изменяет собственный профиль
+func (s *Server) selfRequest(w http.ResponseWriter, r *http.Request) (userID, id int, ok bool) {
+	userID, err := s.tokenUser(r, false)
+	if err != nil {
+		authError(w, http.StatusUnauthorized, err)
+		return 0, 0, false
+	}
+	id, err = pathInt(r, "id")
+	if err != nil {
+		authError(w, http.StatusBadRequest, err)
+		return 0, 0, false
+	}
+	if id != userID {
+		authError(w, http.StatusForbidden, errors.New("можно изменять только свой профиль"))
+		return 0, 0, false
+	}
+	return userID, id, true
+}
+
+// oauthDevices - коды устройств OAuth.
+// Авторизация считается подтвержденной со второй проверки кода.
+type oauthDevices struct {
+	mu     sync.Mutex
+	checks map[string]int    // device_code -> количество проверок
+	users  map[string]string // device_code -> провайдер
+}
+
+func newOAuthDevices() *oauthDevices {
+	return &oauthDevices{checks: make(map[string]int), users: make(map[string]string)}
+}
+
+// handleDeviceInit - начало входа через провайдера provider
+func (s *Server) handleDeviceInit(provider string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deviceCode := rand.Text()
+
+		s.oauth.mu.Lock()
+		s.oauth.checks[deviceCode] = 0
+		s.oauth.users[deviceCode] = provider
+		s.oauth.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, auth.DeviceAuthResponse{
+			UserCode:        deviceCode[:8],
+			DeviceCode:      deviceCode,
+			VerificationURL: "http://" + r.Host + "/oauth/verify",
+			ExpiresIn:       300,
+			Interval:        1,
+		})
+	}
+}
+
+func (s *Server) handleDeviceCheck(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DeviceCode string `json:"device_code"`
+	}
+	if err := readJSON(r, &req); err != nil {
+		authError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.oauth.mu.Lock()
+	provider, ok := s.oauth.users[req.DeviceCode]
+	s.oauth.checks[req.DeviceCode]++
+	checks := s.oauth.checks[req.DeviceCode]
+	s.oauth.mu.Unlock()
+
+	if !ok {
+		writeJSON(w, http.StatusOK, auth.DeviceCheckResponse{Status: "expired"})
+		return
+	}
+	if checks < 2 {
+		writeJSON(w, http.StatusOK, auth.DeviceCheckResponse{Status: "pending"})
+		return
+	}
+
+	// пользователь провайдера создается при первом входе
+	username := provider + "_player"
+	s.store.mu.Lock()
+	user := s.store.userByName(username)
+	if user == nil {
+		s.store.nextUserID++
+		user = &User{ID: s.store.nextUserID, Username: username, Email: username + "@example.com", Gold: 1000}
+		s.store.users[user.ID] = user
+	}
+	profile := profileResponse(user)
+	s.store.mu.Unlock()
+
+	access, refresh, err := s.issueTokens(user.ID)
+	if err != nil {
+		authError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.oauth.mu.Lock()
+	delete(s.oauth.users, req.DeviceCode)
+	delete(s.oauth.checks, req.DeviceCode)
+	s.oauth.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, auth.DeviceCheckResponse{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		Status:       "authenticated",
+		User:         &profile,
+	})
+}
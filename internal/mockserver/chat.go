@@ -0,0 +1,319 @@
+package mockserver
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+)
+
+// chatHistoryLimit - сколько последних сообщений отправляется при подключении
+const chatHistoryLimit = 50
+
+// lobbyID - комната общего чата лобби в chatHub и истории, id гильдий начинаются с 1
+const lobbyID = 0
+
+// wsClient - подключение websocket с очередью отправки.
+// Запись в соединение выполняется только из writeLoop.
+type wsClient struct {
+	conn *websocket.Conn
+	send chan any
+}
+
+func newWSClient(conn *websocket.Conn) *wsClient {
+	c := &wsClient{conn: conn, send: make(chan any, 64)}
+	go c.writeLoop()
+	return c
+}
+
+func (c *wsClient) writeLoop() {
+	for packet := range c.send {
+		if err := c.conn.WriteJSON(packet); err != nil {
+			c.conn.Close()
+			return
+		}
+	}
+	c.conn.Close()
+}
+
+// push - отправка пакета без блокировки, медленный клиент пропускает пакеты
+func (c *wsClient) push(packet any) {
+	select {
+	case c.send <- packet:
+	default:
+	}
+}
+
+// chatHub - подключения к чатам гильдий
+type chatHub struct {
+	mu    sync.Mutex
+	rooms map[int]map[*wsClient]guild.OnlineUser // ключ - guild_id
+}
+
+func newChatHub() *chatHub {
+	return &chatHub{rooms: make(map[int]map[*wsClient]guild.OnlineUser)}
+}
+
+func (h *chatHub) join(guildID int, c *wsClient, user guild.OnlineUser) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.rooms[guildID] == nil {
+		h.rooms[guildID] = make(map[*wsClient]guild.OnlineUser)
+	}
+	h.rooms[guildID][c] = user
+}
+
+func (h *chatHub) leave(guildID int, c *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.rooms[guildID], c)
+	close(c.send)
+}
+
+func (h *chatHub) broadcast(guildID int, packet any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.rooms[guildID] {
+		c.push(packet)
+	}
+}
+
+// relay - пакет всем подключениям комнаты, кроме from
+func (h *chatHub) relay(guildID int, from *wsClient, packet any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.rooms[guildID] {
+		if c != from {
+			c.push(packet)
+		}
+	}
+}
+
+// online - участники в комнате по имени, один раз на пользователя
+func (h *chatHub) online(guildID int) []guild.OnlineUser {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	seen := make(map[int]struct{})
+	users := []guild.OnlineUser{}
+	for _, user := range h.rooms[guildID] {
+		if _, ok := seen[user.UserId]; ok {
+			continue
+		}
+		seen[user.UserId] = struct{}{}
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return users
+}
+
+// whisper - пакет подключениям пользователей userIDs в комнате. Возвращает false, если никого из них нет.
+func (h *chatHub) whisper(guildID int, packet any, userIDs ...int) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delivered := false
+	for c, user := range h.rooms[guildID] {
+		for _, id := range userIDs {
+			if user.UserId == id {
+				c.push(packet)
+				delivered = true
+			}
+		}
+	}
+	return delivered
+}
+
+// chatRequest - пакет клиента: сообщение или служебный пакет с полем type
+type chatRequest struct {
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	Before  string `json:"before"` // HistoryRequest
+	Limit   int    `json:"limit"`  // HistoryRequest
+	To      string `json:"to"`     // Whisper
+	ID      string `json:"_id"`    // Read
+}
+
+// chatPage - до limit сообщений гильдии старше сообщения before
+func (s *Server) chatPage(guildID int, before string, limit int) guild.ChatHistory {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if limit <= 0 || limit > chatHistoryLimit {
+		limit = chatHistoryLimit
+	}
+	page := guild.ChatHistory{Type: guild.HistoryPage, Data: []guild.ChatHistoryMessage{}}
+	history := s.store.chat[guildID]
+	for i, m := range history {
+		if m.Id == before {
+			page.Data = append(page.Data, history[max(i-limit, 0):i]...)
+			break
+		}
+	}
+	return page
+}
+
+// registerChat - websocket чата гильдий и лобби по формату адреса клиента
+func (s *Server) registerChat() {
+	s.mux.HandleFunc("GET /api/v1/chat/ws/guild/{guild_id}/{user_id}", s.handleChat)
+	s.mux.HandleFunc("GET /api/v1/chat/ws/lobby/{user_id}", s.handleLobby)
+}
+
+// chatUser - user_id из адреса, совпадающий с владельцем access token
+func (s *Server) chatUser(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userID, err := pathInt(r, "user_id")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return 0, false
+	}
+	tokenID, err := s.tokenUser(r, false)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return 0, false
+	}
+	if tokenID != userID {
+		http.Error(w, "токен принадлежит другому пользователю", http.StatusForbidden)
+		return 0, false
+	}
+	return userID, true
+}
+
+// handleLobby - общий чат лобби для любого пользователя со своим access token.
+// Протокол тот же, что у чата гильдии, сообщения приходят с guild_id 0.
+func (s *Server) handleLobby(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.chatUser(w, r)
+	if !ok {
+		return
+	}
+	s.serveChat(w, r, lobbyID, userID)
+}
+
+// handleChat - чат гильдии.
+// Подключиться может только участник гильдии со своим access token, user_id в адресе должен совпадать с токеном.
+// Первым пакетом отправляется история, затем каждое новое сообщение рассылается всем участникам, включая автора.
+// На запрос с type "history" клиент получает страницу сообщений старше before, "who" - участников в комнате,
+// "whisper" доставляется только получателю to и автору.
+func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
+	guildID, err := pathInt(r, "guild_id")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userID, ok := s.chatUser(w, r)
+	if !ok {
+		return
+	}
+	s.store.mu.Lock()
+	member := s.store.members[userID]
+	s.store.mu.Unlock()
+	if member == nil || member.GuildID != guildID {
+		http.Error(w, "пользователь не состоит в гильдии", http.StatusForbidden)
+		return
+	}
+	s.serveChat(w, r, guildID, userID)
+}
+
+// serveChat - подключение пользователя к комнате чата guildID: история, рассылка сообщений и служебные пакеты
+func (s *Server) serveChat(w http.ResponseWriter, r *http.Request, guildID, userID int) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.opts.Logger.Printf("chat: ошибка upgrade: %v", err)
+		return
+	}
+	client := newWSClient(conn)
+
+	s.store.mu.Lock()
+	username := fmt.Sprintf("user%d", userID)
+	if u := s.store.users[userID]; u != nil {
+		username = u.Username
+	}
+	history := s.store.chat[guildID]
+	if len(history) > chatHistoryLimit {
+		history = history[len(history)-chatHistoryLimit:]
+	}
+	client.push(guild.ChatHistory{Type: guild.HistoryInitial, Data: append([]guild.ChatHistoryMessage{}, history...)})
+	s.store.mu.Unlock()
+
+	s.chat.join(guildID, client, guild.OnlineUser{UserId: userID, Username: username})
+	defer s.chat.leave(guildID, client)
+
+	for {
+		var msg chatRequest
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		switch msg.Type {
+		case guild.TypeHistory:
+			client.push(s.chatPage(guildID, msg.Before, msg.Limit))
+			continue
+		case guild.TypeWho:
+			client.push(guild.Online{Type: guild.TypeOnline, Users: s.chat.online(guildID)})
+			continue
+		case guild.TypeWhisper:
+			s.chatWhisper(client, guildID, userID, username, msg)
+			continue
+		case guild.TypeTyping:
+			s.chat.relay(guildID, client, guild.Typing{Type: guild.TypeTyping, UserId: userID, Username: username})
+			continue
+		case guild.TypeRead:
+			if msg.ID != "" {
+				s.chat.relay(guildID, client, guild.Read{Type: guild.TypeRead, Id: msg.ID, UserId: userID, Username: username})
+			}
+			continue
+		case guild.TypeMute, guild.TypeUnmute, guild.TypeBan, guild.TypeUnban, guild.TypeDelete:
+			client.push(guild.Error{Type: guild.TypeError, Message: "модерация доступна только на локальном чат-сервере cmd/chat_server"})
+			continue
+		}
+		if msg.Content == "" {
+			continue
+		}
+
+		s.store.mu.Lock()
+		s.store.nextChatID++
+		stored := guild.ChatHistoryMessage{
+			Id:        fmt.Sprintf("%024x", s.store.nextChatID),
+			GuildId:   guildID,
+			UserId:    userID,
+			Content:   msg.Content,
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Username:  username,
+		}
+		s.store.chat[guildID] = append(s.store.chat[guildID], stored)
+		s.store.mu.Unlock()
+
+		s.chat.broadcast(guildID, stored)
+	}
+}
+
+// chatWhisper - шепот автору и получателю, если получатель подключен к комнате. В историю комнаты не попадает.
+func (s *Server) chatWhisper(client *wsClient, guildID, userID int, username string, msg chatRequest) {
+	s.store.mu.Lock()
+	to := s.store.userByName(msg.To)
+	s.store.nextChatID++
+	id := s.store.nextChatID
+	s.store.mu.Unlock()
+
+	if to == nil || msg.Content == "" {
+		client.push(guild.Error{Type: guild.TypeError, Message: "пользователь " + msg.To + " не найден"})
+		return
+	}
+	whisper := guild.ChatHistoryMessage{
+		Id:        fmt.Sprintf("%024x", id),
+		GuildId:   guildID,
+		UserId:    userID,
+		Content:   msg.Content,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Username:  username,
+		To:        to.Username,
+	}
+	if !s.chat.whisper(guildID, whisper, to.ID) {
+		client.push(guild.Error{Type: guild.TypeError, Message: to.Username + " не в чате гильдии"})
+		return
+	}
+	if to.ID != userID {
+		s.chat.whisper(guildID, whisper, userID)
+	}
+}
@@ -0,0 +1,202 @@
+package mockserver
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/direct"
+)
+
+// directMessageLimit - максимальная длина личного сообщения в символах, как в поле ввода клиента
+const directMessageLimit = 500
+
+// directHub - подключения к личным сообщениям
+type directHub struct {
+	mu      sync.Mutex
+	clients map[int]map[*wsClient]struct{} // ключ - user_id
+}
+
+func newDirectHub() *directHub {
+	return &directHub{clients: make(map[int]map[*wsClient]struct{})}
+}
+
+func (h *directHub) join(userID int, c *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[*wsClient]struct{})
+	}
+	h.clients[userID][c] = struct{}{}
+}
+
+func (h *directHub) leave(userID int, c *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients[userID], c)
+	close(c.send)
+}
+
+// send - пакет всем подключениям пользователя
+func (h *directHub) send(userID int, packet any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients[userID] {
+		c.push(packet)
+	}
+}
+
+// directRequest - пакет клиента, тип задается полем type
+type directRequest struct {
+	Type    string `json:"type"`
+	To      int    `json:"to_id"`   // Send
+	Content string `json:"content"` // Send
+	With    int    `json:"with_id"` // HistoryRequest, Read
+	Before  string `json:"before"`  // HistoryRequest
+	Limit   int    `json:"limit"`   // HistoryRequest
+}
+
+// addDirect - сохранение сообщения, непрочитанное для получателя. Вызывается под блокировкой.
+func (s *Store) addDirect(msg direct.Message) direct.Message {
+	s.nextDirectID++
+	if msg.Id == "" {
+		msg.Id = fmt.Sprintf("d%023x", s.nextDirectID)
+	}
+	msg.Type = direct.TypeMessage
+	s.direct = append(s.direct, msg)
+	if s.directUnread[msg.ToId] == nil {
+		s.directUnread[msg.ToId] = make(map[int]int)
+	}
+	s.directUnread[msg.ToId][msg.FromId]++
+	return msg
+}
+
+// directConversations - переписки пользователя, последние первыми. Вызывается под блокировкой.
+func (s *Store) directConversations(userID int) []direct.Conversation {
+	last := make(map[int]direct.Message)
+	for _, msg := range s.direct {
+		if msg.FromId == userID || msg.ToId == userID {
+			peer, _ := msg.Peer(userID)
+			last[peer] = msg
+		}
+	}
+
+	items := []direct.Conversation{}
+	for peer, msg := range last {
+		_, name := msg.Peer(userID)
+		items = append(items, direct.Conversation{UserId: peer, Username: name, Unread: s.directUnread[userID][peer], Last: msg})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Last.Timestamp > items[j].Last.Timestamp })
+	return items
+}
+
+// directHistory - до limit сообщений переписки userID и with старше before, старые первыми. Вызывается под блокировкой.
+func (s *Store) directHistory(userID, with int, before string, limit int) []direct.Message {
+	var messages []direct.Message
+	for _, msg := range s.direct {
+		if msg.Id == before {
+			break
+		}
+		if (msg.FromId == userID && msg.ToId == with) || (msg.FromId == with && msg.ToId == userID) {
+			messages = append(messages, msg)
+		}
+	}
+	if limit <= 0 || limit > chatHistoryLimit {
+		limit = chatHistoryLimit
+	}
+	return append([]direct.Message{}, messages[max(len(messages)-limit, 0):]...)
+}
+
+// registerDirect - websocket личных сообщений
+func (s *Server) registerDirect() {
+	s.mux.HandleFunc("GET /api/v1/direct/ws/direct/{user_id}", s.handleDirect)
+}
+
+// handleDirect - личные сообщения пользователя.
+// Подключиться можно только со своим access token. Первым пакетом отправляется список переписок,
+// сообщение доставляется всем подключениям автора и получателя.
+func (s *Server) handleDirect(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathInt(r, "user_id")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tokenID, err := s.tokenUser(r, false)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if tokenID != userID {
+		http.Error(w, "токен принадлежит другому пользователю", http.StatusForbidden)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.opts.Logger.Printf("direct: ошибка upgrade: %v", err)
+		return
+	}
+	client := newWSClient(conn)
+
+	s.store.mu.Lock()
+	client.push(direct.Conversations{Type: direct.TypeConversations, Items: s.store.directConversations(userID)})
+	s.store.mu.Unlock()
+
+	s.direct.join(userID, client)
+	defer s.direct.leave(userID, client)
+
+	for {
+		var req directRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		switch req.Type {
+		case direct.TypeSend:
+			s.directSend(client, userID, req)
+		case direct.TypeHistory:
+			s.store.mu.Lock()
+			history := s.store.directHistory(userID, req.With, req.Before, req.Limit)
+			s.store.mu.Unlock()
+			client.push(direct.History{Type: direct.TypeHistory, With: req.With, Data: history})
+		case direct.TypeRead:
+			s.store.mu.Lock()
+			delete(s.store.directUnread[userID], req.With)
+			s.store.mu.Unlock()
+		}
+	}
+}
+
+// directSend - сохранение сообщения и доставка автору и получателю
+func (s *Server) directSend(client *wsClient, userID int, req directRequest) {
+	s.store.mu.Lock()
+	from, to := s.store.users[userID], s.store.users[req.To]
+	var err string
+	switch {
+	case to == nil:
+		err = fmt.Sprintf("пользователь %d не найден", req.To)
+	case to.ID == userID:
+		err = "нельзя написать самому себе"
+	case req.Content == "" || utf8.RuneCountInString(req.Content) > directMessageLimit:
+		err = fmt.Sprintf("сообщение должно быть от 1 до %d символов", directMessageLimit)
+	}
+	if err != "" {
+		s.store.mu.Unlock()
+		client.push(direct.Error{Type: direct.TypeError, Message: err})
+		return
+	}
+	msg := s.store.addDirect(direct.Message{
+		FromId:    userID,
+		FromName:  from.Username,
+		ToId:      to.ID,
+		ToName:    to.Username,
+		Content:   req.Content,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+	})
+	s.store.mu.Unlock()
+
+	s.direct.send(userID, msg)
+	s.direct.send(to.ID, msg)
+}
@@ -0,0 +1,794 @@
+package mockserver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/notify"
+)
+
+// guildsPrefix - префикс сервиса гильдий в окружении local
+const guildsPrefix = "guild/"
+
+const (
+	warPendingTTL = 24 * time.Hour // время на подтверждение объявленной войны
+	warDuration   = 48 * time.Hour // продолжительность войны после подтверждения
+)
+
+// guildRoute - шаблон маршрута из константы пути guilds/models.go
+func guildRoute(method, format string, names ...string) string {
+	return pathRoute(method, guildsPrefix, format, names...)
+}
+
+// registerGuilds - маршруты сервиса гильдий
+func (s *Server) registerGuilds() {
+	s.mux.HandleFunc(guildRoute("GET", guilds.PathGetMemberByUserID, "user_id"), s.handleGetMember)
+	s.mux.HandleFunc(guildRoute("GET", guilds.PathGetGuildByTag, "tag"), s.handleGetGuild)
+	s.mux.HandleFunc(guildRoute("GET", guilds.PathGetGuilds), withETag(s.handleListGuilds))
+	s.mux.HandleFunc(guildRoute("POST", guilds.PathCreateGuild), s.handleCreateGuild)
+	s.mux.HandleFunc(guildRoute("PATCH", guilds.PathEditGuild, "tag"), s.handleEditGuild)
+	s.mux.HandleFunc(guildRoute("DELETE", guilds.PathDeleteGuild, "tag"), s.handleDeleteGuild)
+
+	s.mux.HandleFunc(guildRoute("POST", guilds.PathSendJoinRequest, "tag"), s.handleSendJoinRequest)
+	s.mux.HandleFunc(guildRoute("GET", guilds.PathGetJoinRequests, "tag"), s.handleGetJoinRequests)
+	s.mux.HandleFunc(guildRoute("POST", guilds.PathApplyJoinRequest, "tag", "user_id"), s.handleApplyJoinRequest)
+	s.mux.HandleFunc(guildRoute("DELETE", guilds.PathCancelJoinRequest, "tag", "user_id"), s.handleCancelJoinRequest)
+
+	s.mux.HandleFunc(guildRoute("GET", guilds.PathGetGuildMembers, "tag"), s.handleGetMembers)
+	s.mux.HandleFunc(guildRoute("PATCH", guilds.PathEditMember, "tag", "user_id"), s.handleEditMember)
+	s.mux.HandleFunc(guildRoute("DELETE", guilds.PathDeleteMember, "tag", "user_id"), s.handleDeleteMember)
+	s.mux.HandleFunc(guildRoute("DELETE", guilds.PathExitGuild, "tag"), s.handleExitGuild)
+
+	s.mux.HandleFunc(guildRoute("POST", guilds.PathDeclareWar), s.handleDeclareWar)
+	s.mux.HandleFunc(guildRoute("POST", guilds.PathConfirmWar, "war_id"), s.handleConfirmWar)
+	s.mux.HandleFunc(guildRoute("POST", guilds.PathCancelWar, "war_id"), s.handleCancelWar)
+	s.mux.HandleFunc(guildRoute("GET", guilds.PathListGuildWars), s.handleListWars)
+
+	if !s.opts.NoAuditLog {
+		s.mux.HandleFunc(guildRoute("GET", guilds.PathGetAuditLog, "tag"), s.handleGetAuditLog)
+	}
+}
+
+// guildOK - успешный ответ сервиса гильдий в обертке {value: ...}
+func guildOK(w http.ResponseWriter, value any) {
+	writeJSON(w, http.StatusOK, struct {
+		guilds.BaseResponse
+		Value any `json:"value"`
+	}{guilds.BaseResponse{Status: true}, value})
+}
+
+func guildError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, guilds.BaseResponse{Error: err.Error(), ErrorCode: status})
+}
+
+var (
+	errGuildNotFound  = errors.New("гильдия не найдена")
+	errMemberNotFound = errors.New("участник не найден")
+	errForbidden      = errors.New("недостаточно прав")
+)
+
+// actorParam - ID действующего пользователя из параметра пути или запроса name.
+// Параметр должен совпадать с владельцем токена.
+func (s *Server) actorParam(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	tokenUserID, err := s.tokenUser(r, false)
+	if err != nil {
+		guildError(w, http.StatusUnauthorized, err)
+		return 0, false
+	}
+	raw := r.PathValue(name)
+	if raw == "" {
+		raw = r.URL.Query().Get(name)
+	}
+	userID, err := strconv.Atoi(raw)
+	if err != nil {
+		guildError(w, http.StatusBadRequest, fmt.Errorf("параметр %s должен быть числом", name))
+		return 0, false
+	}
+	if userID != tokenUserID {
+		guildError(w, http.StatusForbidden, errForbidden)
+		return 0, false
+	}
+	return userID, true
+}
+
+func (s *Server) handleGetMember(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathInt(r, "user_id")
+	if err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	member, ok := s.store.members[userID]
+	if !ok {
+		guildError(w, http.StatusNotFound, errMemberNotFound)
+		return
+	}
+	resp := s.store.memberResponse(member)
+	guildOK(w, &resp)
+}
+
+func (s *Server) handleGetGuild(w http.ResponseWriter, r *http.Request) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	guild := s.store.guildByTag(r.PathValue("tag"))
+	if guild == nil {
+		guildError(w, http.StatusNotFound, errGuildNotFound)
+		return
+	}
+	resp := s.store.guildResponse(guild)
+	guildOK(w, &resp)
+}
+
+func (s *Server) handleListGuilds(w http.ResponseWriter, r *http.Request) {
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := queryInt(r, "limit", 10)
+	if err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	all := make([]*Guild, 0, len(s.store.guilds))
+	for _, g := range s.store.guilds {
+		all = append(all, g)
+	}
+	sortBy(all, func(g *Guild) int { return g.ID })
+
+	items := []guilds.GuildResponse{}
+	for _, g := range page(all, offset, limit) {
+		items = append(items, s.store.guildResponse(g))
+	}
+	guildOK(w, &guilds.GuildPagination{Items: items, TotalItems: len(all), TotalPages: pages(len(all), limit)})
+}
+
+func (s *Server) handleCreateGuild(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.actorParam(w, r, "user_id")
+	if !ok {
+		return
+	}
+	var req guilds.CreateGuildRequest
+	if err := readJSON(r, &req); err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Tag == "" || req.Title == "" {
+		guildError(w, http.StatusBadRequest, errors.New("не заданы тег или название"))
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	if _, ok := s.store.members[userID]; ok {
+		guildError(w, http.StatusConflict, errors.New("пользователь уже состоит в гильдии"))
+		return
+	}
+	if s.store.guildByTag(req.Tag) != nil {
+		guildError(w, http.StatusConflict, errors.New("тег уже занят"))
+		return
+	}
+
+	s.store.nextGuildID++
+	guild := &Guild{
+		ID:          s.store.nextGuildID,
+		Tag:         req.Tag,
+		Title:       req.Title,
+		Description: req.Description,
+		OwnerID:     userID,
+		MaxMembers:  50,
+	}
+	s.store.guilds[guild.ID] = guild
+	s.store.members[userID] = &Member{UserID: userID, GuildID: guild.ID, RoleID: RoleOwner}
+
+	resp := s.store.guildResponse(guild)
+	guildOK(w, &resp)
+}
+
+// ownedGuild - гильдия по тегу из пути, если действующий пользователь ее владелец.
+// Вызывается под блокировкой.
+func (s *Server) ownedGuild(w http.ResponseWriter, r *http.Request, userID int) *Guild {
+	guild := s.store.guildByTag(r.PathValue("tag"))
+	if guild == nil {
+		guildError(w, http.StatusNotFound, errGuildNotFound)
+		return nil
+	}
+	if guild.OwnerID != userID {
+		guildError(w, http.StatusForbidden, errForbidden)
+		return nil
+	}
+	return guild
+}
+
+func (s *Server) handleEditGuild(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.actorParam(w, r, "user_id")
+	if !ok {
+		return
+	}
+	var req guilds.EditGuildRequest
+	if err := readJSON(r, &req); err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	guild := s.ownedGuild(w, r, userID)
+	if guild == nil {
+		return
+	}
+	if req.Title != "" {
+		guild.Title = req.Title
+	}
+	if req.Description != "" {
+		guild.Description = req.Description
+	}
+
+	resp := s.store.guildResponse(guild)
+	guildOK(w, &resp)
+}
+
+func (s *Server) handleDeleteGuild(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.actorParam(w, r, "user_id")
+	if !ok {
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	guild := s.ownedGuild(w, r, userID)
+	if guild == nil {
+		return
+	}
+
+	for _, m := range s.store.guildMembers(guild.ID) {
+		delete(s.store.members, m.UserID)
+	}
+	s.store.joinRequests = filter(s.store.joinRequests, func(req JoinRequest) bool { return req.GuildID != guild.ID })
+	delete(s.store.guilds, guild.ID)
+	delete(s.store.chat, guild.ID)
+
+	guildOK(w, nil)
+}
+
+func (s *Server) handleSendJoinRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.actorParam(w, r, "user_id")
+	if !ok {
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	guild := s.store.guildByTag(r.PathValue("tag"))
+	if guild == nil {
+		guildError(w, http.StatusNotFound, errGuildNotFound)
+		return
+	}
+	if _, ok := s.store.members[userID]; ok {
+		guildError(w, http.StatusConflict, errors.New("пользователь уже состоит в гильдии"))
+		return
+	}
+	for _, req := range s.store.joinRequests {
+		if req.UserID == userID && req.GuildID == guild.ID {
+			guildError(w, http.StatusConflict, errors.New("заявка уже отправлена"))
+			return
+		}
+	}
+
+	req := JoinRequest{UserID: userID, GuildID: guild.ID, CreatedAt: time.Now()}
+	s.store.joinRequests = append(s.store.joinRequests, req)
+
+	event := notify.Event{Kind: notify.KindJoinRequest, UserID: userID, CreatedAt: req.CreatedAt}
+	if u := s.store.users[userID]; u != nil {
+		event.UserName = u.Username
+	}
+	s.notifyManagers(guild, event)
+	guildOK(w, nil)
+}
+
+// managedGuild - гильдия по тегу из пути, если действующий пользователь в ней владелец или офицер.
+// Вызывается под блокировкой.
+func (s *Server) managedGuild(w http.ResponseWriter, r *http.Request, userID int) (*Guild, *Member) {
+	guild := s.store.guildByTag(r.PathValue("tag"))
+	if guild == nil {
+		guildError(w, http.StatusNotFound, errGuildNotFound)
+		return nil, nil
+	}
+	actor, ok := s.store.members[userID]
+	if !ok || actor.GuildID != guild.ID || len(s.store.roles[actor.RoleID].RolePromote) == 0 {
+		guildError(w, http.StatusForbidden, errForbidden)
+		return nil, nil
+	}
+	return guild, actor
+}
+
+func (s *Server) handleGetJoinRequests(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.actorParam(w, r, "user_id")
+	if !ok {
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	guild, _ := s.managedGuild(w, r, userID)
+	if guild == nil {
+		return
+	}
+
+	items := []guilds.RequestResponse{}
+	for _, req := range s.store.joinRequests {
+		if req.GuildID != guild.ID {
+			continue
+		}
+		item := guilds.RequestResponse{UserID: req.UserID, CreatedAt: req.CreatedAt.Format(time.RFC3339)}
+		if u := s.store.users[req.UserID]; u != nil {
+			item.UserName = u.Username
+		}
+		items = append(items, item)
+	}
+	guildOK(w, &guilds.RequestPagination{Items: items, TotalItems: len(items), TotalPages: 1})
+}
+
+// joinRequestAction - обработка заявки: в пути ID заявителя, в guild_member_id ID действующего пользователя
+func (s *Server) joinRequestAction(w http.ResponseWriter, r *http.Request, accept bool) {
+	actorID, ok := s.actorParam(w, r, "guild_member_id")
+	if !ok {
+		return
+	}
+	applicantID, err := pathInt(r, "user_id")
+	if err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	guild, _ := s.managedGuild(w, r, actorID)
+	if guild == nil {
+		return
+	}
+
+	found := false
+	s.store.joinRequests = filter(s.store.joinRequests, func(req JoinRequest) bool {
+		match := req.UserID == applicantID && req.GuildID == guild.ID
+		found = found || match
+		return !match
+	})
+	if !found {
+		guildError(w, http.StatusNotFound, errors.New("заявка не найдена"))
+		return
+	}
+
+	if accept {
+		if _, ok := s.store.members[applicantID]; ok {
+			guildError(w, http.StatusConflict, errors.New("пользователь уже состоит в гильдии"))
+			return
+		}
+		s.store.members[applicantID] = &Member{UserID: applicantID, GuildID: guild.ID, RoleID: RoleCabinBoy}
+		s.store.userAudit(guild.ID, actorID, applicantID, guilds.AuditJoinAccepted, "")
+	} else {
+		s.store.userAudit(guild.ID, actorID, applicantID, guilds.AuditJoinDeclined, "")
+	}
+	guildOK(w, nil)
+}
+
+func (s *Server) handleApplyJoinRequest(w http.ResponseWriter, r *http.Request) {
+	s.joinRequestAction(w, r, true)
+}
+
+func (s *Server) handleCancelJoinRequest(w http.ResponseWriter, r *http.Request) {
+	s.joinRequestAction(w, r, false)
+}
+
+func (s *Server) handleGetMembers(w http.ResponseWriter, r *http.Request) {
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := queryInt(r, "limit", 10)
+	if err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	guild := s.store.guildByTag(r.PathValue("tag"))
+	if guild == nil {
+		guildError(w, http.StatusNotFound, errGuildNotFound)
+		return
+	}
+
+	all := s.store.guildMembers(guild.ID)
+	items := []guilds.MemberResponse{}
+	for _, m := range page(all, offset, limit) {
+		items = append(items, s.store.memberResponse(m))
+	}
+	guildOK(w, &guilds.MemberPagination{Items: items, TotalItems: len(all), TotalPages: pages(len(all), limit)})
+}
+
+// managedMember - участник из guild_member_id, которым может управлять действующий пользователь actorID.
+// Вызывается под блокировкой.
+func (s *Server) managedMember(w http.ResponseWriter, r *http.Request, actorID int) (actor, target *Member) {
+	targetID, err := queryInt(r, "guild_member_id", 0)
+	if err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return nil, nil
+	}
+
+	guild, actor := s.managedGuild(w, r, actorID)
+	if guild == nil {
+		return nil, nil
+	}
+	target, ok := s.store.members[targetID]
+	if !ok || target.GuildID != guild.ID {
+		guildError(w, http.StatusNotFound, errMemberNotFound)
+		return nil, nil
+	}
+	if !s.store.canManage(actor.RoleID, target.RoleID) {
+		guildError(w, http.StatusForbidden, errForbidden)
+		return nil, nil
+	}
+	return actor, target
+}
+
+func (s *Server) handleEditMember(w http.ResponseWriter, r *http.Request) {
+	var req guilds.EditMemberRequest
+	if err := readJSON(r, &req); err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	actorID, ok := s.actorParam(w, r, "user_id")
+	if !ok {
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	actor, target := s.managedMember(w, r, actorID)
+	if target == nil {
+		return
+	}
+
+	if req.RoleID != 0 {
+		if !s.store.canManage(actor.RoleID, req.RoleID) {
+			guildError(w, http.StatusForbidden, errForbidden)
+			return
+		}
+		if target.RoleID != req.RoleID {
+			target.RoleID = req.RoleID
+			s.store.userAudit(target.GuildID, actorID, target.UserID, guilds.AuditRoleChanged, s.store.roles[req.RoleID].Title)
+			s.notifyMember(target.UserID, s.store.guilds[target.GuildID], notify.Event{
+				Kind:      notify.KindRoleChanged,
+				RoleTitle: s.store.roles[req.RoleID].Title,
+			})
+		}
+	}
+	if req.UserName != "" {
+		if u := s.store.users[target.UserID]; u != nil {
+			u.Username = req.UserName
+		}
+	}
+	guildOK(w, nil)
+}
+
+func (s *Server) handleDeleteMember(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := s.actorParam(w, r, "user_id")
+	if !ok {
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	_, target := s.managedMember(w, r, actorID)
+	if target == nil {
+		return
+	}
+	delete(s.store.members, target.UserID)
+	s.store.userAudit(target.GuildID, actorID, target.UserID, guilds.AuditMemberKicked, "")
+	s.notifyMember(target.UserID, s.store.guilds[target.GuildID], notify.Event{Kind: notify.KindKicked})
+	guildOK(w, nil)
+}
+
+func (s *Server) handleExitGuild(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.tokenUser(r, false)
+	if err != nil {
+		guildError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	guild := s.store.guildByTag(r.PathValue("tag"))
+	if guild == nil {
+		guildError(w, http.StatusNotFound, errGuildNotFound)
+		return
+	}
+	member, ok := s.store.members[userID]
+	if !ok || member.GuildID != guild.ID {
+		guildError(w, http.StatusNotFound, errMemberNotFound)
+		return
+	}
+	if member.RoleID == RoleOwner {
+		guildError(w, http.StatusConflict, errors.New("владелец не может выйти из гильдии"))
+		return
+	}
+	delete(s.store.members, userID)
+	guildOK(w, nil)
+}
+
+func (s *Server) handleDeclareWar(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.tokenUser(r, false)
+	if err != nil {
+		guildError(w, http.StatusUnauthorized, err)
+		return
+	}
+	var req guilds.DeclareWarRequest
+	if err := readJSON(r, &req); err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	initiator, target := s.store.guilds[req.InitiatorGuildID], s.store.guilds[req.TargetGuildID]
+	if initiator == nil || target == nil {
+		guildError(w, http.StatusNotFound, errGuildNotFound)
+		return
+	}
+	if initiator.OwnerID != userID || req.InitiatorOwnerID != userID {
+		guildError(w, http.StatusForbidden, errForbidden)
+		return
+	}
+	if initiator.ID == target.ID {
+		guildError(w, http.StatusBadRequest, errors.New("нельзя объявить войну своей гильдии"))
+		return
+	}
+
+	now := time.Now()
+	s.store.nextWarID++
+	war := &War{
+		ID:               s.store.nextWarID,
+		InitiatorGuildID: initiator.ID,
+		TargetGuildID:    target.ID,
+		Status:           guilds.WarStatusPending,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		ExpiresAt:        warDeadline(guilds.WarStatusPending, now),
+	}
+	s.store.wars[war.ID] = war
+	// объявление войны видно в журналах обеих гильдий
+	for _, guild := range []*Guild{initiator, target} {
+		s.store.addAudit(guild.ID, userID, guilds.AuditEntry{
+			Action:     guilds.AuditWarDeclared,
+			TargetID:   target.ID,
+			TargetName: target.Tag,
+			Details:    initiator.Tag,
+			CreatedAt:  war.CreatedAt,
+		})
+	}
+	s.notifyMember(target.OwnerID, target, notify.Event{
+		Kind:      notify.KindWarDeclared,
+		WarID:     war.ID,
+		EnemyID:   initiator.ID,
+		EnemyTag:  initiator.Tag,
+		CreatedAt: war.CreatedAt,
+	})
+
+	writeJSON(w, http.StatusOK, guilds.DeclareWarResponse{
+		WarID:            war.ID,
+		InitiatorGuildID: war.InitiatorGuildID,
+		TargetGuildID:    war.TargetGuildID,
+		Status:           war.Status,
+		CreatedAt:        war.CreatedAt,
+	})
+}
+
+// warFromPath - война из пути запроса, вызывается под блокировкой
+func (s *Server) warFromPath(w http.ResponseWriter, r *http.Request) *War {
+	warID, err := pathInt(r, "war_id")
+	if err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return nil
+	}
+	war, ok := s.store.wars[warID]
+	if !ok {
+		guildError(w, http.StatusNotFound, errors.New("война не найдена"))
+		return nil
+	}
+	return war
+}
+
+func (s *Server) handleConfirmWar(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.tokenUser(r, false)
+	if err != nil {
+		guildError(w, http.StatusUnauthorized, err)
+		return
+	}
+	var req guilds.ConfirmWarRequest
+	if err := readJSON(r, &req); err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	war := s.warFromPath(w, r)
+	if war == nil {
+		return
+	}
+	initiator, target := s.store.guilds[war.InitiatorGuildID], s.store.guilds[war.TargetGuildID]
+	if target == nil || initiator == nil || target.OwnerID != userID || req.TargetOwnerID != userID {
+		guildError(w, http.StatusForbidden, errForbidden)
+		return
+	}
+	if war.Status != guilds.WarStatusPending {
+		guildError(w, http.StatusConflict, fmt.Errorf("война в статусе %s", war.Status))
+		return
+	}
+
+	war.Status = guilds.WarStatusActive
+	war.UpdatedAt = time.Now()
+	war.ExpiresAt = warDeadline(war.Status, war.UpdatedAt)
+	writeJSON(w, http.StatusOK, guilds.ConfirmWarResponse{
+		WarID:            war.ID,
+		InitiatorGuildID: war.InitiatorGuildID,
+		TargetGuildID:    war.TargetGuildID,
+		Status:           war.Status,
+		UpdatedAt:        war.UpdatedAt,
+		InitiatorOwnerID: initiator.OwnerID,
+		TargetOwnerID:    target.OwnerID,
+	})
+}
+
+func (s *Server) handleCancelWar(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.tokenUser(r, false)
+	if err != nil {
+		guildError(w, http.StatusUnauthorized, err)
+		return
+	}
+	var req guilds.CancelWarRequest
+	if err := readJSON(r, &req); err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	war := s.warFromPath(w, r)
+	if war == nil {
+		return
+	}
+	initiator, target := s.store.guilds[war.InitiatorGuildID], s.store.guilds[war.TargetGuildID]
+	if initiator == nil || target == nil || req.OwnerID != userID || (initiator.OwnerID != userID && target.OwnerID != userID) {
+		guildError(w, http.StatusForbidden, errForbidden)
+		return
+	}
+	if war.Status != guilds.WarStatusPending && war.Status != guilds.WarStatusActive {
+		guildError(w, http.StatusConflict, fmt.Errorf("война в статусе %s", war.Status))
+		return
+	}
+
+	// отказ от неподтвержденной войны - отклонение, остальное - отмена
+	if war.Status == guilds.WarStatusPending && target.OwnerID == userID {
+		war.Status = guilds.WarStatusDeclined
+	} else {
+		war.Status = guilds.WarStatusCanceled
+	}
+	war.UpdatedAt = time.Now()
+	writeJSON(w, http.StatusOK, guilds.CancelWarResponse{
+		WarID:            war.ID,
+		Status:           war.Status,
+		CancelledBy:      userID,
+		CancelledAt:      war.UpdatedAt,
+		InitiatorGuildID: war.InitiatorGuildID,
+		TargetGuildID:    war.TargetGuildID,
+		InitiatorOwnerID: initiator.OwnerID,
+		TargetOwnerID:    target.OwnerID,
+	})
+}
+
+func (s *Server) handleListWars(w http.ResponseWriter, r *http.Request) {
+	if _, err := s.tokenUser(r, false); err != nil {
+		guildError(w, http.StatusUnauthorized, err)
+		return
+	}
+	query := r.URL.Query()
+	guildID, err := queryInt(r, "guild_id", 0)
+	if err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+	pageNum, err := queryInt(r, "page", 1)
+	if err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+	pageSize, err := queryInt(r, "page_size", 10)
+	if err != nil {
+		guildError(w, http.StatusBadRequest, err)
+		return
+	}
+	isInitiator, isTarget := query.Get("is_initiator"), query.Get("is_target")
+	status := query.Get("status")
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	s.expireWars(time.Now())
+
+	var all []guilds.GuildWarItem
+	for _, war := range s.store.wars {
+		initiator, target := war.InitiatorGuildID == guildID, war.TargetGuildID == guildID
+		if guildID != 0 && !initiator && !target {
+			continue
+		}
+		if isInitiator != "" && strconv.FormatBool(initiator) != isInitiator {
+			continue
+		}
+		if isTarget != "" && strconv.FormatBool(target) != isTarget {
+			continue
+		}
+		if status != "" && string(war.Status) != status {
+			continue
+		}
+		all = append(all, guilds.GuildWarItem{
+			ID:               war.ID,
+			InitiatorGuildID: war.InitiatorGuildID,
+			TargetGuildID:    war.TargetGuildID,
+			Status:           war.Status,
+			InitiatorScore:   war.InitiatorScore,
+			TargetScore:      war.TargetScore,
+			CreatedAt:        war.CreatedAt,
+			UpdatedAt:        war.UpdatedAt,
+			ExpiresAt:        war.ExpiresAt,
+		})
+	}
+	sortBy(all, func(w guilds.GuildWarItem) int { return w.ID })
+
+	writeJSON(w, http.StatusOK, guilds.GuildWarListResponse{
+		Page:       pageNum,
+		PageSize:   pageSize,
+		Total:      len(all),
+		TotalPages: pages(len(all), pageSize),
+		Results:    page(all, (pageNum-1)*pageSize, pageSize),
+	})
+}
+
+// warDeadline - срок войны в статусе status, отсчитанный от from
+func warDeadline(status guilds.WarStatus, from time.Time) time.Time {
+	if status == guilds.WarStatusActive {
+		return from.Add(warDuration)
+	}
+	return from.Add(warPendingTTL)
+}
+
+// expireWars - завершение войн с истекшим сроком, вызывается под блокировкой.
+// Неподтвержденная война истекает, начатая - завершается с текущим счетом.
+func (s *Server) expireWars(now time.Time) {
+	for _, war := range s.store.wars {
+		if now.Before(war.ExpiresAt) {
+			continue
+		}
+		switch war.Status {
+		case guilds.WarStatusPending:
+			war.Status = guilds.WarStatusExpired
+		case guilds.WarStatusActive:
+			war.Status = guilds.WarStatusFinished
+		default:
+			continue
+		}
+		war.UpdatedAt = war.ExpiresAt
+	}
+}
@@ -0,0 +1,28 @@
+package mockserver
+
+import (
+	"net/http"
+
+	"lesta-start-battleship/cli/internal/api/inventory"
+)
+
+// inventoryPrefix - префикс сервиса инвентаря в окружении local
+const inventoryPrefix = "inventory/"
+
+// registerInventory - маршруты сервиса инвентаря
+func (s *Server) registerInventory() {
+	s.mux.HandleFunc(pathRoute("GET", inventoryPrefix, inventory.UserInventoryPath), s.handleInventory)
+}
+
+func (s *Server) handleInventory(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.tokenUser(r, false)
+	if err != nil {
+		writeJSON(w, http.StatusUnauthorized, inventory.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	items := append([]inventory.InventoryItem{}, s.store.inventories[userID]...)
+	writeJSON(w, http.StatusOK, inventory.UserInventoryResponse{UserID: userID, Items: items})
+}
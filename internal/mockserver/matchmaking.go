@@ -0,0 +1,191 @@
+package mockserver
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	matchmaking "github.com/lesta-battleship/matchmaking/pkg/packets"
+)
+
+// serverPacketID - id отправителя в пакетах сервера
+const serverPacketID = "mockserver"
+
+// matchmakingHub - очереди поиска игры и пользовательские комнаты
+type matchmakingHub struct {
+	mu      sync.Mutex
+	waiting map[string]*wsClient            // тип поиска -> игрок в ожидании соперника
+	rooms   map[string]map[*wsClient]string // id комнаты -> участники и их id
+}
+
+func newMatchmakingHub() *matchmakingHub {
+	return &matchmakingHub{
+		waiting: make(map[string]*wsClient),
+		rooms:   make(map[string]map[*wsClient]string),
+	}
+}
+
+// registerMatchmaking - websocket матчмейкинга: /matchmaking/random, /matchmaking/ranked, /matchmaking/custom
+func (s *Server) registerMatchmaking() {
+	s.mux.HandleFunc("GET /matchmaking/{type}", s.handleMatchmaking)
+}
+
+// playerID - id игрока из sub токена в заголовке Authorization.
+// Клиент матчмейкинга подписывает токен алгоритмом none, поэтому подпись не проверяется.
+func playerID(r *http.Request) string {
+	raw := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(raw, claims); err == nil {
+		if sub, err := claims.GetSubject(); err == nil && sub != "" {
+			return sub
+		}
+	}
+	return rand.Text()
+}
+
+func message(text string) matchmaking.Packet {
+	return matchmaking.NewPlayerMessage(serverPacketID, text)
+}
+
+func (s *Server) handleMatchmaking(w http.ResponseWriter, r *http.Request) {
+	matchType := r.PathValue("type")
+	id := playerID(r)
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.opts.Logger.Printf("matchmaking: ошибка upgrade: %v", err)
+		return
+	}
+	client := newWSClient(conn)
+	defer close(client.send)
+	defer s.matchmaking.leave(client)
+
+	if matchType != "custom" {
+		s.matchmaking.enqueue(matchType, client, s.opts.BotDelay)
+	}
+
+	for {
+		var packet matchmaking.Packet
+		if err := conn.ReadJSON(&packet); err != nil {
+			return
+		}
+
+		switch body := packet.Body.(type) {
+		case *matchmaking.CreateRoom:
+			roomID := s.matchmaking.createRoom(client, id)
+			client.push(message(roomID))
+		case *matchmaking.JoinRoom:
+			if !s.matchmaking.joinRoom(client, id, roomID(body)) {
+				client.push(message("room not found"))
+			}
+		case *matchmaking.PlayerMessage:
+			// сообщения чата комнаты пересылаются соперникам без изменений
+			s.matchmaking.relay(client, packet)
+		case *matchmaking.Disconnect:
+			return
+		}
+	}
+}
+
+// roomID - id комнаты из пакета JoinRoom: первое строковое поле тела пакета
+func roomID(body any) string {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return ""
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return ""
+	}
+	for _, v := range fields {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// enqueue - постановка в очередь поиска.
+// Два игрока в очереди получают id общей игры, одиночному игроку через botDelay подбирается бот.
+func (h *matchmakingHub) enqueue(matchType string, c *wsClient, botDelay time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if opponent, ok := h.waiting[matchType]; ok && opponent != c {
+		delete(h.waiting, matchType)
+		gameID := matchType + "-" + rand.Text()[:8]
+		opponent.push(message(gameID))
+		c.push(message(gameID))
+		return
+	}
+
+	h.waiting[matchType] = c
+	time.AfterFunc(botDelay, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.waiting[matchType] == c {
+			delete(h.waiting, matchType)
+			c.push(message(matchType + "-bot-" + rand.Text()[:8]))
+		}
+	})
+}
+
+func (h *matchmakingHub) createRoom(c *wsClient, id string) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	roomID := rand.Text()[:6]
+	h.rooms[roomID] = map[*wsClient]string{c: id}
+	return roomID
+}
+
+// joinRoom - вход в комнату, всем участникам отправляется id комнаты
+func (h *matchmakingHub) joinRoom(c *wsClient, id, roomID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	room, ok := h.rooms[roomID]
+	if !ok {
+		return false
+	}
+	room[c] = id
+	for member := range room {
+		member.push(message(roomID))
+	}
+	return true
+}
+
+// relay - пакет игрока остальным участникам его комнат
+func (h *matchmakingHub) relay(c *wsClient, packet matchmaking.Packet) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, room := range h.rooms {
+		if _, ok := room[c]; !ok {
+			continue
+		}
+		for member := range room {
+			if member != c {
+				member.push(packet)
+			}
+		}
+	}
+}
+
+// leave - удаление игрока из очередей и комнат
+func (h *matchmakingHub) leave(c *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for matchType, waiting := range h.waiting {
+		if waiting == c {
+			delete(h.waiting, matchType)
+		}
+	}
+	for roomID, room := range h.rooms {
+		delete(room, c)
+		if len(room) == 0 {
+			delete(h.rooms, roomID)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package mockserver
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/notify"
+)
+
+// notifyHub - подключения к уведомлениям гильдий
+type notifyHub struct {
+	mu      sync.Mutex
+	clients map[int]map[*wsClient]struct{} // ключ - user_id
+}
+
+func newNotifyHub() *notifyHub {
+	return &notifyHub{clients: make(map[int]map[*wsClient]struct{})}
+}
+
+func (h *notifyHub) join(userID int, c *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[*wsClient]struct{})
+	}
+	h.clients[userID][c] = struct{}{}
+}
+
+func (h *notifyHub) leave(userID int, c *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients[userID], c)
+	close(c.send)
+}
+
+// send - событие всем подключениям пользователя
+func (h *notifyHub) send(userID int, event notify.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients[userID] {
+		c.push(event)
+	}
+}
+
+// registerNotify - websocket уведомлений гильдий
+func (s *Server) registerNotify() {
+	s.mux.HandleFunc("GET /api/v1/notifications/ws/{user_id}", s.handleNotify)
+}
+
+// handleNotify - уведомления пользователя.
+// Подключиться можно только со своим access token, user_id в адресе должен совпадать с токеном.
+// Отправляются только события, произошедшие после подключения, сообщения клиента игнорируются.
+func (s *Server) handleNotify(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.chatUser(w, r)
+	if !ok {
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.opts.Logger.Printf("notify: ошибка upgrade: %v", err)
+		return
+	}
+	client := newWSClient(conn)
+
+	s.notify.join(userID, client)
+	defer s.notify.leave(userID, client)
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// notifyManagers - событие владельцу и офицерам гильдии, вызывается под блокировкой
+func (s *Server) notifyManagers(guild *Guild, event notify.Event) {
+	event.GuildID, event.GuildTag = guild.ID, guild.Tag
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	for _, m := range s.store.guildMembers(guild.ID) {
+		if len(s.store.roles[m.RoleID].RolePromote) > 0 {
+			s.notify.send(m.UserID, event)
+		}
+	}
+}
+
+// notifyMember - событие участнику гильдии, вызывается под блокировкой
+func (s *Server) notifyMember(userID int, guild *Guild, event notify.Event) {
+	event.GuildID, event.GuildTag = guild.ID, guild.Tag
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	s.notify.send(userID, event)
+}
@@ -0,0 +1,194 @@
+package mockserver
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"lesta-start-battleship/cli/internal/api/scoreboard"
+)
+
+// scoreboardPrefix - префикс сервиса рейтингов в окружении local
+const scoreboardPrefix = "scoreboard/"
+
+// registerScoreboard - маршруты сервиса рейтингов (пути из scoreboard/client.go)
+func (s *Server) registerScoreboard() {
+	s.mux.HandleFunc(pathRoute("GET", scoreboardPrefix, scoreboard.UsersPath), withETag(s.handleUserStats))
+	s.mux.HandleFunc(pathRoute("GET", scoreboardPrefix, scoreboard.GuildsPath), withETag(s.handleGuildStats))
+}
+
+// statsQuery - общие параметры запросов рейтинга
+type statsQuery struct {
+	id      string
+	name    string
+	orderBy string
+	reverse bool
+	limit   int
+	page    int
+}
+
+func parseStatsQuery(r *http.Request) (statsQuery, error) {
+	q := r.URL.Query()
+	query := statsQuery{
+		id:      q.Get("id_like"),
+		name:    strings.ToLower(q.Get("name_ilike")),
+		orderBy: q.Get("order_by"),
+		reverse: q.Get("reverse") == "true",
+	}
+
+	var err error
+	if query.limit, err = queryInt(r, "limit", 10); err != nil {
+		return query, err
+	}
+	if query.page, err = queryInt(r, "page", 1); err != nil {
+		return query, err
+	}
+	if query.page < 1 {
+		query.page = 1
+	}
+	return query, nil
+}
+
+func (q statsQuery) match(id int, name string) bool {
+	if q.id != "" && !strings.Contains(strconv.Itoa(id), q.id) {
+		return false
+	}
+	return q.name == "" || strings.Contains(strings.ToLower(name), q.name)
+}
+
+// positions - места в рейтинге по убыванию значения, 1 - лучший
+func positions[T any](items []T, value func(T) int) map[int]int {
+	sorted := make([]int, len(items))
+	for i := range items {
+		sorted[i] = i
+	}
+	slices.SortStableFunc(sorted, func(a, b int) int {
+		return value(items[b]) - value(items[a])
+	})
+	result := make(map[int]int, len(items))
+	for pos, i := range sorted {
+		result[i] = pos + 1
+	}
+	return result
+}
+
+func (s *Server) handleUserStats(w http.ResponseWriter, r *http.Request) {
+	query, err := parseStatsQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.store.mu.Lock()
+	users := make([]User, 0, len(s.store.users))
+	for _, u := range s.store.users {
+		users = append(users, *u)
+	}
+	s.store.mu.Unlock()
+	sortBy(users, func(u User) int { return u.ID })
+
+	goldPos := positions(users, func(u User) int { return u.Gold })
+	expPos := positions(users, func(u User) int { return u.Experience })
+	ratingPos := positions(users, func(u User) int { return u.Rating })
+	chestPos := positions(users, func(u User) int { return u.ChestsOpened })
+
+	var items []scoreboard.UserStat
+	for i, u := range users {
+		if !query.match(u.ID, u.Username) {
+			continue
+		}
+		items = append(items, scoreboard.UserStat{
+			ID:                    u.ID,
+			Name:                  u.Username,
+			Gold:                  u.Gold,
+			GoldRatingPos:         goldPos[i],
+			Experience:            u.Experience,
+			ExpRatingPos:          expPos[i],
+			Rating:                u.Rating,
+			RatingRatingPos:       ratingPos[i],
+			ChestsOpened:          u.ChestsOpened,
+			ChestsOpenedRatingPos: chestPos[i],
+		})
+	}
+
+	orderUserStats(items, query.orderBy, query.reverse)
+	writeJSON(w, http.StatusOK, scoreboard.UserListResponse{
+		Page:       query.page,
+		PageAmount: pages(len(items), query.limit),
+		Items:      page(items, (query.page-1)*query.limit, query.limit),
+	})
+}
+
+// orderUserStats - сортировка по полю order_by по возрастанию, reverse - по убыванию
+func orderUserStats(items []scoreboard.UserStat, orderBy string, reverse bool) {
+	key := func(s scoreboard.UserStat) int { return s.ID }
+	switch orderBy {
+	case "gold":
+		key = func(s scoreboard.UserStat) int { return s.Gold }
+	case "experience":
+		key = func(s scoreboard.UserStat) int { return s.Experience }
+	case "rating":
+		key = func(s scoreboard.UserStat) int { return s.Rating }
+	case "chest_opened":
+		key = func(s scoreboard.UserStat) int { return s.ChestsOpened }
+	}
+	sortBy(items, key)
+	if reverse {
+		slices.Reverse(items)
+	}
+}
+
+func (s *Server) handleGuildStats(w http.ResponseWriter, r *http.Request) {
+	query, err := parseStatsQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.store.mu.Lock()
+	all := make([]Guild, 0, len(s.store.guilds))
+	counts := make(map[int]int)
+	for _, g := range s.store.guilds {
+		all = append(all, *g)
+		counts[g.ID] = len(s.store.guildMembers(g.ID))
+	}
+	s.store.mu.Unlock()
+	sortBy(all, func(g Guild) int { return g.ID })
+
+	membersPos := positions(all, func(g Guild) int { return counts[g.ID] })
+	victoriesPos := positions(all, func(g Guild) int { return g.WarsVictories })
+
+	var items []scoreboard.GuildStat
+	for i, g := range all {
+		if !query.match(g.ID, g.Title) {
+			continue
+		}
+		items = append(items, scoreboard.GuildStat{
+			ID:                     g.ID,
+			Name:                   g.Title,
+			GuildMembers:           counts[g.ID],
+			GuildMembersRatingPos:  membersPos[i],
+			WarsVictories:          g.WarsVictories,
+			WarsVictoriesRatingPos: victoriesPos[i],
+		})
+	}
+
+	key := func(s scoreboard.GuildStat) int { return s.ID }
+	switch query.orderBy {
+	case "guild_members":
+		key = func(s scoreboard.GuildStat) int { return s.GuildMembers }
+	case "wars_victories":
+		key = func(s scoreboard.GuildStat) int { return s.WarsVictories }
+	}
+	sortBy(items, key)
+	if query.reverse {
+		slices.Reverse(items)
+	}
+
+	writeJSON(w, http.StatusOK, scoreboard.GuildListResponse{
+		Page:       query.page,
+		PageAmount: pages(len(items), query.limit),
+		Items:      page(items, (query.page-1)*query.limit, query.limit),
+	})
+}
@@ -0,0 +1,33 @@
+package mockserver
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+//go:embed seed.json
+var defaultSeed []byte
+
+// DefaultSeed - встроенные начальные данные
+func DefaultSeed() (Seed, error) {
+	return parseSeed(defaultSeed)
+}
+
+// LoadSeed - чтение начальных данных из JSON файла
+func LoadSeed(path string) (Seed, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Seed{}, fmt.Errorf("ошибка чтения начальных данных: %w", err)
+	}
+	return parseSeed(data)
+}
+
+func parseSeed(data []byte) (Seed, error) {
+	var seed Seed
+	if err := json.Unmarshal(data, &seed); err != nil {
+		return Seed{}, fmt.Errorf("ошибка разбора начальных данных: %w", err)
+	}
+	return seed, nil
+}
@@ -0,0 +1,236 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"lesta-start-battleship/cli/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+)
+
+// Options - настройки сервера
+type Options struct {
+	AccessTTL  time.Duration // время жизни access token
+	RefreshTTL time.Duration // время жизни refresh token
+	BotDelay   time.Duration // через сколько игроку в очереди матчмейкинга подбирается бот
+	NoAuditLog bool          // без журнала действий гильдий, как сервер без этого API
+	Logger     *log.Logger
+}
+
+// DefaultOptions - настройки по умолчанию
+func DefaultOptions() Options {
+	return Options{
+		AccessTTL:  15 * time.Minute,
+		RefreshTTL: 24 * time.Hour,
+		BotDelay:   3 * time.Second,
+		Logger:     log.Default(),
+	}
+}
+
+// Server - эмуляция всех сервисов игры: REST API и websocket чата гильдий и матчмейкинга.
+//
+// Маршруты повторяют адреса окружения local:
+// /auth/, /users/ - авторизация; /guild/; /inventory/; /scoreboard/; /shop/;
+// /api/v1/chat/ws/guild/{guild_id}/{user_id} - чат гильдии; /api/v1/chat/ws/lobby/{user_id} - чат лобби;
+// /api/v1/direct/ws/direct/{user_id} - личные сообщения;
+// /api/v1/notifications/ws/{user_id} - уведомления гильдий;
+// /matchmaking/{type} - матчмейкинг.
+type Server struct {
+	store    *Store
+	opts     Options
+	secret   []byte
+	mux      *http.ServeMux
+	upgrader websocket.Upgrader
+
+	chat        *chatHub
+	direct      *directHub
+	notify      *notifyHub
+	matchmaking *matchmakingHub
+	oauth       *oauthDevices
+}
+
+// New - создание сервера над хранилищем store
+func New(store *Store, opts Options) *Server {
+	if opts.Logger == nil {
+		opts.Logger = log.Default()
+	}
+
+	s := &Server{
+		store:    store,
+		opts:     opts,
+		secret:   []byte("mockserver-secret"),
+		mux:      http.NewServeMux(),
+		upgrader: websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }},
+
+		chat:        newChatHub(),
+		direct:      newDirectHub(),
+		notify:      newNotifyHub(),
+		matchmaking: newMatchmakingHub(),
+		oauth:       newOAuthDevices(),
+	}
+
+	s.registerAuth()
+	s.registerGuilds()
+	s.registerInventory()
+	s.registerScoreboard()
+	s.registerShop()
+	s.registerChat()
+	s.registerDirect()
+	s.registerNotify()
+	s.registerMatchmaking()
+
+	return s
+}
+
+// Endpoints - адреса сервисов клиента для сервера, запущенного по адресу baseURL (http://host:port)
+func Endpoints(baseURL string) config.Endpoints {
+	base := strings.TrimSuffix(baseURL, "/")
+	ws := "ws" + strings.TrimPrefix(base, "http")
+	return config.Endpoints{
+		Auth:        base + "/",
+		Guilds:      base + "/" + guildsPrefix,
+		Inventory:   base + "/" + inventoryPrefix,
+		Scoreboard:  base + "/" + scoreboardPrefix,
+		Shop:        base + "/" + shopPrefix,
+		GuildChat:   ws + "/api/v1/chat/",
+		Direct:      ws + "/api/v1/direct/",
+		Matchmaking: ws + "/matchmaking/",
+
+		Notifications: ws + "/api/v1/notifications/",
+	}
+}
+
+// ServeHTTP - обработка запроса с записью в лог
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.opts.Logger.Printf("%s %s", r.Method, r.URL.RequestURI())
+	s.mux.ServeHTTP(w, r)
+}
+
+// errUnauthorized - запрос без действующего access token
+var errUnauthorized = errors.New("не авторизован")
+
+// issueTokens - выдача пары токенов пользователю
+func (s *Server) issueTokens(userID int) (access, refresh string, err error) {
+	now := time.Now()
+	access, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   strconv.Itoa(userID),
+		"exp":   now.Add(s.opts.AccessTTL).Unix(),
+		"iat":   now.Unix(),
+		"roles": []string{"player"},
+	}).SignedString(s.secret)
+	if err != nil {
+		return "", "", err
+	}
+
+	refresh, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": strconv.Itoa(userID),
+		"exp": now.Add(s.opts.RefreshTTL).Unix(),
+		"iat": now.Unix(),
+		"typ": "refresh",
+	}).SignedString(s.secret)
+	if err != nil {
+		return "", "", err
+	}
+
+	return access, refresh, nil
+}
+
+// tokenUser - ID пользователя из токена в заголовке Authorization.
+// refresh - ожидается refresh token, иначе access token.
+func (s *Server) tokenUser(r *http.Request, refresh bool) (int, error) {
+	raw := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	if raw == "" {
+		return 0, errUnauthorized
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(*jwt.Token) (any, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", errUnauthorized, err)
+	}
+
+	if typ, _ := claims["typ"].(string); (typ == "refresh") != refresh {
+		return 0, fmt.Errorf("%w: неверный тип токена", errUnauthorized)
+	}
+
+	sub, _ := claims.GetSubject()
+	userID, err := strconv.Atoi(sub)
+	if err != nil {
+		return 0, fmt.Errorf("%w: некорректный sub", errUnauthorized)
+	}
+
+	s.store.mu.Lock()
+	_, ok := s.store.users[userID]
+	s.store.mu.Unlock()
+	if !ok {
+		return 0, fmt.Errorf("%w: пользователь не найден", errUnauthorized)
+	}
+
+	return userID, nil
+}
+
+// route - шаблон маршрута для относительного пути клиента.
+// Путь со слешем на конце должен совпадать точно, а не как префикс.
+func route(path string) string {
+	if strings.HasSuffix(path, "/") {
+		return "/" + path + "{$}"
+	}
+	return "/" + path
+}
+
+// pathRoute - шаблон маршрута из константы пути клиента сервиса с префиксом prefix.
+// Спецификаторы формата заменяются на именованные параметры names.
+func pathRoute(method, prefix, format string, names ...string) string {
+	args := make([]any, len(names))
+	for i, name := range names {
+		args[i] = "{" + name + "}"
+	}
+	return method + " " + route(prefix+fmt.Sprintf(strings.ReplaceAll(format, "%d", "%s"), args...))
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Printf("mockserver: ошибка записи ответа: %v", err)
+	}
+}
+
+func readJSON(r *http.Request, value any) error {
+	if err := json.NewDecoder(r.Body).Decode(value); err != nil {
+		return fmt.Errorf("некорректное тело запроса: %w", err)
+	}
+	return nil
+}
+
+// pathInt - числовой параметр пути
+func pathInt(r *http.Request, name string) (int, error) {
+	value, err := strconv.Atoi(r.PathValue(name))
+	if err != nil {
+		return 0, fmt.Errorf("параметр %s должен быть числом", name)
+	}
+	return value, nil
+}
+
+// queryInt - числовой параметр запроса со значением по умолчанию
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("параметр %s должен быть числом", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,165 @@
+package mockserver
+
+import (
+	"errors"
+	"net/http"
+
+	"lesta-start-battleship/cli/internal/api/inventory"
+	"lesta-start-battleship/cli/internal/api/shop"
+)
+
+// shopPrefix - префикс магазина в окружении local
+const shopPrefix = "shop/"
+
+// registerShop - маршруты магазина (пути из shop/client.go)
+func (s *Server) registerShop() {
+	s.mux.HandleFunc(pathRoute("GET", shopPrefix, shop.ProductsPath), withETag(s.handleProducts))
+	s.mux.HandleFunc(pathRoute("GET", shopPrefix, shop.ChestsPath), withETag(s.handleChests))
+	s.mux.HandleFunc(pathRoute("GET", shopPrefix, shop.PromotionsPath), withETag(s.handlePromotions))
+	s.mux.HandleFunc(pathRoute("POST", shopPrefix, shop.BuyProductPath, "id"), s.handleBuyProduct)
+	s.mux.HandleFunc(pathRoute("POST", shopPrefix, shop.BuyChestPath, "id"), s.handleBuyChest)
+	s.mux.HandleFunc(pathRoute("POST", shopPrefix, shop.OpenChestPath), s.handleOpenChest)
+}
+
+var errInsufficientFunds = errors.New("недостаточно средств")
+
+func (s *Server) handleProducts(w http.ResponseWriter, r *http.Request) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	writeJSON(w, http.StatusOK, append([]shop.Product{}, s.store.products...))
+}
+
+func (s *Server) handleChests(w http.ResponseWriter, r *http.Request) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	writeJSON(w, http.StatusOK, append([]shop.Chest{}, s.store.chests...))
+}
+
+func (s *Server) handlePromotions(w http.ResponseWriter, r *http.Request) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	writeJSON(w, http.StatusOK, append([]shop.Promotion{}, s.store.promotions...))
+}
+
+// pay - списание стоимости в валюте currency, вызывается под блокировкой
+func pay(u *User, currency string, cost int) error {
+	balance := &u.Gold
+	if currency == "guild_rage" {
+		balance = &u.GuildRage
+	}
+	if *balance < cost {
+		return errInsufficientFunds
+	}
+	*balance -= cost
+	return nil
+}
+
+func (s *Server) handleBuyProduct(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.tokenUser(r, false)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	id, err := pathInt(r, "id")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	for _, p := range s.store.products {
+		if p.ID != id {
+			continue
+		}
+		if err := pay(s.store.users[userID], p.Currency, p.Cost); err != nil {
+			http.Error(w, err.Error(), http.StatusPaymentRequired)
+			return
+		}
+		s.store.addInventoryItem(userID, inventory.InventoryItem{ItemID: p.ID, Name: p.Name, Description: p.Description, Amount: 1})
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	http.Error(w, "товар не найден", http.StatusNotFound)
+}
+
+func (s *Server) handleBuyChest(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.tokenUser(r, false)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	id, err := pathInt(r, "id")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	for _, c := range s.store.chests {
+		if c.ID != id {
+			continue
+		}
+		if err := pay(s.store.users[userID], c.Currency, c.Cost); err != nil {
+			http.Error(w, err.Error(), http.StatusPaymentRequired)
+			return
+		}
+		s.store.addInventoryItem(userID, inventory.InventoryItem{ItemID: chestItemOffset + c.ID, Name: c.Name, Description: "сундук", Amount: 1})
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	http.Error(w, "сундук не найден", http.StatusNotFound)
+}
+
+func (s *Server) handleOpenChest(w http.ResponseWriter, r *http.Request) {
+	userID, err := s.tokenUser(r, false)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	var req shop.OpenChestRequest
+	if err := readJSON(r, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Amount <= 0 {
+		req.Amount = 1
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	var chest *shop.Chest
+	for i := range s.store.chests {
+		if s.store.chests[i].ID == req.ChestID {
+			chest = &s.store.chests[i]
+		}
+	}
+	if chest == nil {
+		http.Error(w, "сундук не найден", http.StatusNotFound)
+		return
+	}
+
+	items := s.store.inventories[userID]
+	for i := range items {
+		if items[i].ItemID != chestItemOffset+chest.ID {
+			continue
+		}
+		if items[i].Amount < req.Amount {
+			break
+		}
+		items[i].Amount -= req.Amount
+		if items[i].Amount == 0 {
+			s.store.inventories[userID] = append(items[:i], items[i+1:]...)
+		}
+
+		user := s.store.users[userID]
+		user.Gold += chest.Gold * req.Amount
+		user.Experience += chest.Experience * req.Amount
+		user.ChestsOpened += req.Amount
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	http.Error(w, "недостаточно сундуков в инвентаре", http.StatusConflict)
+}
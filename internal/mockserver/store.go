@@ -0,0 +1,270 @@
+package mockserver
+
+import (
+	"sync"
+	"time"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/api/inventory"
+	"lesta-start-battleship/cli/internal/api/shop"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/direct"
+	guildPackets "lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+)
+
+// ID ролей гильдии, как их использует клиент
+const (
+	RoleOwner    = 1
+	RoleCabinBoy = 2
+	RoleOfficer  = 3
+)
+
+// chestItemOffset - сдвиг item_id сундуков в инвентаре, чтобы они не пересекались с товарами
+const chestItemOffset = 1000
+
+// User - пользователь со статистикой для рейтингов
+type User struct {
+	ID           int    `json:"id"`
+	Username     string `json:"username"`
+	Email        string `json:"email"`
+	Password     string `json:"password"`
+	Gold         int    `json:"gold"`
+	GuildRage    int    `json:"guild_rage"`
+	Experience   int    `json:"experience"`
+	Rating       int    `json:"rating"`
+	ChestsOpened int    `json:"chest_opened"`
+}
+
+// Guild - гильдия
+type Guild struct {
+	ID            int    `json:"id"`
+	Tag           string `json:"tag"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	OwnerID       int    `json:"owner_id"`
+	MaxMembers    int    `json:"max_members"`
+	WarsVictories int    `json:"wars_victories"`
+}
+
+// Member - участие пользователя в гильдии
+type Member struct {
+	UserID  int `json:"user_id"`
+	GuildID int `json:"guild_id"`
+	RoleID  int `json:"role_id"`
+}
+
+// JoinRequest - заявка на вступление
+type JoinRequest struct {
+	UserID    int       `json:"user_id"`
+	GuildID   int       `json:"guild_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// War - война гильдий
+type War struct {
+	ID               int              `json:"id"`
+	InitiatorGuildID int              `json:"initiator_guild_id"`
+	TargetGuildID    int              `json:"target_guild_id"`
+	Status           guilds.WarStatus `json:"status"`
+	InitiatorScore   int              `json:"initiator_score"`
+	TargetScore      int              `json:"target_score"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+	ExpiresAt        time.Time        `json:"expires_at"` // пустое значение в начальных данных - отсчет от запуска сервера
+}
+
+// Seed - начальные данные сервера
+type Seed struct {
+	Users        []User                            `json:"users"`
+	Roles        []guilds.Role                     `json:"roles"`
+	Guilds       []Guild                           `json:"guilds"`
+	Members      []Member                          `json:"members"`
+	JoinRequests []JoinRequest                     `json:"join_requests"`
+	Wars         []War                             `json:"wars"`
+	Audit        []AuditRecord                     `json:"audit"`
+	Products     []shop.Product                    `json:"products"`
+	Chests       []shop.Chest                      `json:"chests"`
+	Promotions   []shop.Promotion                  `json:"promotions"`
+	Inventories  []inventory.UserInventoryResponse `json:"inventories"`
+	Chat         []guildPackets.ChatHistoryMessage `json:"chat"`
+	Direct       []direct.Message                  `json:"direct"` // личные сообщения, в начальных данных не прочитаны
+}
+
+// Store - хранилище данных сервера в памяти.
+// Все поля защищены mu, обработчики работают с ними через методы или под блокировкой.
+type Store struct {
+	mu sync.Mutex
+
+	users        map[int]*User
+	roles        map[int]guilds.Role
+	guilds       map[int]*Guild
+	members      map[int]*Member // ключ - user_id, пользователь состоит не более чем в одной гильдии
+	joinRequests []JoinRequest
+	wars         map[int]*War
+	audit        []AuditRecord // журнал действий всех гильдий в порядке записи
+	products     []shop.Product
+	chests       []shop.Chest
+	promotions   []shop.Promotion
+	inventories  map[int][]inventory.InventoryItem
+	chat         map[int][]guildPackets.ChatHistoryMessage // ключ - guild_id
+	direct       []direct.Message                          // личные сообщения в порядке отправки
+	directUnread map[int]map[int]int                       // получатель -> автор -> непрочитанных
+
+	nextUserID   int
+	nextGuildID  int
+	nextWarID    int
+	nextChatID   int
+	nextDirectID int
+	nextAuditID  int
+}
+
+// NewStore - создание хранилища из начальных данных
+func NewStore(seed Seed) *Store {
+	s := &Store{
+		users:        make(map[int]*User),
+		roles:        make(map[int]guilds.Role),
+		guilds:       make(map[int]*Guild),
+		members:      make(map[int]*Member),
+		joinRequests: append([]JoinRequest(nil), seed.JoinRequests...),
+		wars:         make(map[int]*War),
+		audit:        append([]AuditRecord(nil), seed.Audit...),
+		products:     append([]shop.Product(nil), seed.Products...),
+		chests:       append([]shop.Chest(nil), seed.Chests...),
+		promotions:   append([]shop.Promotion(nil), seed.Promotions...),
+		inventories:  make(map[int][]inventory.InventoryItem),
+		chat:         make(map[int][]guildPackets.ChatHistoryMessage),
+		directUnread: make(map[int]map[int]int),
+	}
+
+	for _, u := range seed.Users {
+		u := u
+		s.users[u.ID] = &u
+		s.nextUserID = max(s.nextUserID, u.ID)
+	}
+	for _, r := range seed.Roles {
+		s.roles[r.ID] = r
+	}
+	for _, g := range seed.Guilds {
+		g := g
+		s.guilds[g.ID] = &g
+		s.nextGuildID = max(s.nextGuildID, g.ID)
+	}
+	for _, m := range seed.Members {
+		m := m
+		s.members[m.UserID] = &m
+	}
+	now := time.Now()
+	for _, w := range seed.Wars {
+		w := w
+		if w.ExpiresAt.IsZero() {
+			w.ExpiresAt = warDeadline(w.Status, now)
+		}
+		s.wars[w.ID] = &w
+		s.nextWarID = max(s.nextWarID, w.ID)
+	}
+	for _, rec := range seed.Audit {
+		s.nextAuditID = max(s.nextAuditID, rec.ID)
+	}
+	for _, inv := range seed.Inventories {
+		s.inventories[inv.UserID] = append([]inventory.InventoryItem(nil), inv.Items...)
+	}
+	for _, msg := range seed.Chat {
+		s.chat[msg.GuildId] = append(s.chat[msg.GuildId], msg)
+		s.nextChatID++
+	}
+	for _, msg := range seed.Direct {
+		s.addDirect(msg)
+	}
+
+	return s
+}
+
+// userByName - поиск пользователя по имени, вызывается под блокировкой
+func (s *Store) userByName(username string) *User {
+	for _, u := range s.users {
+		if u.Username == username {
+			return u
+		}
+	}
+	return nil
+}
+
+// guildByTag - поиск гильдии по тегу, вызывается под блокировкой
+func (s *Store) guildByTag(tag string) *Guild {
+	for _, g := range s.guilds {
+		if g.Tag == tag {
+			return g
+		}
+	}
+	return nil
+}
+
+// guildMembers - участники гильдии, отсортированные по user_id, вызывается под блокировкой
+func (s *Store) guildMembers(guildID int) []*Member {
+	var result []*Member
+	for _, m := range s.members {
+		if m.GuildID == guildID {
+			result = append(result, m)
+		}
+	}
+	sortBy(result, func(m *Member) int { return m.UserID })
+	return result
+}
+
+// memberResponse - участник в формате API, вызывается под блокировкой
+func (s *Store) memberResponse(m *Member) guilds.MemberResponse {
+	resp := guilds.MemberResponse{
+		UserID:  m.UserID,
+		GuildID: m.GuildID,
+		Role:    s.roles[m.RoleID],
+	}
+	if u := s.users[m.UserID]; u != nil {
+		resp.UserName = u.Username
+	}
+	if g := s.guilds[m.GuildID]; g != nil {
+		resp.GuildTag = g.Tag
+	}
+	return resp
+}
+
+// guildResponse - гильдия в формате API, вызывается под блокировкой
+func (s *Store) guildResponse(g *Guild) guilds.GuildResponse {
+	return guilds.GuildResponse{
+		ID:          g.ID,
+		Title:       g.Title,
+		Description: g.Description,
+		Tag:         g.Tag,
+		OwnerID:     g.OwnerID,
+		IsActive:    true,
+		IsFull:      g.MaxMembers > 0 && len(s.guildMembers(g.ID)) >= g.MaxMembers,
+	}
+}
+
+// canManage - может ли участник с ролью actorRole управлять участником с ролью targetRole
+func (s *Store) canManage(actorRole, targetRole int) bool {
+	for _, id := range s.roles[actorRole].RolePromote {
+		if id == targetRole {
+			return true
+		}
+	}
+	return false
+}
+
+// addInventoryItem - добавление предметов в инвентарь, вызывается под блокировкой
+func (s *Store) addInventoryItem(userID int, item inventory.InventoryItem) {
+	items := s.inventories[userID]
+	for i := range items {
+		if items[i].ItemID == item.ItemID {
+			items[i].Amount += item.Amount
+			return
+		}
+	}
+	s.inventories[userID] = append(items, item)
+}
+
+// removeUser - удаление пользователя и всех связанных данных, вызывается под блокировкой
+func (s *Store) removeUser(userID int) {
+	delete(s.users, userID)
+	delete(s.members, userID)
+	delete(s.inventories, userID)
+	s.joinRequests = filter(s.joinRequests, func(r JoinRequest) bool { return r.UserID != userID })
+}
@@ -0,0 +1,88 @@
+package mockserver
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+func sortBy[T any, K cmp.Ordered](items []T, key func(T) K) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	})
+}
+
+func filter[T any](items []T, keep func(T) bool) []T {
+	result := items[:0]
+	for _, item := range items {
+		if keep(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// page - срез элементов страницы по смещению и размеру
+func page[T any](items []T, offset, limit int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return items[offset:end]
+}
+
+// pages - количество страниц
+func pages(total, limit int) int {
+	if limit <= 0 || total == 0 {
+		return 1
+	}
+	return (total + limit - 1) / limit
+}
+
+// bufferedResponse - ответ обработчика, записанный в память
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header         { return b.header }
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+func (b *bufferedResponse) WriteHeader(status int)      { b.status = status }
+
+// withETag - успешный ответ получает ETag по содержимому,
+// запрос с совпадающим If-None-Match получает 304 без тела
+func withETag(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		buf := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+		next(buf, r)
+
+		for name, values := range buf.header {
+			w.Header()[name] = values
+		}
+		if buf.status != http.StatusOK {
+			w.WriteHeader(buf.status)
+			w.Write(buf.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(buf.body.Bytes())
+		etag := fmt.Sprintf(`"%x"`, sum[:8])
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.body.Bytes())
+	}
+}
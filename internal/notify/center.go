@@ -0,0 +1,138 @@
+// Package notify - уведомления о событиях гильдии: центр уведомлений сессии
+// и источники событий - websocket канал и опрос сервиса гильдий.
+package notify
+
+import (
+	"fmt"
+	"sync"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/notify"
+)
+
+// Limit - сколько последних уведомлений хранит центр
+const Limit = 50
+
+// Channel - источник событий центра
+type Channel int
+
+const (
+	ChannelNone      Channel = iota // уведомления не получаются
+	ChannelWebsocket                // события приходят по websocket
+	ChannelPolling                  // события находятся опросом сервиса гильдий
+)
+
+// Notification - уведомление центра
+type Notification struct {
+	notify.Event
+	Read bool
+}
+
+// Center - уведомления текущей сессии, новые первыми
+type Center struct {
+	mu      sync.RWMutex
+	items   []Notification
+	seen    map[string]struct{} // ключи всех полученных событий
+	channel Channel
+}
+
+func NewCenter() *Center {
+	return &Center{seen: make(map[string]struct{})}
+}
+
+// Add - добавление событий. Возвращает уведомления о событиях, которых еще не было.
+func (c *Center) Add(events ...notify.Event) []Notification {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var fresh []Notification
+	for _, event := range events {
+		key := Key(event)
+		if _, ok := c.seen[key]; ok {
+			continue
+		}
+		c.seen[key] = struct{}{}
+		fresh = append(fresh, Notification{Event: event})
+	}
+	for _, n := range fresh {
+		c.items = append([]Notification{n}, c.items...)
+	}
+	if len(c.items) > Limit {
+		c.items = c.items[:Limit]
+	}
+	return fresh
+}
+
+// Seen - отметка событий полученными без уведомлений
+func (c *Center) Seen(events ...notify.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, event := range events {
+		c.seen[Key(event)] = struct{}{}
+	}
+}
+
+// List - копия уведомлений, новые первыми
+func (c *Center) List() []Notification {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]Notification(nil), c.items...)
+}
+
+// Unread - количество непрочитанных уведомлений
+func (c *Center) Unread() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	unread := 0
+	for _, n := range c.items {
+		if !n.Read {
+			unread++
+		}
+	}
+	return unread
+}
+
+// MarkRead - отметка всех уведомлений прочитанными
+func (c *Center) MarkRead() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.items {
+		c.items[i].Read = true
+	}
+}
+
+// Channel - текущий источник событий
+func (c *Center) Channel() Channel {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.channel
+}
+
+// SetChannel - смена источника событий
+func (c *Center) SetChannel(channel Channel) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.channel = channel
+}
+
+// Clear - удаление всех уведомлений при завершении сессии
+func (c *Center) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = nil
+	c.seen = make(map[string]struct{})
+	c.channel = ChannelNone
+}
+
+// Key - ключ события: одно и то же событие из websocket и опроса дает один ключ
+func Key(e notify.Event) string {
+	switch e.Kind {
+	case notify.KindJoinRequest:
+		return fmt.Sprintf("%s:%d:%d", e.Kind, e.GuildID, e.UserID)
+	case notify.KindWarDeclared:
+		return fmt.Sprintf("%s:%d", e.Kind, e.WarID)
+	case notify.KindMention, notify.KindChatInvite:
+		return fmt.Sprintf("%s:%s", e.Kind, e.MessageID)
+	default:
+		return fmt.Sprintf("%s:%d:%s:%d", e.Kind, e.GuildID, e.RoleTitle, e.CreatedAt.UnixNano())
+	}
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"lesta-start-battleship/cli/internal/api/websocket"
+	"lesta-start-battleship/cli/internal/api/websocket/packets"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/notify"
+	"lesta-start-battleship/cli/internal/api/websocket/strategies"
+)
+
+const notifyPath = "ws/%d"
+
+// errClosed - канал уведомлений закрыт
+var errClosed = errors.New("канал уведомлений закрыт")
+
+// Listener - события гильдии по websocket
+type Listener struct {
+	client *websocket.WebsocketClient
+}
+
+// Dial - подключение к уведомлениям пользователя userID по базовому адресу baseURL.
+// header - заголовки авторизации сессии пользователя, без них сервер не отдаст его события.
+func Dial(baseURL string, userID int, header http.Header) (*Listener, error) {
+	client, err := websocket.NewWebsocketClient(baseURL+fmt.Sprintf(notifyPath, userID), header, strategies.NotifyStrategy{})
+	if err != nil {
+		return nil, err
+	}
+	go client.ReadPump()
+	go client.WritePump()
+
+	return &Listener{client: client}, nil
+}
+
+// Next - ожидание следующего события. Ошибка означает разрыв соединения.
+func (l *Listener) Next() (notify.Event, error) {
+	select {
+	case packet, ok := <-l.client.ReadChan():
+		if !ok {
+			return notify.Event{}, errClosed
+		}
+		var unwrap notify.Packet
+		if err := packets.UnwrapAsNotify(packet, &unwrap); err != nil {
+			return notify.Event{}, err
+		}
+		event, ok := unwrap.(notify.Event)
+		if !ok {
+			return notify.Event{}, errClosed
+		}
+		return event, nil
+	case err := <-l.client.ErrorChan():
+		return notify.Event{}, err
+	}
+}
+
+// Close - закрытие соединения
+func (l *Listener) Close() {
+	l.client.Stop()
+}
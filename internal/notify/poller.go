@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+	"lesta-start-battleship/cli/internal/api/websocket/packets/notify"
+	guildStorage "lesta-start-battleship/cli/storage/guild"
+)
+
+// PollInterval - период опроса сервиса гильдий без websocket
+const PollInterval = 30 * time.Second
+
+// pollWarsSize - сколько ожидающих войн проверяется за один опрос
+const pollWarsSize = 20
+
+// Poller - поиск событий гильдии опросом GetJoinRequests и GetGuildWarList.
+// Первый опрос запоминает текущее состояние, уведомления дают только последующие изменения.
+type Poller struct {
+	guilds *guilds.Client
+	store  *guildStorage.Store
+	center *Center
+	userID int
+
+	started bool
+	self    *guilds.MemberResponse // членство пользователя на прошлом опросе
+}
+
+func NewPoller(guildsClient *guilds.Client, store *guildStorage.Store, center *Center, userID int) *Poller {
+	return &Poller{
+		guilds: guildsClient,
+		store:  store,
+		center: center,
+		userID: userID,
+	}
+}
+
+// Poll - опрос сервиса гильдий. Возвращает уведомления о новых событиях.
+func (p *Poller) Poll(ctx context.Context) ([]Notification, error) {
+	member, err := p.guilds.GetMemberByUserID(ctx, p.userID)
+	// 404 - пользователь не состоит в гильдии
+	var statusErr *guilds.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		member, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	events := p.membershipEvents(member)
+	p.self = member
+
+	if member != nil && len(member.Role.RolePromote) > 0 {
+		requests, err := p.guilds.GetJoinRequests(ctx, member.GuildTag, p.userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, req := range requests.Items {
+			events = append(events, notify.Event{
+				Kind:      notify.KindJoinRequest,
+				GuildID:   member.GuildID,
+				GuildTag:  member.GuildTag,
+				UserID:    req.UserID,
+				UserName:  req.UserName,
+				CreatedAt: parseTime(req.CreatedAt),
+			})
+		}
+	}
+
+	if member != nil && member.Role.Title == "owner" {
+		wars, err := p.pendingWars(ctx, member)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, wars...)
+	}
+
+	if !p.started {
+		p.started = true
+		p.center.Seen(events...)
+		return nil, nil
+	}
+	return p.center.Add(events...), nil
+}
+
+// membershipEvents - смена роли или исключение из гильдии с прошлого опроса
+func (p *Poller) membershipEvents(member *guilds.MemberResponse) []notify.Event {
+	prev := p.self
+	if !p.started || prev == nil {
+		return nil
+	}
+
+	now := time.Now()
+	switch {
+	case member == nil || member.GuildID != prev.GuildID:
+		// выход из гильдии через клиент уже очистил хранилище
+		if _, ok := p.store.Self(); !ok {
+			return nil
+		}
+		return []notify.Event{{Kind: notify.KindKicked, GuildID: prev.GuildID, GuildTag: prev.GuildTag, CreatedAt: now}}
+	case member.Role.ID != prev.Role.ID:
+		return []notify.Event{{
+			Kind:      notify.KindRoleChanged,
+			GuildID:   member.GuildID,
+			GuildTag:  member.GuildTag,
+			RoleTitle: member.Role.Title,
+			CreatedAt: now,
+		}}
+	}
+	return nil
+}
+
+// pendingWars - войны, объявленные гильдии пользователя и ожидающие подтверждения
+func (p *Poller) pendingWars(ctx context.Context, member *guilds.MemberResponse) ([]notify.Event, error) {
+	isTarget := true
+	status := guilds.WarStatusPending
+	wars, err := p.guilds.GetGuildWarList(ctx, p.userID, member.GuildID, nil, &isTarget, &status, 1, pollWarsSize)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]notify.Event, 0, len(wars.Results))
+	for _, war := range wars.Results {
+		event := notify.Event{
+			Kind:      notify.KindWarDeclared,
+			GuildID:   member.GuildID,
+			GuildTag:  member.GuildTag,
+			WarID:     war.ID,
+			EnemyID:   war.InitiatorGuildID,
+			CreatedAt: war.CreatedAt,
+		}
+		if enemy, ok := p.store.GuildByID(war.InitiatorGuildID); ok {
+			event.EnemyTag = enemy.Tag
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// parseTime - время заявки в формате RFC 3339, нулевое при ошибке разбора
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
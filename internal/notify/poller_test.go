@@ -0,0 +1,143 @@
+package notify_test
+
+import (
+	"context"
+	"testing"
+
+	"lesta-start-battleship/cli/internal/api/auth"
+	"lesta-start-battleship/cli/internal/api/guilds"
+	notifyPackets "lesta-start-battleship/cli/internal/api/websocket/packets/notify"
+	"lesta-start-battleship/cli/internal/cli/clitest"
+	"lesta-start-battleship/cli/internal/clientdeps"
+	"lesta-start-battleship/cli/internal/notify"
+)
+
+// loginAs - клиенты сервисов с сессией пользователя
+func loginAs(t *testing.T, backend *clitest.Backend, username string) *clientdeps.Client {
+	t.Helper()
+
+	clients := backend.Clients(t)
+	if _, _, err := clients.AuthClient.Login(context.Background(), auth.LoginRequest{Username: username, Password: username}); err != nil {
+		t.Fatalf("ошибка входа %s: %v", username, err)
+	}
+	return clients
+}
+
+func TestPollerJoinRequest(t *testing.T) {
+	ctx := context.Background()
+	backend := clitest.NewBackend(t)
+	owner := loginAs(t, backend, "admiral")
+	poller := notify.NewPoller(owner.GuildsClient, owner.GuildStore, owner.Notifications, 1)
+
+	// заявка mariner и война от KRAK были до начала сессии
+	items, err := poller.Poll(ctx)
+	if err != nil {
+		t.Fatalf("ошибка первого опроса: %v", err)
+	}
+	if len(items) != 0 {
+		t.Fatalf("первый опрос: %+v, ожидалось без уведомлений", items)
+	}
+
+	newbie := loginAs(t, backend, "newbie")
+	if err := newbie.GuildsClient.SendJoinRequest(ctx, "WOLF", 6); err != nil {
+		t.Fatalf("ошибка отправки заявки: %v", err)
+	}
+
+	items, err = poller.Poll(ctx)
+	if err != nil {
+		t.Fatalf("ошибка опроса: %v", err)
+	}
+	if len(items) != 1 || items[0].Kind != notifyPackets.KindJoinRequest || items[0].UserName != "newbie" {
+		t.Fatalf("уведомления: %+v, ожидалась заявка newbie", items)
+	}
+	if unread := owner.Notifications.Unread(); unread != 1 {
+		t.Errorf("непрочитанных уведомлений %d, ожидалось 1", unread)
+	}
+
+	// повторный опрос не дублирует уведомление
+	if items, err = poller.Poll(ctx); err != nil || len(items) != 0 {
+		t.Errorf("повторный опрос: %+v, %v, ожидалось без уведомлений", items, err)
+	}
+}
+
+// hasKind - есть ли среди уведомлений событие kind гильдии tag
+func hasKind(items []notify.Notification, kind notifyPackets.Kind, tag string) bool {
+	for _, item := range items {
+		if item.Kind == kind && item.GuildTag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPollerMembership(t *testing.T) {
+	ctx := context.Background()
+	backend := clitest.NewBackend(t)
+	admiral := loginAs(t, backend, "admiral")
+	cabin := loginAs(t, backend, "cabin")
+	poller := notify.NewPoller(cabin.GuildsClient, cabin.GuildStore, cabin.Notifications, 3)
+
+	if items, err := poller.Poll(ctx); err != nil || len(items) != 0 {
+		t.Fatalf("первый опрос: %+v, %v", items, err)
+	}
+	member, err := cabin.GuildsClient.GetMemberByUserID(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cabin.GuildStore.SetSelf(*member)
+
+	if err := admiral.GuildsClient.EditMember(ctx, "WOLF", 1, 3, guilds.EditMemberRequest{RoleID: 3}); err != nil {
+		t.Fatalf("ошибка повышения: %v", err)
+	}
+	// офицер заодно видит заявки в гильдию
+	items, err := poller.Poll(ctx)
+	if err != nil || !hasKind(items, notifyPackets.KindRoleChanged, "WOLF") {
+		t.Fatalf("уведомления после повышения: %+v, %v", items, err)
+	}
+
+	if err := admiral.GuildsClient.DeleteMember(ctx, "WOLF", 1, 3); err != nil {
+		t.Fatalf("ошибка исключения: %v", err)
+	}
+	items, err = poller.Poll(ctx)
+	if err != nil || len(items) != 1 || !hasKind(items, notifyPackets.KindKicked, "WOLF") {
+		t.Fatalf("уведомления после исключения: %+v, %v", items, err)
+	}
+
+	// без гильдии опрос проходит без ошибок и уведомлений
+	if items, err = poller.Poll(ctx); err != nil || len(items) != 0 {
+		t.Errorf("опрос без гильдии: %+v, %v", items, err)
+	}
+}
+
+func TestPollerWithoutGuild(t *testing.T) {
+	ctx := context.Background()
+	backend := clitest.NewBackend(t)
+	newbie := loginAs(t, backend, "newbie")
+	poller := notify.NewPoller(newbie.GuildsClient, newbie.GuildStore, newbie.Notifications, 6)
+
+	for i := range 2 {
+		if items, err := poller.Poll(ctx); err != nil || len(items) != 0 {
+			t.Fatalf("опрос %d: %+v, %v", i+1, items, err)
+		}
+	}
+}
+
+// TestDialRequiresSession - уведомления пользователя доступны только с токеном его сессии
+func TestDialRequiresSession(t *testing.T) {
+	backend := clitest.NewBackend(t)
+	admiral := loginAs(t, backend, "admiral")
+	url := backend.Endpoints.Notifications
+
+	if _, err := notify.Dial(url, 1, nil); err == nil {
+		t.Fatal("подключение без токена должно быть отклонено")
+	}
+	if _, err := notify.Dial(url, 2, admiral.AuthClient.AuthHeader()); err == nil {
+		t.Fatal("подключение к чужим уведомлениям должно быть отклонено")
+	}
+
+	listener, err := notify.Dial(url, 1, admiral.AuthClient.AuthHeader())
+	if err != nil {
+		t.Fatalf("ошибка подключения: %v", err)
+	}
+	listener.Close()
+}
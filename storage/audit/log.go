@@ -0,0 +1,128 @@
+// Package audit - локальный журнал действий управления гильдией, выполненных с этого устройства.
+// Используется, когда сервер гильдий не отдает журнал действий.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+)
+
+// Limit - сколько последних записей хранится для каждой гильдии
+const Limit = 500
+
+// Log - журнал в файле: тег гильдии -> записи, новые первыми
+type Log struct {
+	path    string
+	entries map[string][]guilds.AuditEntry
+	mu      sync.RWMutex
+	now     func() time.Time
+}
+
+// DefaultPath - путь к файлу журнала профиля по умолчанию
+func DefaultPath(profile string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join("audit", profile+".json")
+	}
+	return filepath.Join(dir, "lesta-battleship", "audit", profile+".json")
+}
+
+// Open - чтение журнала. Отсутствующий или поврежденный файл дает пустой журнал,
+// пустой path - журнал только в памяти.
+func Open(path string) *Log {
+	l := &Log{
+		path:    path,
+		entries: make(map[string][]guilds.AuditEntry),
+		now:     time.Now,
+	}
+	if path == "" {
+		return l
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return l
+	}
+	if err := json.Unmarshal(raw, &l.entries); err != nil || l.entries == nil {
+		l.entries = make(map[string][]guilds.AuditEntry)
+	}
+	return l
+}
+
+// Record - запись действия в журнал гильдии tag. ID и время записи заполняются журналом.
+func (l *Log) Record(tag string, entry guilds.AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := l.entries[tag]
+	entry.ID = 1
+	if len(entries) > 0 {
+		entry.ID = entries[0].ID + 1
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = l.now()
+	}
+
+	entries = append([]guilds.AuditEntry{entry}, entries...)
+	if len(entries) > Limit {
+		entries = entries[:Limit]
+	}
+	l.entries[tag] = entries
+	return l.save()
+}
+
+// Entries - копия записей гильдии tag, новые первыми
+func (l *Log) Entries(tag string) []guilds.AuditEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]guilds.AuditEntry(nil), l.entries[tag]...)
+}
+
+// Page - страница записей гильдии tag в формате ответа сервера
+func (l *Log) Page(tag string, offset, limit int) *guilds.AuditPagination {
+	all := l.Entries(tag)
+	p := &guilds.AuditPagination{Items: []guilds.AuditEntry{}, TotalItems: len(all), TotalPages: 1}
+	if limit > 0 && len(all) > 0 {
+		p.TotalPages = (len(all) + limit - 1) / limit
+	}
+	if offset < len(all) {
+		end := len(all)
+		if limit > 0 {
+			end = min(offset+limit, end)
+		}
+		p.Items = all[offset:end]
+	}
+	return p
+}
+
+// save - запись файла, вызывается под блокировкой
+func (l *Log) save() error {
+	if l.path == "" {
+		return nil
+	}
+
+	raw, err := json.Marshal(l.entries)
+	if err != nil {
+		return fmt.Errorf("ошибка кодирования журнала: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("ошибка создания каталога журнала: %w", err)
+	}
+
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("ошибка записи журнала: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("ошибка записи журнала: %w", err)
+	}
+
+	return nil
+}
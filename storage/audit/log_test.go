@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"path/filepath"
+	"testing"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+)
+
+func TestLogRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "default.json")
+	l := Open(path)
+
+	for _, name := range []string{"bosun", "cabin", "mariner"} {
+		entry := guilds.AuditEntry{Action: guilds.AuditJoinAccepted, ActorName: "admiral", TargetName: name}
+		if err := l.Record("WOLF", entry); err != nil {
+			t.Fatalf("ошибка записи журнала: %v", err)
+		}
+	}
+
+	// журнал читается из файла, новые записи первыми
+	entries := Open(path).Entries("WOLF")
+	if len(entries) != 3 || entries[0].TargetName != "mariner" || entries[0].ID != 3 || entries[2].ID != 1 {
+		t.Fatalf("записи журнала: %+v", entries)
+	}
+	if entries[0].CreatedAt.IsZero() {
+		t.Error("время записи не заполнено")
+	}
+	if other := l.Entries("KRAK"); len(other) != 0 {
+		t.Errorf("записи чужой гильдии: %+v", other)
+	}
+
+	page := l.Page("WOLF", 2, 2)
+	if page.TotalItems != 3 || page.TotalPages != 2 || len(page.Items) != 1 || page.Items[0].TargetName != "bosun" {
+		t.Errorf("страница журнала: %+v", page)
+	}
+}
@@ -0,0 +1,163 @@
+package chat
+
+import (
+	"sort"
+	"sync"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/direct"
+)
+
+// Direct - личные переписки пользователя текущей сессии: сообщения и счетчики непрочитанных по собеседникам
+type Direct struct {
+	userID        int
+	conversations map[int]*conversation // ключ - user_id собеседника
+	open          int                   // собеседник открытой переписки, ее сообщения сразу прочитаны
+	mu            sync.Mutex
+}
+
+// conversation - переписка с собеседником по времени отправки
+type conversation struct {
+	username string
+	messages []direct.Message
+	ids      map[string]struct{}
+	unread   int
+	last     direct.Message // последнее сообщение, может быть известно без загрузки переписки
+}
+
+// NewDirect - пустые переписки, пользователь задается Reset при входе
+func NewDirect() *Direct {
+	return &Direct{conversations: make(map[int]*conversation)}
+}
+
+// Reset - очистка переписок при смене пользователя
+func (d *Direct) Reset(userID int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.userID = userID
+	d.open = 0
+	d.conversations = make(map[int]*conversation)
+}
+
+// SetConversations - список переписок от сервера: собеседники, последние сообщения и непрочитанные
+func (d *Direct) SetConversations(items []direct.Conversation) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, item := range items {
+		c := d.conversation(item.UserId, item.Username)
+		c.unread = item.Unread
+		if item.UserId == d.open {
+			c.unread = 0
+		}
+		if item.Last.Id != "" && !sentBefore(item.Last.Timestamp, c.last.Timestamp) {
+			c.last = item.Last
+		}
+	}
+}
+
+// Add - сохранение сообщений. Возвращает сообщения, которых еще не было.
+// Новые сообщения от собеседника увеличивают счетчик непрочитанных, если его переписка не открыта.
+func (d *Direct) Add(messages ...direct.Message) []direct.Message {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var added []direct.Message
+	for _, m := range messages {
+		peerID, peerName := m.Peer(d.userID)
+		c := d.conversation(peerID, peerName)
+		if _, ok := c.ids[m.Id]; ok || m.Id == "" {
+			continue
+		}
+		c.ids[m.Id] = struct{}{}
+		c.messages = append(c.messages, m)
+		sort.SliceStable(c.messages, func(i, j int) bool {
+			return sentBefore(c.messages[i].Timestamp, c.messages[j].Timestamp)
+		})
+		if !sentBefore(m.Timestamp, c.last.Timestamp) {
+			c.last = m
+		}
+		added = append(added, m)
+	}
+	return added
+}
+
+// Received - сообщение от собеседника, пришедшее во время сессии.
+// Возвращает false для повтора уже известного сообщения.
+func (d *Direct) Received(m direct.Message) bool {
+	if len(d.Add(m)) == 0 {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if peerID, _ := m.Peer(d.userID); m.FromId != d.userID && peerID != d.open {
+		d.conversations[peerID].unread++
+	}
+	return true
+}
+
+// Open - переписка с собеседником открыта, ее сообщения прочитаны. 0 - открытой переписки нет.
+func (d *Direct) Open(userID int, username string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.open = userID
+	if userID != 0 {
+		d.conversation(userID, username).unread = 0
+	}
+}
+
+// Messages - сообщения переписки с собеседником, старые первыми
+func (d *Direct) Messages(userID int) []direct.Message {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if c, ok := d.conversations[userID]; ok {
+		return append([]direct.Message(nil), c.messages...)
+	}
+	return nil
+}
+
+// Conversations - переписки, последние первыми. Собеседники без сообщений не возвращаются.
+func (d *Direct) Conversations() []direct.Conversation {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var items []direct.Conversation
+	for id, c := range d.conversations {
+		if c.last.Id == "" {
+			continue
+		}
+		items = append(items, direct.Conversation{UserId: id, Username: c.username, Unread: c.unread, Last: c.last})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return sentBefore(items[j].Last.Timestamp, items[i].Last.Timestamp)
+	})
+	return items
+}
+
+// Unread - непрочитанных сообщений во всех переписках
+func (d *Direct) Unread() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	total := 0
+	for _, c := range d.conversations {
+		total += c.unread
+	}
+	return total
+}
+
+// conversation - переписка с собеседником, создается при первом обращении. Вызывается под блокировкой.
+func (d *Direct) conversation(userID int, username string) *conversation {
+	c, ok := d.conversations[userID]
+	if !ok {
+		c = &conversation{ids: make(map[string]struct{})}
+		d.conversations[userID] = c
+	}
+	if username != "" {
+		c.username = username
+	}
+	return c
+}
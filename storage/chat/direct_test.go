@@ -0,0 +1,42 @@
+package chat
+
+import (
+	"testing"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/direct"
+)
+
+func directMessage(id string, from, to int, timestamp string) direct.Message {
+	names := map[int]string{1: "admiral", 2: "bosun", 4: "corsair"}
+	return direct.Message{Type: direct.TypeMessage, Id: id, FromId: from, FromName: names[from], ToId: to, ToName: names[to],
+		Content: "сообщение " + id, Timestamp: timestamp}
+}
+
+func TestDirectUnread(t *testing.T) {
+	d := NewDirect()
+	d.Reset(1)
+
+	d.SetConversations([]direct.Conversation{{UserId: 2, Username: "bosun", Unread: 1, Last: directMessage("a", 2, 1, "2025-06-02T10:00:00Z")}})
+	if !d.Received(directMessage("b", 4, 1, "2025-06-02T11:00:00Z")) || d.Received(directMessage("b", 4, 1, "2025-06-02T11:00:00Z")) {
+		t.Fatal("повтор сообщения принят как новое")
+	}
+	d.Received(directMessage("c", 1, 4, "2025-06-02T11:01:00Z"))
+	if d.Unread() != 2 {
+		t.Fatalf("непрочитанных %d, ожидалось 2", d.Unread())
+	}
+
+	items := d.Conversations()
+	if len(items) != 2 || items[0].Username != "corsair" || items[0].Last.Id != "c" || items[0].Unread != 1 {
+		t.Fatalf("переписки: %+v", items)
+	}
+
+	// в открытой переписке новые сообщения сразу прочитаны
+	d.Open(2, "bosun")
+	d.Received(directMessage("d", 2, 1, "2025-06-02T12:00:00Z"))
+	if d.Unread() != 1 {
+		t.Errorf("непрочитанных после открытия %d, ожидалось 1", d.Unread())
+	}
+	if messages := d.Messages(2); len(messages) != 1 || messages[0].Id != "d" {
+		t.Errorf("сообщения bosun: %+v", messages)
+	}
+}
@@ -0,0 +1,240 @@
+// Package chat - локальная история чатов гильдий.
+// Сообщения каждой гильдии дописываются в файл JSON Lines, повторы по ID сообщения отбрасываются.
+package chat
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+)
+
+// LobbyID - комната общего чата лобби в истории, id гильдий начинаются с 1
+const LobbyID = 0
+
+// Log - история чатов гильдий в каталоге dir, файл guild_<id>.jsonl на гильдию, lobby.jsonl - лобби
+type Log struct {
+	dir   string
+	rooms map[int]*room
+	mu    sync.Mutex
+}
+
+// room - сообщения гильдии по времени отправки
+type room struct {
+	messages []guild.ChatHistoryMessage
+	ids      map[string]struct{}
+}
+
+// DefaultDir - каталог истории чатов профиля по умолчанию
+func DefaultDir(profile string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join("chat", profile)
+	}
+	return filepath.Join(dir, "lesta-battleship", "chat", profile)
+}
+
+// Open - история чатов в каталоге dir, пустой dir - история только в памяти.
+// Файлы гильдий читаются при первом обращении.
+func Open(dir string) *Log {
+	return &Log{dir: dir, rooms: make(map[int]*room)}
+}
+
+// Append - сохранение сообщений гильдии. Возвращает сообщения, которых еще не было в истории.
+func (l *Log) Append(guildID int, messages ...guild.ChatHistoryMessage) ([]guild.ChatHistoryMessage, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r := l.room(guildID)
+	var added []guild.ChatHistoryMessage
+	for _, m := range messages {
+		if _, ok := r.ids[m.Id]; ok || m.Id == "" {
+			continue
+		}
+		r.ids[m.Id] = struct{}{}
+		added = append(added, m)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	r.messages = append(r.messages, added...)
+	sort.SliceStable(r.messages, func(i, j int) bool {
+		return sentBefore(r.messages[i].Timestamp, r.messages[j].Timestamp)
+	})
+	return added, l.save(guildID, added)
+}
+
+// Latest - последние limit сообщений гильдии, старые первыми
+func (l *Log) Latest(guildID, limit int) []guild.ChatHistoryMessage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	messages := l.room(guildID).messages
+	return clone(messages[max(len(messages)-limit, 0):])
+}
+
+// Before - до limit сообщений гильдии, отправленных перед сообщением id, старые первыми.
+// Неизвестный id дает пустой результат.
+func (l *Log) Before(guildID int, id string, limit int) []guild.ChatHistoryMessage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	messages := l.room(guildID).messages
+	for i, m := range messages {
+		if m.Id == id {
+			return clone(messages[max(i-limit, 0):i])
+		}
+	}
+	return nil
+}
+
+// Delete - удаление сообщения id, удаленного модератором или автором, файл гильдии переписывается.
+// ID остается известным: повторная история с сервера не вернет сообщение до перезапуска.
+func (l *Log) Delete(guildID int, id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r := l.room(guildID)
+	for i, m := range r.messages {
+		if m.Id == id {
+			r.messages = append(r.messages[:i:i], r.messages[i+1:]...)
+			return l.rewrite(guildID, r.messages)
+		}
+	}
+	return nil
+}
+
+// Search - сообщения гильдии, текст или автор которых содержит term без учета регистра, старые первыми
+func (l *Log) Search(guildID int, term string) []guild.ChatHistoryMessage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	term = strings.ToLower(term)
+	var found []guild.ChatHistoryMessage
+	for _, m := range l.room(guildID).messages {
+		if strings.Contains(strings.ToLower(m.Content), term) || strings.Contains(strings.ToLower(m.Username), term) {
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// room - сообщения гильдии, при первом обращении читаются из файла. Вызывается под блокировкой.
+// Поврежденные строки файла пропускаются.
+func (l *Log) room(guildID int) *room {
+	if r, ok := l.rooms[guildID]; ok {
+		return r
+	}
+
+	r := &room{ids: make(map[string]struct{})}
+	l.rooms[guildID] = r
+	if l.dir == "" {
+		return r
+	}
+
+	f, err := os.Open(l.path(guildID))
+	if err != nil {
+		return r
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var m guild.ChatHistoryMessage
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil || m.Id == "" {
+			continue
+		}
+		if _, ok := r.ids[m.Id]; ok {
+			continue
+		}
+		r.ids[m.Id] = struct{}{}
+		r.messages = append(r.messages, m)
+	}
+	sort.SliceStable(r.messages, func(i, j int) bool {
+		return sentBefore(r.messages[i].Timestamp, r.messages[j].Timestamp)
+	})
+	return r
+}
+
+func (l *Log) path(guildID int) string {
+	if guildID == LobbyID {
+		return filepath.Join(l.dir, "lobby.jsonl")
+	}
+	return filepath.Join(l.dir, fmt.Sprintf("guild_%d.jsonl", guildID))
+}
+
+// save - дозапись сообщений в файл гильдии, вызывается под блокировкой
+func (l *Log) save(guildID int, messages []guild.ChatHistoryMessage) error {
+	if l.dir == "" {
+		return nil
+	}
+
+	return l.saveTo(l.path(guildID), messages)
+}
+
+// saveTo - дозапись сообщений в файл path
+func (l *Log) saveTo(path string, messages []guild.ChatHistoryMessage) error {
+	if err := os.MkdirAll(l.dir, 0o700); err != nil {
+		return fmt.Errorf("ошибка создания каталога истории чата: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("ошибка записи истории чата: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, m := range messages {
+		if err := enc.Encode(m); err != nil {
+			return fmt.Errorf("ошибка записи истории чата: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("ошибка записи истории чата: %w", err)
+	}
+	return f.Close()
+}
+
+// rewrite - замена файла гильдии сообщениями messages, вызывается под блокировкой
+func (l *Log) rewrite(guildID int, messages []guild.ChatHistoryMessage) error {
+	if l.dir == "" {
+		return nil
+	}
+
+	path := l.path(guildID)
+	tmp := path + ".tmp"
+	if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("ошибка записи истории чата: %w", err)
+	}
+	if err := l.saveTo(tmp, messages); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("ошибка записи истории чата: %w", err)
+	}
+	return nil
+}
+
+// sentBefore - сообщение со временем a отправлено раньше b. Время без зоны сравнивается как строка.
+func sentBefore(a, b string) bool {
+	ta, errA := time.Parse(time.RFC3339Nano, a)
+	tb, errB := time.Parse(time.RFC3339Nano, b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return ta.Before(tb)
+}
+
+func clone(messages []guild.ChatHistoryMessage) []guild.ChatHistoryMessage {
+	return append([]guild.ChatHistoryMessage(nil), messages...)
+}
@@ -0,0 +1,74 @@
+package chat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"lesta-start-battleship/cli/internal/api/websocket/packets/guild"
+)
+
+func message(id, content, timestamp string) guild.ChatHistoryMessage {
+	return guild.ChatHistoryMessage{Id: id, GuildId: 1, UserId: 2, Username: "bosun", Content: content, Timestamp: timestamp}
+}
+
+func TestLogAppend(t *testing.T) {
+	dir := t.TempDir()
+	l := Open(dir)
+
+	added, err := l.Append(1,
+		message("b", "Принято, капитан", "2025-06-02T10:01:00Z"),
+		message("a", "Всем привет", "2025-06-02T10:00:00Z"),
+	)
+	if err != nil || len(added) != 2 {
+		t.Fatalf("добавлено %d сообщений, ошибка %v", len(added), err)
+	}
+
+	// повторная история после переподключения не дублируется
+	added, err = l.Append(1, message("b", "Принято, капитан", "2025-06-02T10:01:00Z"), message("c", "Сбор в 20:00", "2025-06-02T10:02:00Z"))
+	if err != nil || len(added) != 1 || added[0].Id != "c" {
+		t.Fatalf("повторное добавление: %+v, %v", added, err)
+	}
+
+	// файл дописан поврежденной строкой, история читается заново
+	f, err := os.OpenFile(filepath.Join(dir, "guild_1.jsonl"), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("ошибка открытия файла истории: %v", err)
+	}
+	f.WriteString("{обрыв\n")
+	f.Close()
+
+	reopened := Open(dir)
+	latest := reopened.Latest(1, 10)
+	if len(latest) != 3 || latest[0].Id != "a" || latest[2].Id != "c" {
+		t.Fatalf("история из файла: %+v", latest)
+	}
+	if other := reopened.Latest(2, 10); len(other) != 0 {
+		t.Errorf("история чужой гильдии: %+v", other)
+	}
+
+	if before := reopened.Before(1, "c", 1); len(before) != 1 || before[0].Id != "b" {
+		t.Errorf("сообщения до c: %+v", before)
+	}
+	if before := reopened.Before(1, "a", 10); len(before) != 0 {
+		t.Errorf("сообщения до первого: %+v", before)
+	}
+
+	if found := reopened.Search(1, "ПРИВЕТ"); len(found) != 1 || found[0].Id != "a" {
+		t.Errorf("поиск по тексту: %+v", found)
+	}
+	if found := reopened.Search(1, "bos"); len(found) != 3 {
+		t.Errorf("поиск по автору: %+v", found)
+	}
+
+	// удаленное сообщение не возвращается после перечитывания файла и повторной истории
+	if err := reopened.Delete(1, "b"); err != nil {
+		t.Fatalf("ошибка удаления: %v", err)
+	}
+	if added, _ := reopened.Append(1, message("b", "Принято, капитан", "2025-06-02T10:01:00Z")); len(added) != 0 {
+		t.Errorf("удаленное сообщение добавлено снова: %+v", added)
+	}
+	if latest := Open(dir).Latest(1, 10); len(latest) != 2 || latest[0].Id != "a" || latest[1].Id != "c" {
+		t.Errorf("история после удаления: %+v", latest)
+	}
+}
@@ -1,72 +1,260 @@
 package guild
 
 import (
-	"lesta-start-battleship/cli/internal/api/guilds"
+	"reflect"
+	"sort"
 	"sync"
-)
+	"time"
 
-var (
-	mu sync.RWMutex
+	"lesta-start-battleship/cli/internal/api/guilds"
+)
 
-	// Список участников гильдии: username -> MemberResponse
-	Members = make(map[string]guilds.MemberResponse)
+// DefaultTTL - время жизни данных гильдий в хранилище сессии
+const DefaultTTL = 2 * time.Minute
 
-	// Информация о текущем пользователе в гильдии
-	Self guilds.MemberResponse
+// subscriberBuffer - размер очереди изменений одного подписчика
+const subscriberBuffer = 8
 
-	// Список гильдий: guild_tag -> GuildResponse
-	GuildListTag = make(map[string]guilds.GuildResponse)
+// ChangeKind - вид изменения данных в хранилище
+type ChangeKind int
 
-	// Список гильдий: guild_id -> GuildResponse
-	GuildListID = make(map[int]guilds.GuildResponse)
+const (
+	SelfChanged    ChangeKind = iota // членство или роль текущего пользователя
+	MembersChanged                   // состав или роли участников гильдии
+	GuildsChanged                    // данные гильдий
 )
 
-func SetMember(username string, member guilds.MemberResponse) {
-	mu.Lock()
-	defer mu.Unlock()
-	Members[username] = member
+// Change - изменение данных в хранилище
+type Change struct {
+	Kind     ChangeKind
+	GuildTag string // тег затронутой гильдии, пустой - все гильдии
+}
+
+// entry - значение с временем сохранения
+type entry[T any] struct {
+	value    T
+	storedAt time.Time
+}
+
+// Store - хранилище данных гильдий сессии пользователя.
+// Значения старше ttl считаются устаревшими и не возвращаются,
+// изменения рассылаются подписчикам.
+type Store struct {
+	mu  sync.RWMutex
+	ttl time.Duration
+	now func() time.Time
+
+	self    *entry[guilds.MemberResponse]           // текущий пользователь в гильдии
+	members map[string]entry[guilds.MemberResponse] // username -> участник
+	byTag   map[string]entry[guilds.GuildResponse]  // guild_tag -> гильдия
+	byID    map[int]entry[guilds.GuildResponse]     // guild_id -> гильдия
+
+	subs   map[int]chan Change
+	nextID int
+}
+
+// NewStore - создание хранилища с временем жизни данных ttl
+func NewStore(ttl time.Duration) *Store {
+	return &Store{
+		ttl:     ttl,
+		now:     time.Now,
+		members: make(map[string]entry[guilds.MemberResponse]),
+		byTag:   make(map[string]entry[guilds.GuildResponse]),
+		byID:    make(map[int]entry[guilds.GuildResponse]),
+		subs:    make(map[int]chan Change),
+	}
+}
+
+// TTL - время жизни данных
+func (s *Store) TTL() time.Duration {
+	return s.ttl
+}
+
+// Subscribe - подписка на изменения. Если подписчик не успевает читать,
+// изменения сверх буфера отбрасываются. cancel закрывает канал.
+func (s *Store) Subscribe() (<-chan Change, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	ch := make(chan Change, subscriberBuffer)
+	s.subs[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			delete(s.subs, id)
+			close(ch)
+		})
+	}
+}
+
+// Self - текущий пользователь в гильдии
+func (s *Store) Self() (guilds.MemberResponse, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.self == nil || s.expired(s.self.storedAt) {
+		return guilds.MemberResponse{}, false
+	}
+	return s.self.value, true
 }
 
-func GetMember(username string) (guilds.MemberResponse, bool) {
-	mu.RLock()
-	defer mu.RUnlock()
-	val, ok := Members[username]
-	return val, ok
+// SetSelf - сохранение текущего пользователя в гильдии.
+// Смена гильдии или роли рассылается подписчикам.
+func (s *Store) SetSelf(member guilds.MemberResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := s.self == nil || s.self.value.GuildID != member.GuildID || s.self.value.Role.ID != member.Role.ID
+	s.self = &entry[guilds.MemberResponse]{value: member, storedAt: s.now()}
+	if changed {
+		s.notify(Change{Kind: SelfChanged, GuildTag: member.GuildTag})
+	}
+}
+
+// ClearSelf - текущий пользователь не состоит в гильдии
+func (s *Store) ClearSelf() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.self == nil {
+		return
+	}
+	tag := s.self.value.GuildTag
+	s.self = nil
+	s.notify(Change{Kind: SelfChanged, GuildTag: tag})
+}
+
+// Member - участник гильдии по имени пользователя
+func (s *Store) Member(username string) (guilds.MemberResponse, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.members[username]
+	if !ok || s.expired(e.storedAt) {
+		return guilds.MemberResponse{}, false
+	}
+	return e.value, true
+}
+
+// Members - известные участники гильдии guildTag по имени пользователя
+func (s *Store) Members(guildTag string) []guilds.MemberResponse {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var members []guilds.MemberResponse
+	for _, e := range s.members {
+		if e.value.GuildTag == guildTag && !s.expired(e.storedAt) {
+			members = append(members, e.value)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].UserName < members[j].UserName })
+	return members
+}
+
+// SetMember - сохранение участника гильдии.
+// Изменение уже известного участника рассылается подписчикам.
+func (s *Store) SetMember(member guilds.MemberResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, ok := s.members[member.UserName]
+	s.members[member.UserName] = entry[guilds.MemberResponse]{value: member, storedAt: s.now()}
+	if ok && !reflect.DeepEqual(prev.value, member) {
+		s.notify(Change{Kind: MembersChanged, GuildTag: member.GuildTag})
+	}
+}
+
+// InvalidateMembers - сброс участников гильдии guildTag после их изменения
+func (s *Store) InvalidateMembers(guildTag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for username, e := range s.members {
+		if e.value.GuildTag == guildTag {
+			delete(s.members, username)
+		}
+	}
+	s.notify(Change{Kind: MembersChanged, GuildTag: guildTag})
 }
 
-func SetSelf(member guilds.MemberResponse) {
-	mu.Lock()
-	defer mu.Unlock()
-	Self = member
+// Guild - гильдия по тегу
+func (s *Store) Guild(tag string) (guilds.GuildResponse, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.byTag[tag]
+	if !ok || s.expired(e.storedAt) {
+		return guilds.GuildResponse{}, false
+	}
+	return e.value, true
 }
 
-func SetGuild(guildTag string, guild guilds.GuildResponse) {
-	mu.Lock()
-	defer mu.Unlock()
-	GuildListTag[guildTag] = guild
+// GuildByID - гильдия по идентификатору
+func (s *Store) GuildByID(id int) (guilds.GuildResponse, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.byID[id]
+	if !ok || s.expired(e.storedAt) {
+		return guilds.GuildResponse{}, false
+	}
+	return e.value, true
 }
 
-func GetGuild(guildTag string) (guilds.GuildResponse, bool) {
-	mu.RLock()
-	defer mu.RUnlock()
-	val, ok := GuildListTag[guildTag]
-	return val, ok
+// SetGuild - сохранение гильдии.
+// Изменение уже известной гильдии рассылается подписчикам.
+func (s *Store) SetGuild(guild guilds.GuildResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, ok := s.byTag[guild.Tag]
+	e := entry[guilds.GuildResponse]{value: guild, storedAt: s.now()}
+	s.byTag[guild.Tag] = e
+	s.byID[guild.ID] = e
+	if ok && !reflect.DeepEqual(prev.value, guild) {
+		s.notify(Change{Kind: GuildsChanged, GuildTag: guild.Tag})
+	}
 }
 
-func CleanStorage() {
-	Self = guilds.MemberResponse{}
-	Members = nil
+// RemoveGuild - удаление гильдии и ее участников
+func (s *Store) RemoveGuild(tag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.byTag[tag]; ok {
+		delete(s.byID, e.value.ID)
+		delete(s.byTag, tag)
+	}
+	for username, e := range s.members {
+		if e.value.GuildTag == tag {
+			delete(s.members, username)
+		}
+	}
+	s.notify(Change{Kind: GuildsChanged, GuildTag: tag})
+}
+
+// Clear - очистка хранилища при завершении сессии. Подписки сохраняются.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.self = nil
+	s.members = make(map[string]entry[guilds.MemberResponse])
+	s.byTag = make(map[string]entry[guilds.GuildResponse])
+	s.byID = make(map[int]entry[guilds.GuildResponse])
 }
 
-func SetGuildID(guildId int, guild guilds.GuildResponse) {
-	mu.Lock()
-	defer mu.Unlock()
-	GuildListID[guildId] = guild
+func (s *Store) expired(storedAt time.Time) bool {
+	return s.ttl > 0 && s.now().Sub(storedAt) > s.ttl
 }
 
-func GetGuildID(guildId int) (guilds.GuildResponse, bool) {
-	mu.RLock()
-	defer mu.RUnlock()
-	val, ok := GuildListID[guildId]
-	return val, ok
+// notify - рассылка изменения подписчикам, вызывается под s.mu
+func (s *Store) notify(change Change) {
+	for _, ch := range s.subs {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
 }
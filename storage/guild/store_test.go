@@ -0,0 +1,75 @@
+package guild
+
+import (
+	"testing"
+	"time"
+
+	"lesta-start-battleship/cli/internal/api/guilds"
+)
+
+func TestStoreTTL(t *testing.T) {
+	now := time.Now()
+	s := NewStore(time.Minute)
+	s.now = func() time.Time { return now }
+
+	s.SetGuild(guilds.GuildResponse{ID: 1, Tag: "WOLF"})
+	if _, ok := s.Guild("WOLF"); !ok {
+		t.Fatal("гильдия WOLF не найдена сразу после сохранения")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := s.Guild("WOLF"); ok {
+		t.Error("устаревшая гильдия WOLF возвращена по тегу")
+	}
+	if _, ok := s.GuildByID(1); ok {
+		t.Error("устаревшая гильдия WOLF возвращена по идентификатору")
+	}
+}
+
+func TestStoreSubscribe(t *testing.T) {
+	s := NewStore(DefaultTTL)
+	changes, cancel := s.Subscribe()
+
+	officer := guilds.MemberResponse{UserName: "bosun", GuildID: 1, GuildTag: "WOLF", Role: guilds.Role{ID: 3}}
+	s.SetSelf(officer)
+	s.SetSelf(officer) // повторное сохранение без изменений не рассылается
+	officer.Role.ID = 2
+	s.SetSelf(officer)
+	s.ClearSelf()
+
+	want := []Change{
+		{Kind: SelfChanged, GuildTag: "WOLF"},
+		{Kind: SelfChanged, GuildTag: "WOLF"},
+		{Kind: SelfChanged, GuildTag: "WOLF"},
+	}
+	for i, w := range want {
+		select {
+		case got := <-changes:
+			if got != w {
+				t.Errorf("изменение %d: %+v, ожидалось %+v", i, got, w)
+			}
+		default:
+			t.Fatalf("изменение %d не получено", i)
+		}
+	}
+
+	cancel()
+	if _, ok := <-changes; ok {
+		t.Error("канал подписки не закрыт после отмены")
+	}
+	s.InvalidateMembers("WOLF") // после отмены рассылка не паникует
+}
+
+func TestStoreClear(t *testing.T) {
+	s := NewStore(DefaultTTL)
+	s.SetMember(guilds.MemberResponse{UserName: "cabin", GuildTag: "WOLF"})
+	s.Clear()
+
+	if _, ok := s.Member("cabin"); ok {
+		t.Error("участник cabin остался после очистки")
+	}
+	s.SetMember(guilds.MemberResponse{UserName: "cabin", GuildTag: "WOLF"})
+	if _, ok := s.Member("cabin"); !ok {
+		t.Error("участник cabin не сохранен после очистки")
+	}
+}
@@ -0,0 +1,234 @@
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// DefaultName - имя профиля, который создается при первом запуске
+const DefaultName = "default"
+
+// Session - сохраненная сессия аккаунта
+type Session struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	UserID       int    `json:"user_id"`
+	Username     string `json:"username"`
+}
+
+// Settings - последние использованные настройки профиля
+type Settings struct {
+	LastLogin  string `json:"last_login,omitempty"`  // логин для подстановки на экране авторизации
+	AuthMethod int    `json:"auth_method,omitempty"` // 0 - Логин/Пароль, 1 - Google, 2 - Яндекс
+}
+
+// Profile - профиль аккаунта
+type Profile struct {
+	Name        string   `json:"name"`
+	Environment string   `json:"environment,omitempty"` // окружение из конфигурации, пусто - окружение по умолчанию
+	Session     *Session `json:"session,omitempty"`
+	Settings    Settings `json:"settings"`
+}
+
+// fileData - формат файла профилей
+type fileData struct {
+	Current  string              `json:"current"`
+	Profiles map[string]*Profile `json:"profiles"`
+}
+
+// Store - хранилище профилей в файле.
+// Файл содержит токены, поэтому создается с правами 0600.
+type Store struct {
+	path string
+	data fileData
+	mu   sync.RWMutex
+}
+
+// DefaultPath - путь к файлу профилей по умолчанию
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "profiles.json"
+	}
+	return filepath.Join(dir, "lesta-battleship", "profiles.json")
+}
+
+// Open - чтение хранилища профилей. Отсутствующий файл не является ошибкой.
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path: path,
+		data: fileData{Profiles: make(map[string]*Profile)},
+	}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения профилей: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("ошибка разбора профилей %s: %w", path, err)
+	}
+	if s.data.Profiles == nil {
+		s.data.Profiles = make(map[string]*Profile)
+	}
+
+	return s, nil
+}
+
+// Current - имя последнего использованного профиля
+func (s *Store) Current() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.data.Current == "" {
+		return DefaultName
+	}
+	return s.data.Current
+}
+
+// List - список профилей, отсортированный по имени
+func (s *Store) List() []Profile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]Profile, 0, len(s.data.Profiles))
+	for _, p := range s.data.Profiles {
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Get - получение профиля по имени
+func (s *Store) Get(name string) (Profile, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.data.Profiles[name]
+	if !ok {
+		return Profile{}, false
+	}
+	return *p, true
+}
+
+// Use - выбор профиля как текущего. Несуществующий профиль создается.
+func (s *Store) Use(name string) (Profile, error) {
+	if name == "" {
+		return Profile{}, fmt.Errorf("пустое имя профиля")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.data.Profiles[name]
+	if !ok {
+		p = &Profile{Name: name}
+		s.data.Profiles[name] = p
+	}
+	s.data.Current = name
+
+	return *p, s.save()
+}
+
+// SetEnvironment - привязка профиля к окружению из конфигурации
+func (s *Store) SetEnvironment(name, environment string) error {
+	return s.update(name, func(p *Profile) {
+		p.Environment = environment
+	})
+}
+
+// SaveSession - сохранение данных пользователя в сессии профиля
+func (s *Store) SaveSession(name string, userID int, username string) error {
+	return s.update(name, func(p *Profile) {
+		if p.Session == nil {
+			p.Session = &Session{}
+		}
+		p.Session.UserID = userID
+		p.Session.Username = username
+		p.Settings.LastLogin = username
+	})
+}
+
+// SaveTokens - сохранение токенов в сессии профиля.
+// Пустые токены удаляют сессию.
+func (s *Store) SaveTokens(name, access, refresh string) error {
+	return s.update(name, func(p *Profile) {
+		if access == "" && refresh == "" {
+			p.Session = nil
+			return
+		}
+		if p.Session == nil {
+			p.Session = &Session{}
+		}
+		p.Session.AccessToken = access
+		p.Session.RefreshToken = refresh
+	})
+}
+
+// ClearSession - удаление сохраненной сессии профиля
+func (s *Store) ClearSession(name string) error {
+	return s.update(name, func(p *Profile) {
+		p.Session = nil
+	})
+}
+
+// UpdateSettings - изменение последних использованных настроек профиля
+func (s *Store) UpdateSettings(name string, fn func(*Settings)) error {
+	return s.update(name, func(p *Profile) {
+		fn(&p.Settings)
+	})
+}
+
+// Delete - удаление профиля
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data.Profiles, name)
+	if s.data.Current == name {
+		s.data.Current = ""
+	}
+	return s.save()
+}
+
+func (s *Store) update(name string, fn func(*Profile)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.data.Profiles[name]
+	if !ok {
+		p = &Profile{Name: name}
+		s.data.Profiles[name] = p
+	}
+	fn(p)
+
+	return s.save()
+}
+
+// save - запись файла, вызывается под блокировкой
+func (s *Store) save() error {
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ошибка кодирования профилей: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("ошибка создания каталога профилей: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("ошибка записи профилей: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("ошибка записи профилей: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "profiles.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("ошибка открытия без файла: %v", err)
+	}
+	if s.Current() != DefaultName {
+		t.Errorf("текущий профиль без файла: %q", s.Current())
+	}
+
+	if _, err := s.Use("officer"); err != nil {
+		t.Fatal(err)
+	}
+	s.SetEnvironment("officer", "local")
+	s.SaveTokens("officer", "access", "refresh")
+	s.SaveSession("officer", 2, "bosun")
+	s.UpdateSettings("officer", func(settings *Settings) { settings.AuthMethod = 1 })
+	s.Use("owner")
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("ошибка повторного открытия: %v", err)
+	}
+	if s.Current() != "owner" {
+		t.Errorf("текущий профиль после перезапуска: %q", s.Current())
+	}
+	got, ok := s.Get("officer")
+	want := Profile{
+		Name:        "officer",
+		Environment: "local",
+		Session:     &Session{AccessToken: "access", RefreshToken: "refresh", UserID: 2, Username: "bosun"},
+		Settings:    Settings{LastLogin: "bosun", AuthMethod: 1},
+	}
+	if !ok || got.Environment != want.Environment || got.Settings != want.Settings || got.Session == nil || *got.Session != *want.Session {
+		t.Errorf("профиль после перезапуска: %+v (сессия %+v), ожидалось %+v", got, got.Session, want)
+	}
+	if list := s.List(); len(list) != 2 || list[0].Name != "officer" || list[1].Name != "owner" {
+		t.Errorf("список профилей: %+v", list)
+	}
+
+	// пустые токены удаляют сессию, удаление текущего профиля сбрасывает выбор
+	s.SaveTokens("officer", "", "")
+	s.Delete("owner")
+	s, _ = Open(path)
+	if got, _ := s.Get("officer"); got.Session != nil {
+		t.Errorf("сессия после удаления токенов: %+v", got.Session)
+	}
+	if _, ok := s.Get("owner"); ok || s.Current() != DefaultName {
+		t.Errorf("удаленный профиль: найден %v, текущий %q", ok, s.Current())
+	}
+}
+
+func TestStorePermissions(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "lesta-battleship")
+	path := filepath.Join(dir, "profiles.json")
+	s, _ := Open(path)
+	if err := s.SaveTokens(DefaultName, "access", "refresh"); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]os.FileMode{path: 0o600, dir: 0o700} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != want {
+			t.Errorf("права %s: %o, ожидалось %o", name, perm, want)
+		}
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("временный файл остался: %v", err)
+	}
+}
+
+func TestOpenCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	os.WriteFile(path, []byte("{"), 0o600)
+	if _, err := Open(path); err == nil {
+		t.Error("поврежденный файл открыт без ошибки")
+	}
+}
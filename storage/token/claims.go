@@ -0,0 +1,78 @@
+package token
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims - данные из access token, прочитанные без проверки подписи.
+// Используются только для отображения и планирования обновления токена.
+type Claims struct {
+	Subject   string
+	UserID    int // sub, если он является числом
+	Roles     []string
+	ExpiresAt time.Time // нулевое значение, если exp не задан
+}
+
+// ParseClaims - разбор claims JWT без проверки подписи
+func ParseClaims(access string) (*Claims, error) {
+	access = strings.TrimSpace(strings.TrimPrefix(access, "Bearer "))
+	if access == "" {
+		return nil, fmt.Errorf("токен не задан")
+	}
+
+	mapClaims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(access, mapClaims); err != nil {
+		return nil, fmt.Errorf("ошибка разбора токена: %w", err)
+	}
+
+	claims := &Claims{}
+
+	switch sub := mapClaims["sub"].(type) {
+	case string:
+		claims.Subject = sub
+	case float64:
+		claims.Subject = strconv.FormatInt(int64(sub), 10)
+	}
+	if id, err := strconv.Atoi(claims.Subject); err == nil {
+		claims.UserID = id
+	}
+
+	exp, err := mapClaims.GetExpirationTime()
+	if err != nil {
+		return nil, fmt.Errorf("некорректный exp: %w", err)
+	}
+	if exp != nil {
+		claims.ExpiresAt = exp.Time
+	}
+
+	// роли встречаются как списком в roles, так и строкой в role
+	switch roles := mapClaims["roles"].(type) {
+	case []any:
+		for _, r := range roles {
+			if s, ok := r.(string); ok {
+				claims.Roles = append(claims.Roles, s)
+			}
+		}
+	case string:
+		claims.Roles = strings.Fields(roles)
+	}
+	if role, ok := mapClaims["role"].(string); ok && role != "" {
+		claims.Roles = append(claims.Roles, role)
+	}
+
+	return claims, nil
+}
+
+// ExpiresWithin - истекает ли токен в течение d.
+// Токен без exp считается бессрочным.
+func (c *Claims) ExpiresWithin(d time.Duration, now time.Time) bool {
+	if c.ExpiresAt.IsZero() {
+		return false
+	}
+	return c.ExpiresAt.Sub(now) <= d
+}
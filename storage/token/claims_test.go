@@ -0,0 +1,86 @@
+package token
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// sign - JWT с claims, подпись не проверяется при разборе
+func sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	access, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return access
+}
+
+func TestParseClaims(t *testing.T) {
+	exp := time.Unix(1893456000, 0)
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   Claims
+	}{
+		{
+			name:   "строковый sub и список ролей",
+			claims: jwt.MapClaims{"sub": "42", "exp": exp.Unix(), "roles": []string{"user", "admin"}},
+			want:   Claims{Subject: "42", UserID: 42, Roles: []string{"user", "admin"}, ExpiresAt: exp},
+		},
+		{
+			name:   "числовой sub и роли строкой",
+			claims: jwt.MapClaims{"sub": 7, "roles": "user moderator", "role": "owner"},
+			want:   Claims{Subject: "7", UserID: 7, Roles: []string{"user", "moderator", "owner"}},
+		},
+		{
+			name:   "sub не число",
+			claims: jwt.MapClaims{"sub": "admiral"},
+			want:   Claims{Subject: "admiral"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseClaims("Bearer " + sign(t, tt.claims))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.ExpiresAt.Equal(tt.want.ExpiresAt) {
+				t.Errorf("exp: %v, ожидалось %v", got.ExpiresAt, tt.want.ExpiresAt)
+			}
+			got.ExpiresAt = tt.want.ExpiresAt
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("claims: %+v, ожидалось %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClaimsErrors(t *testing.T) {
+	for name, access := range map[string]string{
+		"пустой токен":  "",
+		"только Bearer": "Bearer ",
+		"не JWT":        "opaque-token",
+		"exp строкой":   sign(t, jwt.MapClaims{"sub": "1", "exp": "завтра"}),
+	} {
+		if claims, err := ParseClaims(access); err == nil {
+			t.Errorf("%s: ошибки нет, claims %+v", name, claims)
+		}
+	}
+}
+
+func TestClaimsExpiresWithin(t *testing.T) {
+	now := time.Now()
+	claims := Claims{ExpiresAt: now.Add(time.Minute)}
+	if !claims.ExpiresWithin(time.Minute, now) {
+		t.Error("токен, истекающий через минуту, не истекает в течение минуты")
+	}
+	if claims.ExpiresWithin(30*time.Second, now) {
+		t.Error("токен, истекающий через минуту, истекает в течение 30 секунд")
+	}
+	if (&Claims{}).ExpiresWithin(time.Hour, now) {
+		t.Error("токен без exp считается истекающим")
+	}
+}
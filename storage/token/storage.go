@@ -5,6 +5,8 @@ import "sync"
 type Storage struct {
 	accessToken  string
 	refreshToken string
+	claims       *Claims // claims текущего access token, nil если токен не JWT
+	onChange     func(access, refresh string)
 	mu           sync.RWMutex
 }
 
@@ -12,11 +14,27 @@ func NewStorage() *Storage {
 	return &Storage{}
 }
 
-func (s *Storage) SetTokens(access, refresh string) {
+// OnChange - подписка на изменение токенов, например для сохранения сессии профиля
+func (s *Storage) OnChange(fn func(access, refresh string)) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.onChange = fn
+}
+
+func (s *Storage) SetTokens(access, refresh string) {
+	s.mu.Lock()
+	changed := s.accessToken != access || s.refreshToken != refresh
+	if s.accessToken != access {
+		s.claims, _ = ParseClaims(access)
+	}
 	s.accessToken = access
 	s.refreshToken = refresh
+	onChange := s.onChange
+	s.mu.Unlock()
+
+	if changed && onChange != nil {
+		onChange(access, refresh)
+	}
 }
 
 func (s *Storage) GetToken() (string, string) {
@@ -25,9 +43,17 @@ func (s *Storage) GetToken() (string, string) {
 	return s.accessToken, s.refreshToken
 }
 
+// Claims - claims текущего access token.
+// Возвращает false, если токен не задан или не является JWT.
+func (s *Storage) Claims() (Claims, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.claims == nil {
+		return Claims{}, false
+	}
+	return *s.claims, true
+}
+
 func (s *Storage) Clear() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.accessToken = ""
-	s.refreshToken = ""
+	s.SetTokens("", "")
 }